@@ -0,0 +1,152 @@
+package asset
+
+import (
+	"bwastartup/api/storage"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultURLTTL = time.Hour
+
+// Builder membuat URL publik untuk file di storage (avatar dan gambar campaign)
+type Builder interface {
+	URL(key string) string
+	Verify(key string, expires string, signature string) bool
+	Signed() bool
+}
+
+type Config struct {
+	// BaseURL origin publik file, misalnya "https://cdn.bwastartup.com".
+	// Kosong berarti memakai URL dari storage
+	BaseURL string
+	// AppBaseURL origin aplikasi, dipakai kalau URL dari storage masih berupa path relatif
+	AppBaseURL string
+	// SigningKey jika diisi semua URL diberi signature dan masa berlaku (untuk file private)
+	SigningKey string
+	URLTTL     time.Duration
+}
+
+// NewConfigFromEnv membaca ASSET_BASE_URL, APP_BASE_URL, ASSET_SIGNING_KEY dan ASSET_URL_TTL (dalam detik)
+func NewConfigFromEnv() Config {
+	config := Config{}
+	config.BaseURL = os.Getenv("ASSET_BASE_URL")
+	config.AppBaseURL = os.Getenv("APP_BASE_URL")
+	if config.AppBaseURL == "" {
+		config.AppBaseURL = "http://localhost:8080"
+	}
+	config.SigningKey = os.Getenv("ASSET_SIGNING_KEY")
+	config.URLTTL = DefaultURLTTL
+
+	ttl, err := strconv.Atoi(os.Getenv("ASSET_URL_TTL"))
+	if err == nil && ttl > 0 {
+		config.URLTTL = time.Duration(ttl) * time.Second
+	}
+	return config
+}
+
+type builder struct {
+	config  Config
+	storage storage.Storage
+}
+
+func NewBuilder(config Config, storage storage.Storage) *builder {
+	if config.URLTTL <= 0 {
+		config.URLTTL = DefaultURLTTL
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	config.AppBaseURL = strings.TrimRight(config.AppBaseURL, "/")
+	return &builder{config, storage}
+}
+
+func (b *builder) URL(key string) string {
+	if key == "" {
+		return ""
+	}
+	// data lama yang sudah berupa URL lengkap tidak diubah
+	if strings.HasPrefix(key, "http://") || strings.HasPrefix(key, "https://") {
+		return key
+	}
+
+	key = strings.TrimLeft(key, "/")
+
+	var fileURL string
+	if b.config.BaseURL != "" {
+		fileURL = b.config.BaseURL + "/" + key
+	} else {
+		fileURL = b.storage.URL(key)
+	}
+	// storage lokal tanpa STORAGE_BASE_URL menghasilkan path relatif, dilengkapi origin aplikasi
+	// supaya client (aplikasi mobile) selalu mendapat URL lengkap
+	if strings.HasPrefix(fileURL, "/") {
+		fileURL = b.config.AppBaseURL + fileURL
+	}
+
+	if !b.Signed() {
+		return fileURL
+	}
+
+	// masa berlaku dibulatkan supaya URL yang sama bisa di-cache selama satu periode TTL
+	ttl := int64(b.config.URLTTL / time.Second)
+	expires := (time.Now().Unix()/ttl + 2) * ttl
+	expiresParam := strconv.FormatInt(expires, 10)
+
+	query := url.Values{}
+	query.Set("expires", expiresParam)
+	query.Set("signature", b.sign(key, expiresParam))
+	return fileURL + "?" + query.Encode()
+}
+
+// Verify memastikan signature cocok dengan key dan belum kedaluwarsa
+func (b *builder) Verify(key string, expires string, signature string) bool {
+	if !b.Signed() {
+		return true
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return false
+	}
+
+	expected := b.sign(strings.TrimLeft(key, "/"), expires)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+func (b *builder) Signed() bool {
+	return b.config.SigningKey != ""
+}
+
+func (b *builder) sign(key string, expires string) string {
+	mac := hmac.New(sha256.New, []byte(b.config.SigningKey))
+	mac.Write([]byte(key + ":" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// defaultBuilder dipakai oleh formatter, diatur sekali di main lewat SetDefault.
+// Sebelum diatur, URL yang dihasilkan sama dengan key (path relatif seperti sebelumnya)
+var defaultBuilder Builder
+
+func SetDefault(builder Builder) {
+	defaultBuilder = builder
+}
+
+func URL(key string) string {
+	if defaultBuilder == nil {
+		return key
+	}
+	return defaultBuilder.URL(key)
+}
+
+// URLs mengubah map key file (misalnya hasil upload.ImageURLs) menjadi map URL
+func URLs(keys map[string]string) map[string]string {
+	urls := map[string]string{}
+	for name, key := range keys {
+		urls[name] = URL(key)
+	}
+	return urls
+}
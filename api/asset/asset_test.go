@@ -0,0 +1,52 @@
+package asset
+
+import (
+	"bwastartup/api/storage"
+	"net/url"
+	"testing"
+)
+
+func TestURL(t *testing.T) {
+	cases := []struct {
+		name        string
+		config      Config
+		storageBase string
+		key         string
+		want        string
+	}{
+		{"relative storage URL uses app base URL", Config{AppBaseURL: "https://api.bwastartup.test/"}, "", "images/avatars/1/a.jpg", "https://api.bwastartup.test/images/avatars/1/a.jpg"},
+		{"storage base URL", Config{AppBaseURL: "https://api.bwastartup.test"}, "https://files.bwastartup.test", "/images/avatars/1/a.jpg", "https://files.bwastartup.test/images/avatars/1/a.jpg"},
+		{"asset base URL", Config{BaseURL: "https://cdn.bwastartup.test/", AppBaseURL: "https://api.bwastartup.test"}, "", "images/avatars/1/a.jpg", "https://cdn.bwastartup.test/images/avatars/1/a.jpg"},
+		{"full URL is kept", Config{AppBaseURL: "https://api.bwastartup.test"}, "", "https://example.com/a.jpg", "https://example.com/a.jpg"},
+		{"empty key", Config{AppBaseURL: "https://api.bwastartup.test"}, "", "", ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			builder := NewBuilder(c.config, storage.NewMemoryStorage(c.storageBase))
+			if got := builder.URL(c.key); got != c.want {
+				t.Errorf("URL(%q) = %q, want %q", c.key, got, c.want)
+			}
+		})
+	}
+}
+
+func TestSignedURL(t *testing.T) {
+	builder := NewBuilder(Config{AppBaseURL: "https://api.bwastartup.test", SigningKey: "secret"}, storage.NewMemoryStorage(""))
+
+	signedURL, err := url.Parse(builder.URL("images/avatars/1/a.jpg"))
+	if err != nil {
+		t.Fatalf("parse URL: %v", err)
+	}
+	if signedURL.Scheme != "https" || signedURL.Host != "api.bwastartup.test" || signedURL.Path != "/images/avatars/1/a.jpg" {
+		t.Errorf("URL = %q, want absolute URL on the app base URL", signedURL)
+	}
+
+	query := signedURL.Query()
+	if !builder.Verify("images/avatars/1/a.jpg", query.Get("expires"), query.Get("signature")) {
+		t.Error("Verify rejected a URL from the same builder")
+	}
+	if builder.Verify("images/avatars/2/a.jpg", query.Get("expires"), query.Get("signature")) {
+		t.Error("Verify accepted the signature for another key")
+	}
+}
@@ -1,10 +1,11 @@
 package campaign
 
 import (
+	"bwastartup/api/category"
 	"bwastartup/api/user"
+	"bwastartup/api/money"
+	"strings"
 	"time"
-
-	"github.com/leekchan/accounting"
 )
 
 type Campaign struct {
@@ -15,13 +16,30 @@ type Campaign struct {
 	Description      string
 	Perks            string
 	BackerCount      int
+	// GoalAmount dan CurrentAmount dalam minor unit mata uang campaign
 	GoalAmount       int
 	CurrentAmount    int
+	Currency         string
 	Slug             string
+	CategoryID       int
+	CommentsBackersOnly bool
+	// SupporterWallEnabled daftar pendukung campaign bisa dilihat publik (sesuai pilihan tampilan tiap backer)
+	SupporterWallEnabled bool
+	// FeeBasisPoints dan FeeFixed override fee platform untuk campaign ini, nil berarti ikut kategori/default
+	FeeBasisPoints   *int
+	FeeFixed         *int
+	// PledgeMinimumAmount, PledgeMaximumAmount, PledgeIncrement dan OverfundingCapPercent override aturan dukungan,
+	// nil berarti ikut aturan platform (lihat PledgeRules)
+	PledgeMinimumAmount   *int
+	PledgeMaximumAmount   *int
+	PledgeIncrement       *int
+	OverfundingCapPercent *int
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
 	CampaignImages   []CampaignImage
 	User 						 user.User
+	Category         category.Category
+	Tags             []CampaignTag
 }
 
 type CampaignImage struct {
@@ -29,11 +47,43 @@ type CampaignImage struct {
 	CampaignID int
 	FileName   string
 	IsPrimary  int
+	Position   int
+	Caption    string
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
 }
 
-func (c Campaign) GoalAmountFormatIDR() string {
-	ac := accounting.Accounting{Symbol: "Rp.", Precision: 2, Thousand: ".", Decimal: ","}
-	return ac.FormatMoney(c.GoalAmount)
-}
\ No newline at end of file
+type CampaignTag struct {
+	ID         int
+	CampaignID int
+	Name       string
+	CreatedAt  time.Time
+}
+
+// CampaignSlug menyimpan slug lama campaign agar url lama bisa di-redirect ke slug terbaru
+type CampaignSlug struct {
+	ID         int
+	CampaignID int
+	Slug       string
+	CreatedAt  time.Time
+}
+
+func (c Campaign) GoalAmountFormatted() string {
+	return money.New(c.GoalAmount, c.Currency).Format()
+}
+
+func (c Campaign) CurrentAmountFormatted() string {
+	return money.New(c.CurrentAmount, c.Currency).Format()
+}
+
+func (c Campaign) TagNames() []string {
+	tags := []string{}
+	for _, tag := range c.Tags {
+		tags = append(tags, tag.Name)
+	}
+	return tags
+}
+
+func (c Campaign) TagsString() string {
+	return strings.Join(c.TagNames(), ", ")
+}
@@ -1,6 +1,11 @@
 package campaign
 
-import "strings"
+import (
+	"bwastartup/api/asset"
+	"bwastartup/api/money"
+	"bwastartup/api/upload"
+	"strings"
+)
 
 type CampaignFormatter struct {
 	ID               int    `json:"id"`
@@ -8,9 +13,13 @@ type CampaignFormatter struct {
 	Name             string `json:"name"`
 	ShortDescription string `json:"short_description"`
 	ImageURL         string `json:"image_url"`
+	ImageURLs        map[string]string `json:"image_urls"`
 	GoalAmount       int    `json:"goal_amount"`
 	CurrentAmount    int    `json:"current_amount"`
+	Currency         string `json:"currency"`
 	Slug             string `json:"slug"`
+	CategoryID       int    `json:"category_id"`
+	Tags             []string `json:"tags"`
 }
 
 func FormatCampaign(campaign Campaign) CampaignFormatter {
@@ -21,12 +30,17 @@ func FormatCampaign(campaign Campaign) CampaignFormatter {
 	campaignFormatter.ShortDescription = campaign.ShortDescription
 	campaignFormatter.GoalAmount = campaign.GoalAmount
 	campaignFormatter.CurrentAmount = campaign.CurrentAmount
+	campaignFormatter.Currency = money.NormalizeCurrency(campaign.Currency)
 	campaignFormatter.Slug = campaign.Slug
-	campaignFormatter.ImageURL = ""
+	campaignFormatter.CategoryID = campaign.CategoryID
+	campaignFormatter.Tags = campaign.TagNames()
+	imageKey := ""
 
 	if len(campaign.CampaignImages) > 0 {
-		campaignFormatter.ImageURL = campaign.CampaignImages[0].FileName
+		imageKey = campaign.CampaignImages[0].FileName
 	}
+	campaignFormatter.ImageURL = asset.URL(imageKey)
+	campaignFormatter.ImageURLs = asset.URLs(upload.ImageURLs(imageKey))
 	return campaignFormatter
 }
 
@@ -47,14 +61,32 @@ type CampaignDetailFormatter struct {
 	ShortDescription string   `json:"short_description"`
 	Description      string   `json:"description"`
 	ImageURL         string   `json:"image_url"`
+	ImageURLs        map[string]string `json:"image_urls"`
 	GoalAmount       int      `json:"goal_amount"`
 	CurrentAmount    int      `json:"current_amount"`
+	Currency         string   `json:"currency"`
 	BackerCount    	 int      `json:"backer_count"`
+	CommentsBackersOnly bool  `json:"comments_backers_only"`
+	SupporterWallEnabled bool `json:"supporter_wall_enabled"`
 	UserID           int      `json:"user_id"`
 	Slug             string   `json:"slug"`
 	Perks            []string `json:"perks"`
+	Tags             []string `json:"tags"`
+	Category         *CampaignCategoryFormatter `json:"category"`
 	User             CampaignUserFormatter `json:"user"`
 	Images           []CampaignImageFormatter `json:"images"`
+	PledgeRules      CampaignPledgeRulesFormatter `json:"pledge_rules"`
+}
+
+// CampaignPledgeRulesFormatter aturan dukungan yang berlaku, 0 berarti tanpa batas
+type CampaignPledgeRulesFormatter struct {
+	MinimumAmount         int `json:"minimum_amount"`
+	MaximumAmount         int `json:"maximum_amount"`
+	Increment             int `json:"increment"`
+	OverfundingCapPercent int `json:"overfunding_cap_percent"`
+	// FundingLimit total dana maksimal campaign dan RemainingAmount sisa yang masih bisa didukung
+	FundingLimit          int `json:"funding_limit"`
+	RemainingAmount       int `json:"remaining_amount"`
 }
 
 type CampaignUserFormatter struct{
@@ -62,9 +94,20 @@ type CampaignUserFormatter struct{
 	ImageURL string `json:"image_url"`
 }
 
+type CampaignCategoryFormatter struct{
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+	Icon string `json:"icon"`
+}
+
 type CampaignImageFormatter struct{
+	ID        int    `json:"id"`
 	ImageURL string `json:"image_url"`
+	ImageURLs map[string]string `json:"image_urls"`
 	IsPrimary bool `json:"is_primary"`
+	Position  int    `json:"position"`
+	Caption   string `json:"caption"`
 }
 
 func FormatCampaignDetail(campaign Campaign) CampaignDetailFormatter {
@@ -75,14 +118,26 @@ func FormatCampaignDetail(campaign Campaign) CampaignDetailFormatter {
 	campaignDetailFormatter.Description = campaign.Description
 	campaignDetailFormatter.GoalAmount = campaign.GoalAmount
 	campaignDetailFormatter.CurrentAmount = campaign.CurrentAmount
+	campaignDetailFormatter.Currency = money.NormalizeCurrency(campaign.Currency)
 	campaignDetailFormatter.BackerCount = campaign.BackerCount
+	campaignDetailFormatter.CommentsBackersOnly = campaign.CommentsBackersOnly
+	campaignDetailFormatter.SupporterWallEnabled = campaign.SupporterWallEnabled
 	campaignDetailFormatter.Slug = campaign.Slug
 	campaignDetailFormatter.UserID = campaign.UserID
-	campaignDetailFormatter.ImageURL = ""
+	imageKey := ""
 
 	if len(campaign.CampaignImages) > 0 {
-		campaignDetailFormatter.ImageURL = campaign.CampaignImages[0].FileName
+		imageKey = campaign.CampaignImages[0].FileName
+	}
+
+	for _, image := range campaign.CampaignImages {
+		if image.IsPrimary == 1 {
+			imageKey = image.FileName
+			break
+		}
 	}
+	campaignDetailFormatter.ImageURL = asset.URL(imageKey)
+	campaignDetailFormatter.ImageURLs = asset.URLs(upload.ImageURLs(imageKey))
 
 	var perks []string
 
@@ -91,31 +146,74 @@ func FormatCampaignDetail(campaign Campaign) CampaignDetailFormatter {
 	}
 
 	campaignDetailFormatter.Perks = perks
+	campaignDetailFormatter.Tags = campaign.TagNames()
+
+	if campaign.Category.ID != 0 {
+		campaignDetailFormatter.Category = &CampaignCategoryFormatter{
+			ID:   campaign.Category.ID,
+			Name: campaign.Category.Name,
+			Slug: campaign.Category.Slug,
+			Icon: campaign.Category.Icon,
+		}
+	}
 
 	user := campaign.User
 	campaignUserFormatter := CampaignUserFormatter{}
 	campaignUserFormatter.Name = user.Name
-	campaignUserFormatter.ImageURL = user.AvatarFileName
+	campaignUserFormatter.ImageURL = asset.URL(user.AvatarFileName)
 
 	campaignDetailFormatter.User = campaignUserFormatter
 
 	images := []CampaignImageFormatter{}
 
 	for _, image := range campaign.CampaignImages{
-		campaignImageFormatter := CampaignImageFormatter{}
-		campaignImageFormatter.ImageURL = image.FileName
+		images = append(images, FormatCampaignImage(image))
+	}
 
-		isPrimary := false
-		if image.IsPrimary == 1 {
-			isPrimary = true
-		}
+	campaignDetailFormatter.Images = images
+	campaignDetailFormatter.PledgeRules = FormatCampaignPledgeRules(campaign)
 
-		campaignImageFormatter.IsPrimary = isPrimary
+	return campaignDetailFormatter
+}
 
-		images = append(images, campaignImageFormatter)
+func FormatCampaignPledgeRules(campaign Campaign) CampaignPledgeRulesFormatter {
+	rules := campaign.PledgeRules()
+
+	formatter := CampaignPledgeRulesFormatter{}
+	formatter.MinimumAmount = rules.MinimumAmount
+	formatter.MaximumAmount = rules.MaximumAmount
+	formatter.Increment = rules.Increment
+	formatter.OverfundingCapPercent = rules.OverfundingCapPercent
+	formatter.FundingLimit = campaign.FundingLimit()
+	if formatter.FundingLimit > 0 && formatter.FundingLimit > campaign.CurrentAmount {
+		formatter.RemainingAmount = formatter.FundingLimit - campaign.CurrentAmount
 	}
+	return formatter
+}
 
-	campaignDetailFormatter.Images = images
+func FormatCampaignImage(image CampaignImage) CampaignImageFormatter {
+	campaignImageFormatter := CampaignImageFormatter{}
+	campaignImageFormatter.ID = image.ID
+	campaignImageFormatter.ImageURL = asset.URL(image.FileName)
+	campaignImageFormatter.ImageURLs = asset.URLs(upload.ImageURLs(image.FileName))
 
-	return campaignDetailFormatter
+	isPrimary := false
+	if image.IsPrimary == 1 {
+		isPrimary = true
+	}
+
+	campaignImageFormatter.IsPrimary = isPrimary
+	campaignImageFormatter.Position = image.Position
+	campaignImageFormatter.Caption = image.Caption
+
+	return campaignImageFormatter
+}
+
+func FormatCampaignImages(images []CampaignImage) []CampaignImageFormatter {
+	imagesFormatter := []CampaignImageFormatter{}
+
+	for _, image := range images {
+		imagesFormatter = append(imagesFormatter, FormatCampaignImage(image))
+	}
+	return imagesFormatter
 }
\ No newline at end of file
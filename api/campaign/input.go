@@ -1,34 +1,84 @@
 package campaign
 
-import "bwastartup/api/user"
+import (
+	"bwastartup/api/category"
+	"bwastartup/api/user"
+)
 
 type GetCampaignDetailInput struct {
 	ID int `uri:"id" binding:"required"`
 }
 
+type GetCampaignSlugInput struct {
+	Slug string `uri:"slug" binding:"required"`
+}
+
 type CreateCampaignInput struct {
 	Name             string `json:"name" binding:"required"`
 	ShortDescription string `json:"short_description" binding:"required"`
 	Description      string `json:"description" binding:"required"`
 	GoalAmount       int    `json:"goal_amount" binding:"required"`
+	// Currency kode mata uang (IDR, USD, ...), kosong berarti IDR saat create dan tidak berubah saat update
+	Currency         string `json:"currency"`
 	Perks            string `json:"perks" binding:"required"`
+	// CategoryID dan Tags nil berarti tidak diubah saat update (saat create: tanpa kategori/tag)
+	CategoryID       *int    `json:"category_id"`
+	Tags             *string `json:"tags"`
+	// CommentsBackersOnly nil berarti tidak diubah saat update (saat create: semua user boleh berkomentar)
+	CommentsBackersOnly *bool `json:"comments_backers_only"`
+	// SupporterWallEnabled nil berarti tidak diubah saat update (saat create: supporter wall tidak aktif)
+	SupporterWallEnabled *bool `json:"supporter_wall_enabled"`
+	// PledgeRules nil berarti aturan dukungan tidak diubah (saat create: ikut aturan platform)
+	PledgeRules      *PledgeRulesInput `json:"pledge_rules"`
 	User             user.User
 }
 
+// PledgeRulesInput override aturan dukungan campaign, field nil berarti ikut aturan platform
+type PledgeRulesInput struct {
+	MinimumAmount         *int `json:"minimum_amount"`
+	MaximumAmount         *int `json:"maximum_amount"`
+	Increment             *int `json:"increment"`
+	OverfundingCapPercent *int `json:"overfunding_cap_percent"`
+}
+
 type CreateCampaignImageInput struct{
 	CampaignID int  `form:"campaign_id" binding:"required"`
 	IsPrimary  bool `form:"is_primary"`
 	User             user.User
 }
 
+type GetCampaignImageInput struct {
+	ID   int `uri:"id" binding:"required"`
+	User user.User
+}
+
+// UpdateCampaignImageInput field yang tidak dikirim (nil) tidak diubah
+type UpdateCampaignImageInput struct {
+	IsPrimary *bool   `json:"is_primary"`
+	Caption   *string `json:"caption"`
+	User      user.User
+}
+
+type ReorderCampaignImagesInput struct {
+	ImageIDs []int `json:"image_ids" binding:"required"`
+	User     user.User
+}
+
 type FormCreateCampaignInput struct {
 	Name             string `form:"name" binding:"required"`
 	ShortDescription string `form:"short_description" binding:"required"`
 	Description      string `form:"description" binding:"required"`
 	GoalAmount       int    `form:"goal_amount" binding:"required"`
+	Currency         string `form:"currency"`
 	Perks            string `form:"perks" binding:"required"`
 	UserID					 int		`form:"user_id" binding:"required"`
+	CategoryID       int    `form:"category_id"`
+	Tags             string `form:"tags"`
+	CommentsBackersOnly bool `form:"comments_backers_only"`
+	SupporterWallEnabled bool `form:"supporter_wall_enabled"`
 	Users						 []user.User
+	Categories       []category.Category
+	Currencies       []string
 	Error						 error
 }
 type FormUpdateCampaignInput struct {
@@ -37,7 +87,22 @@ type FormUpdateCampaignInput struct {
 	ShortDescription string `form:"short_description" binding:"required"`
 	Description      string `form:"description" binding:"required"`
 	GoalAmount       int    `form:"goal_amount" binding:"required"`
+	Currency         string `form:"currency"`
 	Perks            string `form:"perks" binding:"required"`
+	CategoryID       int    `form:"category_id"`
+	Tags             string `form:"tags"`
+	CommentsBackersOnly bool `form:"comments_backers_only"`
+	SupporterWallEnabled bool `form:"supporter_wall_enabled"`
+	// FeePercent dan FeeFixed override fee platform, kosong berarti ikut kategori/default
+	FeePercent       string `form:"fee_percent"`
+	FeeFixed         string `form:"fee_fixed"`
+	// isian aturan dukungan, kosong berarti ikut aturan platform
+	PledgeMinimumAmount   string `form:"pledge_minimum_amount"`
+	PledgeMaximumAmount   string `form:"pledge_maximum_amount"`
+	PledgeIncrement       string `form:"pledge_increment"`
+	OverfundingCapPercent string `form:"overfunding_cap_percent"`
+	Categories       []category.Category
+	Currencies       []string
 	Error						 error
 	User						 user.User
 }
\ No newline at end of file
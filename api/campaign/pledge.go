@@ -0,0 +1,128 @@
+package campaign
+
+import (
+	"bwastartup/api/money"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// PledgeRules batasan nominal dukungan untuk satu campaign, semua amount dalam minor unit mata uang campaign
+type PledgeRules struct {
+	MinimumAmount int
+	// MaximumAmount 0 berarti tanpa batas
+	MaximumAmount int
+	// Increment kelipatan nominal dukungan, 0 berarti bebas
+	Increment int
+	// OverfundingCapPercent batas total dana terhadap GoalAmount (150 berarti maksimal 150% goal), 0 berarti tanpa batas
+	OverfundingCapPercent int
+}
+
+// defaultPledgeRules aturan platform, nominal dalam rupiah sehingga hanya dipakai campaign IDR
+var defaultPledgeRules = PledgeRules{}
+
+// NewPledgeRulesFromEnv membaca PLEDGE_MIN_AMOUNT, PLEDGE_MAX_AMOUNT, PLEDGE_INCREMENT (dalam rupiah)
+// dan PLEDGE_OVERFUNDING_CAP_PERCENT. Minimum kosong berarti minimum mata uang (Rp10.000)
+func NewPledgeRulesFromEnv() PledgeRules {
+	rules := PledgeRules{}
+	rules.MinimumAmount = getEnvInt("PLEDGE_MIN_AMOUNT")
+	rules.MaximumAmount = getEnvInt("PLEDGE_MAX_AMOUNT")
+	rules.Increment = getEnvInt("PLEDGE_INCREMENT")
+	rules.OverfundingCapPercent = getEnvInt("PLEDGE_OVERFUNDING_CAP_PERCENT")
+	return rules
+}
+
+func getEnvInt(key string) int {
+	value, err := strconv.Atoi(strings.TrimSpace(os.Getenv(key)))
+	if err != nil || value < 0 {
+		return 0
+	}
+	return value
+}
+
+// SetDefaultPledgeRules dipanggil sekali saat aplikasi start
+func SetDefaultPledgeRules(rules PledgeRules) {
+	defaultPledgeRules = rules
+}
+
+// PledgeRules aturan yang berlaku untuk campaign: override campaign, lalu aturan platform, lalu minimum mata uang
+func (c Campaign) PledgeRules() PledgeRules {
+	currency := money.LookupCurrency(c.Currency)
+
+	rules := PledgeRules{}
+	rules.OverfundingCapPercent = defaultPledgeRules.OverfundingCapPercent
+	if currency.Code == money.DefaultCurrency {
+		rules.MinimumAmount = defaultPledgeRules.MinimumAmount
+		rules.MaximumAmount = defaultPledgeRules.MaximumAmount
+		rules.Increment = defaultPledgeRules.Increment
+	}
+
+	if c.PledgeMinimumAmount != nil {
+		rules.MinimumAmount = *c.PledgeMinimumAmount
+	}
+	if c.PledgeMaximumAmount != nil {
+		rules.MaximumAmount = *c.PledgeMaximumAmount
+	}
+	if c.PledgeIncrement != nil {
+		rules.Increment = *c.PledgeIncrement
+	}
+	if c.OverfundingCapPercent != nil {
+		rules.OverfundingCapPercent = *c.OverfundingCapPercent
+	}
+
+	// minimum mata uang tetap berlaku walaupun override lebih kecil
+	if rules.MinimumAmount < currency.MinimumPledge {
+		rules.MinimumAmount = currency.MinimumPledge
+	}
+	return rules
+}
+
+// FundingLimit total dana maksimal campaign, 0 berarti tanpa batas
+func (c Campaign) FundingLimit() int {
+	capPercent := c.PledgeRules().OverfundingCapPercent
+	if capPercent == 0 {
+		return 0
+	}
+	return int(int64(c.GoalAmount) * int64(capPercent) / 100)
+}
+
+// validatePledgeRules memeriksa override sebelum disimpan, nil berarti ikut aturan platform
+func validatePledgeRules(input PledgeRulesInput) error {
+	for _, value := range []*int{input.MinimumAmount, input.MaximumAmount, input.Increment, input.OverfundingCapPercent} {
+		if value != nil && *value < 0 {
+			return errors.New("Pledge rules must not be negative")
+		}
+	}
+
+	if input.MinimumAmount != nil && input.MaximumAmount != nil && *input.MaximumAmount > 0 && *input.MaximumAmount < *input.MinimumAmount {
+		return errors.New("Maximum pledge must be greater than minimum pledge")
+	}
+
+	if input.OverfundingCapPercent != nil && *input.OverfundingCapPercent > 0 && *input.OverfundingCapPercent < 100 {
+		return errors.New("Overfunding cap must be at least 100 percent of the goal")
+	}
+	return nil
+}
+
+// ParsePledgeRule isian form CMS, string kosong berarti ikut aturan platform (nil)
+func ParsePledgeRule(value string) (*int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	rule, err := strconv.Atoi(value)
+	if err != nil || rule < 0 {
+		return nil, errors.New("Pledge rule must be a positive number")
+	}
+	return &rule, nil
+}
+
+// FormatPledgeRule kebalikan ParsePledgeRule untuk ditampilkan di form
+func FormatPledgeRule(rule *int) string {
+	if rule == nil {
+		return ""
+	}
+	return strconv.Itoa(*rule)
+}
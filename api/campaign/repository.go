@@ -7,13 +7,27 @@ import (
 )
 
 type Repository interface {
-	FindAll(Order string, q string) ([]Campaign, error)
-	FindByUserID(userID int, Order string, q string) ([]Campaign, error)
+	FindAll(Order string, q string, tag string) ([]Campaign, error)
+	FindByUserID(userID int, Order string, q string, tag string) ([]Campaign, error)
+	FindByCategoryID(categoryID int, Order string, q string, tag string) ([]Campaign, error)
 	FindByID(ID int) (Campaign, error)
+	FindBySlug(slug string) (Campaign, error)
+	FindSlugHistory(slug string) (CampaignSlug, error)
+	IsSlugTaken(slug string, campaignID int) (bool, error)
+	SaveSlugHistory(campaignSlug CampaignSlug) (CampaignSlug, error)
+	DeleteSlugHistory(campaignID int, slug string) (bool, error)
 	Save(campaign Campaign) (Campaign, error)
 	Update(campaign Campaign) (Campaign, error)
+	AddFunding(campaignID int, amount int, backerCount int) error
+	HasTransactions(campaignID int) (bool, error)
+	UpdateFee(campaignID int, feeBasisPoints *int, feeFixed *int) error
 	CreateImage(campaignImage CampaignImage) (CampaignImage, error)
 	MarkAllImagesAsNonPrimary(campaignID int) (bool, error)
+	FindImageByID(ID int) (CampaignImage, error)
+	UpdateImage(campaignImage CampaignImage) (CampaignImage, error)
+	DeleteImage(campaignImage CampaignImage) (bool, error)
+	UpdateImagePositions(campaignID int, imageIDs []int) (bool, error)
+	ReplaceTags(campaignID int, names []string) ([]CampaignTag, error)
 }
 
 type repository struct {
@@ -24,10 +38,23 @@ func NewRepository(db *gorm.DB) *repository{
 	return &repository{db}
 }
 
-func (r *repository) FindAll(Order string, q string) ([]Campaign, error){
+// orderImages mengurutkan gambar campaign sesuai urutan yang diatur pemilik campaign
+func orderImages(db *gorm.DB) *gorm.DB {
+	return db.Order("campaign_images.position asc").Order("campaign_images.id asc")
+}
+
+// filterByTag membatasi query hanya ke campaign yang memiliki tag tersebut
+func filterByTag(db *gorm.DB, tag string) *gorm.DB {
+	if tag == "" {
+		return db
+	}
+	return db.Where("id IN (SELECT campaign_id FROM campaign_tags WHERE name = ?)", normalizeTag(tag))
+}
+
+func (r *repository) FindAll(Order string, q string, tag string) ([]Campaign, error){
 	var campaigns []Campaign
 	var query = "%" + q + "%"
-	err := r.db.Order(fmt.Sprintf("id %s", Order)).Where("name LIKE ?",query).Preload("CampaignImages", "campaign_images.is_primary = 1").Find(&campaigns).Error
+	err := filterByTag(r.db.Order(fmt.Sprintf("id %s", Order)).Where("name LIKE ?",query), tag).Preload("CampaignImages", "campaign_images.is_primary = 1").Preload("Tags").Find(&campaigns).Error
 
 	if err != nil{
 		return campaigns, err
@@ -35,10 +62,21 @@ func (r *repository) FindAll(Order string, q string) ([]Campaign, error){
 	return campaigns, nil
 }
 
-func (r *repository) FindByUserID(userID int ,Order string, q string) ([]Campaign, error){
+func (r *repository) FindByUserID(userID int ,Order string, q string, tag string) ([]Campaign, error){
 	var campaigns []Campaign
 	var query = "%" + q + "%"
-	err := r.db.Order(fmt.Sprintf("id %s", Order)).Where("user_id = ? AND name LIKE ?", userID, query).Preload("CampaignImages", "campaign_images.is_primary = 1").Find(&campaigns).Error
+	err := filterByTag(r.db.Order(fmt.Sprintf("id %s", Order)).Where("user_id = ? AND name LIKE ?", userID, query), tag).Preload("CampaignImages", "campaign_images.is_primary = 1").Preload("Tags").Find(&campaigns).Error
+
+	if err != nil{
+		return campaigns, err
+	}
+	return campaigns, nil
+}
+
+func (r *repository) FindByCategoryID(categoryID int, Order string, q string, tag string) ([]Campaign, error){
+	var campaigns []Campaign
+	var query = "%" + q + "%"
+	err := filterByTag(r.db.Order(fmt.Sprintf("id %s", Order)).Where("category_id = ? AND name LIKE ?", categoryID, query), tag).Preload("CampaignImages", "campaign_images.is_primary = 1").Preload("Tags").Find(&campaigns).Error
 
 	if err != nil{
 		return campaigns, err
@@ -48,7 +86,7 @@ func (r *repository) FindByUserID(userID int ,Order string, q string) ([]Campaig
 
 func (r *repository) FindByID(ID int) (Campaign, error){
 	var campaign Campaign
-	err := r.db.Preload("User").Preload("CampaignImages").Where("id = ?", ID).Find(&campaign).Error
+	err := r.db.Preload("User").Preload("CampaignImages", orderImages).Preload("Category").Preload("Tags").Where("id = ?", ID).Find(&campaign).Error
 
 	if err != nil {
 		return campaign, err
@@ -57,6 +95,63 @@ func (r *repository) FindByID(ID int) (Campaign, error){
 	return campaign, nil
 }
 
+func (r *repository) FindBySlug(slug string) (Campaign, error){
+	var campaign Campaign
+	err := r.db.Preload("User").Preload("CampaignImages", orderImages).Preload("Category").Preload("Tags").Where("slug = ?", slug).Find(&campaign).Error
+
+	if err != nil {
+		return campaign, err
+	}
+
+	return campaign, nil
+}
+
+func (r *repository) FindSlugHistory(slug string) (CampaignSlug, error){
+	var campaignSlug CampaignSlug
+	err := r.db.Where("slug = ?", slug).Find(&campaignSlug).Error
+
+	if err != nil {
+		return campaignSlug, err
+	}
+
+	return campaignSlug, nil
+}
+
+// IsSlugTaken mengecek slug sudah dipakai campaign lain, termasuk slug lama campaign lain
+// supaya redirect dari slug lama tidak pernah mengarah ke campaign yang salah
+func (r *repository) IsSlugTaken(slug string, campaignID int) (bool, error){
+	var count int64
+	err := r.db.Model(&Campaign{}).Where("slug = ? AND id <> ?", slug, campaignID).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	err = r.db.Model(&CampaignSlug{}).Where("slug = ? AND campaign_id <> ?", slug, campaignID).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *repository) SaveSlugHistory(campaignSlug CampaignSlug) (CampaignSlug, error){
+	err := r.db.Create(&campaignSlug).Error
+	if err != nil {
+		return campaignSlug, err
+	}
+	return campaignSlug, nil
+}
+
+func (r *repository) DeleteSlugHistory(campaignID int, slug string) (bool, error){
+	err := r.db.Where("campaign_id = ? AND slug = ?", campaignID, slug).Delete(&CampaignSlug{}).Error
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 func (r *repository) Save(campaign Campaign) (Campaign, error){
 	err := r.db.Create(&campaign).Error
 	if err != nil {
@@ -65,8 +160,10 @@ func (r *repository) Save(campaign Campaign) (Campaign, error){
 	return campaign, nil
 }
 
+// Update menyimpan perubahan data campaign. current_amount dan backer_count tidak ikut disimpan
+// karena hanya boleh diubah lewat AddFunding, supaya edit campaign tidak menimpa dana yang masuk bersamaan
 func (r *repository) Update(campaign Campaign) (Campaign, error){
-	err := r.db.Save(&campaign).Error
+	err := r.db.Omit("current_amount", "backer_count").Save(&campaign).Error
 	if err != nil {
 		return campaign, err
 	}
@@ -90,4 +187,106 @@ func (r *repository) MarkAllImagesAsNonPrimary(campaignID int) (bool, error){
 		return false, err
 	}
 	return true, nil
-}
\ No newline at end of file
+}
+
+func (r *repository) ReplaceTags(campaignID int, names []string) ([]CampaignTag, error){
+	tags := []CampaignTag{}
+	for _, name := range names {
+		tags = append(tags, CampaignTag{CampaignID: campaignID, Name: name})
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("campaign_id = ?", campaignID).Delete(&CampaignTag{}).Error
+		if err != nil {
+			return err
+		}
+		if len(tags) == 0 {
+			return nil
+		}
+		return tx.Create(&tags).Error
+	})
+
+	if err != nil {
+		return tags, err
+	}
+	return tags, nil
+}
+
+func (r *repository) FindImageByID(ID int) (CampaignImage, error){
+	var campaignImage CampaignImage
+	err := r.db.Where("id = ?", ID).Find(&campaignImage).Error
+
+	if err != nil {
+		return campaignImage, err
+	}
+	return campaignImage, nil
+}
+
+func (r *repository) UpdateImage(campaignImage CampaignImage) (CampaignImage, error){
+	err := r.db.Save(&campaignImage).Error
+	if err != nil {
+		return campaignImage, err
+	}
+	return campaignImage, nil
+}
+
+func (r *repository) DeleteImage(campaignImage CampaignImage) (bool, error){
+	err := r.db.Delete(&campaignImage).Error
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// UpdateImagePositions menyimpan urutan gambar sesuai index pada imageIDs
+func (r *repository) UpdateImagePositions(campaignID int, imageIDs []int) (bool, error){
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for position, imageID := range imageIDs {
+			err := tx.Model(&CampaignImage{}).Where("id = ? AND campaign_id = ?", imageID, campaignID).Update("position", position).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// HasTransactions true jika campaign sudah punya transaksi (termasuk yang masih pending)
+func (r *repository) HasTransactions(campaignID int) (bool, error){
+	var count int64
+
+	err := r.db.Table("transactions").Where("campaign_id = ?", campaignID).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// AddFunding menambah (atau mengurangi jika negatif) dana dan jumlah backer langsung di database
+// tanpa membaca data campaign dulu, sehingga aman dipanggil bersamaan
+func (r *repository) AddFunding(campaignID int, amount int, backerCount int) error{
+	err := r.db.Model(&Campaign{}).Where("id = ?", campaignID).Updates(map[string]interface{}{
+		"current_amount": gorm.Expr("current_amount + ?", amount),
+		"backer_count":   gorm.Expr("backer_count + ?", backerCount),
+	}).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *repository) UpdateFee(campaignID int, feeBasisPoints *int, feeFixed *int) error{
+	err := r.db.Model(&Campaign{}).Where("id = ?", campaignID).Updates(map[string]interface{}{
+		"fee_basis_points": feeBasisPoints,
+		"fee_fixed":        feeFixed,
+	}).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
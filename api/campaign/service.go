@@ -1,39 +1,49 @@
 package campaign
 
 import (
+	"bwastartup/api/category"
+	"bwastartup/api/money"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/gosimple/slug"
 )
 
 type Service interface {
-	GetCampaigns(userID int, Order string, q string) ([]Campaign, error)
+	GetCampaigns(userID int, Order string, q string, tag string) ([]Campaign, error)
+	GetCampaignsByCategoryID(categoryID int, Order string, q string, tag string) ([]Campaign, error)
 	GetCampaignByID(input GetCampaignDetailInput) (Campaign, error)
+	GetCampaignBySlug(input GetCampaignSlugInput) (Campaign, error)
 	CreateCampaign(input CreateCampaignInput) (Campaign, error)
 	UpdateCampaign(inputID GetCampaignDetailInput, inputData CreateCampaignInput) (Campaign, error)
 	SaveCampaignImage(input CreateCampaignImageInput, fileLocation string) (CampaignImage, error)
+	UpdateCampaignImage(inputID GetCampaignImageInput, inputData UpdateCampaignImageInput) (CampaignImage, error)
+	DeleteCampaignImage(input GetCampaignImageInput) (CampaignImage, error)
+	ReorderCampaignImages(inputID GetCampaignDetailInput, inputData ReorderCampaignImagesInput) ([]CampaignImage, error)
+	UpdateCampaignFee(inputID GetCampaignDetailInput, feeBasisPoints *int, feeFixed *int) (Campaign, error)
 }
 
 type service struct {
-	repository Repository
+	repository         Repository
+	categoryRepository category.Repository
 }
 
-func NewService(repository Repository) *service {
-	return &service{repository}
+func NewService(repository Repository, categoryRepository category.Repository) *service {
+	return &service{repository, categoryRepository}
 }
 
-func (s *service) GetCampaigns(userID int, Order string, q string) ([]Campaign, error) {
+func (s *service) GetCampaigns(userID int, Order string, q string, tag string) ([]Campaign, error) {
 
 	if userID != 0 {
-		campaigns, err := s.repository.FindByUserID(userID, Order, q)
+		campaigns, err := s.repository.FindByUserID(userID, Order, q, tag)
 		if err != nil {
 			return campaigns, err
 		}
 		return campaigns, nil
 	}
 
-	campaigns, err := s.repository.FindAll(Order, q)
+	campaigns, err := s.repository.FindAll(Order, q, tag)
 	if err != nil {
 		return campaigns, err
 	}
@@ -41,6 +51,14 @@ func (s *service) GetCampaigns(userID int, Order string, q string) ([]Campaign,
 
 }
 
+func (s *service) GetCampaignsByCategoryID(categoryID int, Order string, q string, tag string) ([]Campaign, error) {
+	campaigns, err := s.repository.FindByCategoryID(categoryID, Order, q, tag)
+	if err != nil {
+		return campaigns, err
+	}
+	return campaigns, nil
+}
+
 func (s *service) GetCampaignByID(input GetCampaignDetailInput) (Campaign, error) {
 	campaign, err := s.repository.FindByID(input.ID)
 
@@ -50,6 +68,35 @@ func (s *service) GetCampaignByID(input GetCampaignDetailInput) (Campaign, error
 	return campaign, nil
 }
 
+// GetCampaignBySlug mencari campaign berdasarkan slug terbaru, kalau tidak ada dicari di riwayat slug.
+// Handler bisa membandingkan campaign.Slug dengan input.Slug untuk melakukan redirect
+func (s *service) GetCampaignBySlug(input GetCampaignSlugInput) (Campaign, error) {
+	campaign, err := s.repository.FindBySlug(input.Slug)
+	if err != nil {
+		return campaign, err
+	}
+	if campaign.ID != 0 {
+		return campaign, nil
+	}
+
+	campaignSlug, err := s.repository.FindSlugHistory(input.Slug)
+	if err != nil {
+		return campaign, err
+	}
+	if campaignSlug.ID == 0 {
+		return campaign, errors.New("No campaign found with that slug")
+	}
+
+	campaign, err = s.repository.FindByID(campaignSlug.CampaignID)
+	if err != nil {
+		return campaign, err
+	}
+	if campaign.ID == 0 {
+		return campaign, errors.New("No campaign found with that slug")
+	}
+	return campaign, nil
+}
+
 func (s *service) CreateCampaign(input CreateCampaignInput) (Campaign, error) {
 	campaign := Campaign{}
 
@@ -59,14 +106,50 @@ func (s *service) CreateCampaign(input CreateCampaignInput) (Campaign, error) {
 	campaign.Perks = input.Perks
 	campaign.GoalAmount = input.GoalAmount
 	campaign.UserID = input.User.ID
+	if input.CommentsBackersOnly != nil {
+		campaign.CommentsBackersOnly = *input.CommentsBackersOnly
+	}
+	if input.SupporterWallEnabled != nil {
+		campaign.SupporterWallEnabled = *input.SupporterWallEnabled
+	}
+
+	campaign.Currency = money.NormalizeCurrency(input.Currency)
+	if !money.IsSupported(campaign.Currency) {
+		return campaign, errors.New("Currency is not supported")
+	}
+
+	err := applyPledgeRules(&campaign, input.PledgeRules)
+	if err != nil {
+		return campaign, err
+	}
+
+	if input.CategoryID != nil {
+		err = s.checkCategory(*input.CategoryID)
+		if err != nil {
+			return campaign, err
+		}
+		campaign.CategoryID = *input.CategoryID
+	}
 
-	slugCandidate := fmt.Sprintf("%s %d", input.Name, input.User.ID)
-	campaign.Slug = slug.Make(slugCandidate)
+	campaignSlug, err := s.generateSlug(input.Name, input.User.ID, 0)
+	if err != nil {
+		return campaign, err
+	}
+	campaign.Slug = campaignSlug
 
 	newCampaign, err := s.repository.Save(campaign)
 	if err != nil {
-		return newCampaign, nil
+		return newCampaign, err
+	}
+
+	if input.Tags != nil {
+		tags, err := s.repository.ReplaceTags(newCampaign.ID, ParseTags(*input.Tags))
+		if err != nil {
+			return newCampaign, err
+		}
+		newCampaign.Tags = tags
 	}
+
 	return newCampaign, nil
 }
 
@@ -81,16 +164,77 @@ func (s *service) UpdateCampaign(inputID GetCampaignDetailInput, inputData Creat
 		return campaign, errors.New("Not an owner of the campaign")
 	}
 
+	// slug dibuat ulang saat nama campaign berubah, slug lama disimpan untuk redirect
+	oldSlug := ""
+	if campaign.Name != inputData.Name {
+		newSlug, err := s.generateSlug(inputData.Name, campaign.UserID, campaign.ID)
+		if err != nil {
+			return campaign, err
+		}
+		if newSlug != campaign.Slug {
+			oldSlug = campaign.Slug
+			campaign.Slug = newSlug
+		}
+	}
+
 	campaign.Name = inputData.Name
 	campaign.ShortDescription = inputData.ShortDescription
 	campaign.Description = inputData.Description
 	campaign.Perks = inputData.Perks
 	campaign.GoalAmount = inputData.GoalAmount
+	if inputData.CommentsBackersOnly != nil {
+		campaign.CommentsBackersOnly = *inputData.CommentsBackersOnly
+	}
+	if inputData.SupporterWallEnabled != nil {
+		campaign.SupporterWallEnabled = *inputData.SupporterWallEnabled
+	}
+
+	err = s.changeCurrency(&campaign, inputData.Currency)
+	if err != nil {
+		return campaign, err
+	}
+
+	err = applyPledgeRules(&campaign, inputData.PledgeRules)
+	if err != nil {
+		return campaign, err
+	}
+
+	if inputData.CategoryID != nil {
+		err = s.checkCategory(*inputData.CategoryID)
+		if err != nil {
+			return campaign, err
+		}
+		// kosongkan relasi category agar gorm tidak mengembalikan category_id ke nilai lama saat Save
+		campaign.CategoryID = *inputData.CategoryID
+		campaign.Category = category.Category{}
+	}
+
 	updatedCampaign,err := s.repository.Update(campaign)
 	
 	if err != nil{
 		return updatedCampaign, err
 	}
+
+	if oldSlug != "" {
+		_, err = s.repository.DeleteSlugHistory(updatedCampaign.ID, updatedCampaign.Slug)
+		if err != nil {
+			return updatedCampaign, err
+		}
+
+		_, err = s.repository.SaveSlugHistory(CampaignSlug{CampaignID: updatedCampaign.ID, Slug: oldSlug})
+		if err != nil {
+			return updatedCampaign, err
+		}
+	}
+
+	if inputData.Tags != nil {
+		tags, err := s.repository.ReplaceTags(updatedCampaign.ID, ParseTags(*inputData.Tags))
+		if err != nil {
+			return updatedCampaign, err
+		}
+		updatedCampaign.Tags = tags
+	}
+
 	return updatedCampaign, nil
 }
 
@@ -104,6 +248,14 @@ func (s *service) SaveCampaignImage(input CreateCampaignImageInput, fileLocation
 		return CampaignImage{}, errors.New("Not an owner of the campaign")
 	}
 
+	// nama file diambil dari hash isi file, jadi gambar yang sama tidak boleh dipakai dua kali
+	// agar menghapus salah satunya tidak ikut menghapus file milik gambar lain
+	for _, image := range campaign.CampaignImages {
+		if image.FileName == fileLocation {
+			return CampaignImage{}, errors.New("Image has already been uploaded to this campaign")
+		}
+	}
+
 	isPrimary := 0
 	if input.IsPrimary {
 		isPrimary = 1
@@ -120,6 +272,7 @@ func (s *service) SaveCampaignImage(input CreateCampaignImageInput, fileLocation
 	campaignImage.CampaignID = input.CampaignID
 	campaignImage.IsPrimary = isPrimary
 	campaignImage.FileName = fileLocation
+	campaignImage.Position = len(campaign.CampaignImages)
 
 	newCampaignImage, err:= s.repository.CreateImage(campaignImage)
 
@@ -127,4 +280,253 @@ func (s *service) SaveCampaignImage(input CreateCampaignImageInput, fileLocation
 		return newCampaignImage, err
 	}
 	return newCampaignImage, nil
-}
\ No newline at end of file
+}
+
+// findOwnedImage mengambil gambar beserta campaign-nya dan memastikan user adalah pemilik campaign
+func (s *service) findOwnedImage(input GetCampaignImageInput) (CampaignImage, Campaign, error) {
+	campaignImage, err := s.repository.FindImageByID(input.ID)
+	if err != nil {
+		return campaignImage, Campaign{}, err
+	}
+	if campaignImage.ID == 0 {
+		return campaignImage, Campaign{}, errors.New("No campaign image found with that ID")
+	}
+
+	campaign, err := s.repository.FindByID(campaignImage.CampaignID)
+	if err != nil {
+		return campaignImage, campaign, err
+	}
+
+	if campaign.UserID != input.User.ID {
+		return campaignImage, campaign, errors.New("Not an owner of the campaign")
+	}
+	return campaignImage, campaign, nil
+}
+
+func (s *service) UpdateCampaignImage(inputID GetCampaignImageInput, inputData UpdateCampaignImageInput) (CampaignImage, error) {
+	inputID.User = inputData.User
+	campaignImage, _, err := s.findOwnedImage(inputID)
+	if err != nil {
+		return campaignImage, err
+	}
+
+	if inputData.IsPrimary != nil {
+		if *inputData.IsPrimary {
+			_, err := s.repository.MarkAllImagesAsNonPrimary(campaignImage.CampaignID)
+			if err != nil {
+				return campaignImage, err
+			}
+			campaignImage.IsPrimary = 1
+		} else {
+			campaignImage.IsPrimary = 0
+		}
+	}
+
+	if inputData.Caption != nil {
+		campaignImage.Caption = *inputData.Caption
+	}
+
+	updatedCampaignImage, err := s.repository.UpdateImage(campaignImage)
+	if err != nil {
+		return updatedCampaignImage, err
+	}
+	return updatedCampaignImage, nil
+}
+
+// DeleteCampaignImage menghapus data gambar, file-nya dihapus oleh pemanggil menggunakan FileName
+// dari gambar yang dikembalikan. Kalau yang dihapus gambar primary, gambar pertama berikutnya
+// dijadikan primary
+func (s *service) DeleteCampaignImage(input GetCampaignImageInput) (CampaignImage, error) {
+	campaignImage, campaign, err := s.findOwnedImage(input)
+	if err != nil {
+		return campaignImage, err
+	}
+
+	_, err = s.repository.DeleteImage(campaignImage)
+	if err != nil {
+		return campaignImage, err
+	}
+
+	remainingIDs := []int{}
+	for _, image := range campaign.CampaignImages {
+		if image.ID != campaignImage.ID {
+			remainingIDs = append(remainingIDs, image.ID)
+		}
+	}
+
+	_, err = s.repository.UpdateImagePositions(campaign.ID, remainingIDs)
+	if err != nil {
+		return campaignImage, err
+	}
+
+	if campaignImage.IsPrimary == 1 && len(remainingIDs) > 0 {
+		nextImage, err := s.repository.FindImageByID(remainingIDs[0])
+		if err != nil {
+			return campaignImage, err
+		}
+		nextImage.IsPrimary = 1
+
+		_, err = s.repository.UpdateImage(nextImage)
+		if err != nil {
+			return campaignImage, err
+		}
+	}
+
+	return campaignImage, nil
+}
+
+// ReorderCampaignImages imageIDs harus berisi semua gambar milik campaign tepat satu kali
+func (s *service) ReorderCampaignImages(inputID GetCampaignDetailInput, inputData ReorderCampaignImagesInput) ([]CampaignImage, error) {
+	campaign, err := s.repository.FindByID(inputID.ID)
+	if err != nil {
+		return []CampaignImage{}, err
+	}
+
+	if campaign.UserID != inputData.User.ID {
+		return []CampaignImage{}, errors.New("Not an owner of the campaign")
+	}
+
+	images := map[int]CampaignImage{}
+	for _, image := range campaign.CampaignImages {
+		images[image.ID] = image
+	}
+
+	if len(inputData.ImageIDs) != len(images) {
+		return []CampaignImage{}, errors.New("Image order must contain every campaign image")
+	}
+
+	orderedImages := []CampaignImage{}
+	for position, imageID := range inputData.ImageIDs {
+		image, ok := images[imageID]
+		if !ok {
+			return []CampaignImage{}, errors.New("Image order must contain every campaign image")
+		}
+		delete(images, imageID)
+
+		image.Position = position
+		orderedImages = append(orderedImages, image)
+	}
+
+	_, err = s.repository.UpdateImagePositions(campaign.ID, inputData.ImageIDs)
+	if err != nil {
+		return orderedImages, err
+	}
+	return orderedImages, nil
+}
+
+// generateSlug membuat slug unik dari nama campaign, kalau sudah dipakai campaign lain
+// ditambahkan akhiran angka (-2, -3, dst)
+func (s *service) generateSlug(name string, userID int, campaignID int) (string, error) {
+	slugCandidate := fmt.Sprintf("%s %d", name, userID)
+	baseSlug := slug.Make(slugCandidate)
+
+	campaignSlug := baseSlug
+	for i := 2; ; i++ {
+		isTaken, err := s.repository.IsSlugTaken(campaignSlug, campaignID)
+		if err != nil {
+			return "", err
+		}
+		if !isTaken {
+			return campaignSlug, nil
+		}
+		campaignSlug = fmt.Sprintf("%s-%d", baseSlug, i)
+	}
+}
+
+// changeCurrency mata uang hanya bisa diganti sebelum campaign punya transaksi,
+// karena amount transaksi dan ledger dicatat dalam mata uang campaign. Kosong berarti tidak diubah
+func (s *service) changeCurrency(campaign *Campaign, currency string) error {
+	if currency == "" || money.NormalizeCurrency(currency) == money.NormalizeCurrency(campaign.Currency) {
+		return nil
+	}
+
+	if !money.IsSupported(currency) {
+		return errors.New("Currency is not supported")
+	}
+
+	hasTransactions, err := s.repository.HasTransactions(campaign.ID)
+	if err != nil {
+		return err
+	}
+	if hasTransactions {
+		return errors.New("Currency cannot be changed after the campaign has received pledges")
+	}
+
+	campaign.Currency = money.NormalizeCurrency(currency)
+	return nil
+}
+
+// applyPledgeRules input nil berarti aturan dukungan campaign tidak diubah
+func applyPledgeRules(campaign *Campaign, input *PledgeRulesInput) error {
+	if input == nil {
+		return nil
+	}
+
+	err := validatePledgeRules(*input)
+	if err != nil {
+		return err
+	}
+
+	campaign.PledgeMinimumAmount = input.MinimumAmount
+	campaign.PledgeMaximumAmount = input.MaximumAmount
+	campaign.PledgeIncrement = input.Increment
+	campaign.OverfundingCapPercent = input.OverfundingCapPercent
+	return nil
+}
+
+// checkCategory memastikan category yang dipilih ada, category 0 berarti tanpa kategori
+func (s *service) checkCategory(categoryID int) error {
+	if categoryID == 0 {
+		return nil
+	}
+
+	existingCategory, err := s.categoryRepository.FindByID(categoryID)
+	if err != nil {
+		return err
+	}
+	if existingCategory.ID == 0 {
+		return errors.New("No category found with that ID")
+	}
+	return nil
+}
+
+// ParseTags memecah input tag yang dipisahkan koma (seperti perks) menjadi daftar tag unik
+func ParseTags(input string) []string {
+	tags := []string{}
+	seen := map[string]bool{}
+
+	for _, tag := range strings.Split(input, ",") {
+		tag = normalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// UpdateCampaignFee override fee platform campaign dari CMS, nil berarti ikut kategori/default
+func (s *service) UpdateCampaignFee(inputID GetCampaignDetailInput, feeBasisPoints *int, feeFixed *int) (Campaign, error) {
+	campaign, err := s.repository.FindByID(inputID.ID)
+	if err != nil {
+		return campaign, err
+	}
+
+	if campaign.ID == 0 {
+		return campaign, errors.New("No campaign found with that ID")
+	}
+
+	err = s.repository.UpdateFee(campaign.ID, feeBasisPoints, feeFixed)
+	if err != nil {
+		return campaign, err
+	}
+
+	campaign.FeeBasisPoints = feeBasisPoints
+	campaign.FeeFixed = feeFixed
+	return campaign, nil
+}
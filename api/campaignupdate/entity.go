@@ -0,0 +1,25 @@
+package campaignupdate
+
+import (
+	"bwastartup/api/user"
+	"time"
+)
+
+const (
+	VisibilityPublic  = "public"
+	VisibilityBackers = "backers"
+)
+
+// CampaignUpdate kabar perkembangan campaign yang ditulis oleh pembuat campaign,
+// Body berisi markdown dan dikirim apa adanya ke client
+type CampaignUpdate struct {
+	ID         int
+	CampaignID int
+	UserID     int
+	Title      string
+	Body       string
+	Visibility string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	User       user.User
+}
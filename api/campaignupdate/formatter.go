@@ -0,0 +1,47 @@
+package campaignupdate
+
+import (
+	"bwastartup/api/asset"
+	"time"
+)
+
+type CampaignUpdateFormatter struct {
+	ID         int                         `json:"id"`
+	CampaignID int                         `json:"campaign_id"`
+	Title      string                      `json:"title"`
+	Body       string                      `json:"body"`
+	Visibility string                      `json:"visibility"`
+	CreatedAt  time.Time                   `json:"created_at"`
+	User       CampaignUpdateUserFormatter `json:"user"`
+}
+
+type CampaignUpdateUserFormatter struct {
+	Name     string `json:"name"`
+	ImageURL string `json:"image_url"`
+}
+
+func FormatCampaignUpdate(campaignUpdate CampaignUpdate) CampaignUpdateFormatter {
+	formatter := CampaignUpdateFormatter{}
+	formatter.ID = campaignUpdate.ID
+	formatter.CampaignID = campaignUpdate.CampaignID
+	formatter.Title = campaignUpdate.Title
+	formatter.Body = campaignUpdate.Body
+	formatter.Visibility = campaignUpdate.Visibility
+	formatter.CreatedAt = campaignUpdate.CreatedAt
+
+	userFormatter := CampaignUpdateUserFormatter{}
+	userFormatter.Name = campaignUpdate.User.Name
+	userFormatter.ImageURL = asset.URL(campaignUpdate.User.AvatarFileName)
+	formatter.User = userFormatter
+
+	return formatter
+}
+
+func FormatCampaignUpdates(campaignUpdates []CampaignUpdate) []CampaignUpdateFormatter {
+	campaignUpdatesFormatter := []CampaignUpdateFormatter{}
+
+	for _, campaignUpdate := range campaignUpdates {
+		campaignUpdatesFormatter = append(campaignUpdatesFormatter, FormatCampaignUpdate(campaignUpdate))
+	}
+	return campaignUpdatesFormatter
+}
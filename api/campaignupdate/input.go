@@ -0,0 +1,15 @@
+package campaignupdate
+
+import "bwastartup/api/user"
+
+type GetCampaignUpdatesInput struct {
+	ID   int `uri:"id" binding:"required"`
+	User user.User
+}
+
+type CreateCampaignUpdateInput struct {
+	Title      string `json:"title" binding:"required"`
+	Body       string `json:"body" binding:"required"`
+	Visibility string `json:"visibility" binding:"required,oneof=public backers"`
+	User       user.User
+}
@@ -0,0 +1,39 @@
+package campaignupdate
+
+import "gorm.io/gorm"
+
+type Repository interface {
+	FindByCampaignID(campaignID int, includeBackersOnly bool) ([]CampaignUpdate, error)
+	Save(campaignUpdate CampaignUpdate) (CampaignUpdate, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *repository {
+	return &repository{db}
+}
+
+func (r *repository) FindByCampaignID(campaignID int, includeBackersOnly bool) ([]CampaignUpdate, error) {
+	var campaignUpdates []CampaignUpdate
+
+	query := r.db.Preload("User").Where("campaign_id = ?", campaignID)
+	if !includeBackersOnly {
+		query = query.Where("visibility = ?", VisibilityPublic)
+	}
+
+	err := query.Order("id desc").Find(&campaignUpdates).Error
+	if err != nil {
+		return campaignUpdates, err
+	}
+	return campaignUpdates, nil
+}
+
+func (r *repository) Save(campaignUpdate CampaignUpdate) (CampaignUpdate, error) {
+	err := r.db.Create(&campaignUpdate).Error
+	if err != nil {
+		return campaignUpdate, err
+	}
+	return campaignUpdate, nil
+}
@@ -0,0 +1,115 @@
+package campaignupdate
+
+import (
+	"bwastartup/api/campaign"
+	"bwastartup/api/notification"
+	"bwastartup/api/transaction"
+	"errors"
+	"fmt"
+	"log"
+)
+
+type Service interface {
+	GetCampaignUpdates(input GetCampaignUpdatesInput) ([]CampaignUpdate, error)
+	CreateCampaignUpdate(inputID GetCampaignUpdatesInput, inputData CreateCampaignUpdateInput) (CampaignUpdate, error)
+}
+
+type service struct {
+	repository            Repository
+	campaignRepository    campaign.Repository
+	transactionRepository transaction.Repository
+	notificationService   notification.Service
+}
+
+func NewService(repository Repository, campaignRepository campaign.Repository, transactionRepository transaction.Repository, notificationService notification.Service) *service {
+	return &service{repository, campaignRepository, transactionRepository, notificationService}
+}
+
+// GetCampaignUpdates update dengan visibility backers hanya terlihat oleh pemilik campaign
+// dan user yang punya transaksi paid di campaign tersebut
+func (s *service) GetCampaignUpdates(input GetCampaignUpdatesInput) ([]CampaignUpdate, error) {
+	campaign, err := s.campaignRepository.FindByID(input.ID)
+	if err != nil {
+		return []CampaignUpdate{}, err
+	}
+	if campaign.ID == 0 {
+		return []CampaignUpdate{}, errors.New("No campaign found with that ID")
+	}
+
+	includeBackersOnly := false
+	if input.User.ID != 0 {
+		if campaign.UserID == input.User.ID {
+			includeBackersOnly = true
+		} else {
+			includeBackersOnly, err = s.transactionRepository.IsPaidBacker(campaign.ID, input.User.ID)
+			if err != nil {
+				return []CampaignUpdate{}, err
+			}
+		}
+	}
+
+	campaignUpdates, err := s.repository.FindByCampaignID(campaign.ID, includeBackersOnly)
+	if err != nil {
+		return campaignUpdates, err
+	}
+	return campaignUpdates, nil
+}
+
+func (s *service) CreateCampaignUpdate(inputID GetCampaignUpdatesInput, inputData CreateCampaignUpdateInput) (CampaignUpdate, error) {
+	campaign, err := s.campaignRepository.FindByID(inputID.ID)
+	if err != nil {
+		return CampaignUpdate{}, err
+	}
+
+	if campaign.UserID != inputData.User.ID {
+		return CampaignUpdate{}, errors.New("Not an owner of the campaign")
+	}
+
+	campaignUpdate := CampaignUpdate{}
+	campaignUpdate.CampaignID = campaign.ID
+	campaignUpdate.UserID = inputData.User.ID
+	campaignUpdate.Title = inputData.Title
+	campaignUpdate.Body = inputData.Body
+	campaignUpdate.Visibility = inputData.Visibility
+
+	newCampaignUpdate, err := s.repository.Save(campaignUpdate)
+	if err != nil {
+		return newCampaignUpdate, err
+	}
+	newCampaignUpdate.User = inputData.User
+
+	// update sudah tersimpan, kegagalan kirim notifikasi cukup dicatat di log
+	err = s.notifyBackers(campaign, newCampaignUpdate)
+	if err != nil {
+		log.Printf("failed to notify backers of campaign %d: %s", campaign.ID, err.Error())
+	}
+
+	return newCampaignUpdate, nil
+}
+
+// notifyBackers mengirim notifikasi ke semua user yang punya transaksi paid di campaign
+func (s *service) notifyBackers(campaign campaign.Campaign, campaignUpdate CampaignUpdate) error {
+	backerIDs, err := s.transactionRepository.GetPaidBackerIDs(campaign.ID)
+	if err != nil {
+		return err
+	}
+
+	userIDs := []int{}
+	for _, backerID := range backerIDs {
+		if backerID != campaign.UserID {
+			userIDs = append(userIDs, backerID)
+		}
+	}
+
+	notificationInput := notification.NotificationInput{}
+	notificationInput.Type = "campaign_update"
+	notificationInput.Title = fmt.Sprintf("New update on %s", campaign.Name)
+	notificationInput.Message = campaignUpdate.Title
+	notificationInput.Link = fmt.Sprintf("/api/v1/campaigns/%d/updates", campaign.ID)
+
+	_, err = s.notificationService.NotifyUsers(userIDs, notificationInput)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package category
+
+import "time"
+
+type Category struct {
+	ID          int
+	Name        string
+	Slug        string
+	Icon        string
+	Description string
+	// FeeBasisPoints dan FeeFixed override fee platform untuk kategori ini, nil berarti ikut default
+	FeeBasisPoints *int
+	FeeFixed       *int
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
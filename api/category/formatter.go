@@ -0,0 +1,28 @@
+package category
+
+type CategoryFormatter struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Icon        string `json:"icon"`
+	Description string `json:"description"`
+}
+
+func FormatCategory(category Category) CategoryFormatter {
+	formatter := CategoryFormatter{}
+	formatter.ID = category.ID
+	formatter.Name = category.Name
+	formatter.Slug = category.Slug
+	formatter.Icon = category.Icon
+	formatter.Description = category.Description
+	return formatter
+}
+
+func FormatCategories(categories []Category) []CategoryFormatter {
+	categoriesFormatter := []CategoryFormatter{}
+
+	for _, category := range categories {
+		categoriesFormatter = append(categoriesFormatter, FormatCategory(category))
+	}
+	return categoriesFormatter
+}
@@ -0,0 +1,27 @@
+package category
+
+type GetCategoryInput struct {
+	Slug string `uri:"slug" binding:"required"`
+}
+
+// ini digunakan untuk http form
+type FormCreateCategoryInput struct {
+	Name        string `form:"name" binding:"required"`
+	Icon        string `form:"icon"`
+	Description string `form:"description"`
+	Error       error
+}
+
+type FormUpdateCategoryInput struct {
+	ID          int
+	Name        string `form:"name" binding:"required"`
+	Icon        string `form:"icon"`
+	Description string `form:"description"`
+	// FeePercent dan FeeFixed isian form override fee, kosong berarti ikut fee default
+	FeePercent string `form:"fee_percent"`
+	FeeFixed   string `form:"fee_fixed"`
+	// FeeBasisPoints dan FeeFixedAmount hasil parsing FeePercent dan FeeFixed
+	FeeBasisPoints *int
+	FeeFixedAmount *int
+	Error          error
+}
@@ -0,0 +1,108 @@
+package category
+
+import (
+	"errors"
+
+	"github.com/gosimple/slug"
+)
+
+type Service interface {
+	GetCategories() ([]Category, error)
+	GetCategoryByID(ID int) (Category, error)
+	GetCategoryBySlug(input GetCategoryInput) (Category, error)
+	CreateCategory(input FormCreateCategoryInput) (Category, error)
+	UpdateCategory(input FormUpdateCategoryInput) (Category, error)
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(repository Repository) *service {
+	return &service{repository}
+}
+
+func (s *service) GetCategories() ([]Category, error) {
+	categories, err := s.repository.FindAll()
+	if err != nil {
+		return categories, err
+	}
+	return categories, nil
+}
+
+func (s *service) GetCategoryByID(ID int) (Category, error) {
+	category, err := s.repository.FindByID(ID)
+	if err != nil {
+		return category, err
+	}
+	if category.ID == 0 {
+		return category, errors.New("No category found with that ID")
+	}
+	return category, nil
+}
+
+func (s *service) GetCategoryBySlug(input GetCategoryInput) (Category, error) {
+	category, err := s.repository.FindBySlug(input.Slug)
+	if err != nil {
+		return category, err
+	}
+	if category.ID == 0 {
+		return category, errors.New("No category found with that slug")
+	}
+	return category, nil
+}
+
+func (s *service) CreateCategory(input FormCreateCategoryInput) (Category, error) {
+	category := Category{}
+	category.Name = input.Name
+	category.Icon = input.Icon
+	category.Description = input.Description
+	category.Slug = slug.Make(input.Name)
+
+	// slug kategori harus unik karena dipakai di url /categories/:slug/campaigns
+	existingCategory, err := s.repository.FindBySlug(category.Slug)
+	if err != nil {
+		return category, err
+	}
+	if existingCategory.ID != 0 {
+		return category, errors.New("Category is already registered")
+	}
+
+	newCategory, err := s.repository.Save(category)
+	if err != nil {
+		return newCategory, err
+	}
+	return newCategory, nil
+}
+
+func (s *service) UpdateCategory(input FormUpdateCategoryInput) (Category, error) {
+	category, err := s.repository.FindByID(input.ID)
+	if err != nil {
+		return category, err
+	}
+	if category.ID == 0 {
+		return category, errors.New("No category found with that ID")
+	}
+
+	newSlug := slug.Make(input.Name)
+	existingCategory, err := s.repository.FindBySlug(newSlug)
+	if err != nil {
+		return category, err
+	}
+	if existingCategory.ID != 0 && existingCategory.ID != category.ID {
+		return category, errors.New("Category is already registered")
+	}
+
+	category.Name = input.Name
+	category.Icon = input.Icon
+	category.Description = input.Description
+	category.Slug = newSlug
+	category.FeeBasisPoints = input.FeeBasisPoints
+	category.FeeFixed = input.FeeFixedAmount
+
+	updatedCategory, err := s.repository.Update(category)
+	if err != nil {
+		return updatedCategory, err
+	}
+	return updatedCategory, nil
+}
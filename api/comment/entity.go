@@ -0,0 +1,45 @@
+package comment
+
+import (
+	"bwastartup/api/user"
+	"time"
+)
+
+const (
+	StatusVisible = "visible"
+	StatusHidden  = "hidden"
+	StatusDeleted = "deleted"
+)
+
+// EditWindow batas waktu penulis komentar boleh mengubah atau menghapus komentarnya
+const EditWindow = 15 * time.Minute
+
+// Comment komentar pada campaign, balasan menyimpan ParentID (komentar yang dibalas)
+// dan RootID (komentar paling atas di thread) supaya satu thread bisa diambil sekaligus
+type Comment struct {
+	ID         int
+	CampaignID int
+	UserID     int
+	ParentID   int
+	RootID     int
+	Body       string
+	Status     string
+	FlagCount  int
+	EditedAt   *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	User       user.User
+	IsCreator  bool `gorm:"-"`
+}
+
+type CommentFlag struct {
+	ID        int
+	CommentID int
+	UserID    int
+	Reason    string
+	CreatedAt time.Time
+}
+
+func (c Comment) IsEditable(now time.Time) bool {
+	return c.Status == StatusVisible && now.Sub(c.CreatedAt) <= EditWindow
+}
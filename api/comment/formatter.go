@@ -0,0 +1,84 @@
+package comment
+
+import (
+	"bwastartup/api/asset"
+	"time"
+)
+
+type CommentFormatter struct {
+	ID        int                  `json:"id"`
+	ParentID  int                  `json:"parent_id"`
+	Body      string               `json:"body"`
+	Status    string               `json:"status"`
+	IsCreator bool                 `json:"is_creator"`
+	IsEdited  bool                 `json:"is_edited"`
+	CreatedAt time.Time            `json:"created_at"`
+	User      CommentUserFormatter `json:"user"`
+	Replies   []CommentFormatter   `json:"replies"`
+}
+
+type CommentUserFormatter struct {
+	Name     string `json:"name"`
+	ImageURL string `json:"image_url"`
+}
+
+type CommentPageFormatter struct {
+	Comments []CommentFormatter `json:"comments"`
+	Page     int                `json:"page"`
+	Limit    int                `json:"limit"`
+	Total    int64              `json:"total"`
+}
+
+// FormatComment isi komentar yang disembunyikan admin atau dihapus penulisnya dikosongkan
+func FormatComment(comment Comment) CommentFormatter {
+	formatter := CommentFormatter{}
+	formatter.ID = comment.ID
+	formatter.ParentID = comment.ParentID
+	formatter.Status = comment.Status
+	formatter.IsCreator = comment.IsCreator
+	formatter.IsEdited = comment.EditedAt != nil
+	formatter.CreatedAt = comment.CreatedAt
+	formatter.Replies = []CommentFormatter{}
+
+	formatter.Body = ""
+	if comment.Status == StatusVisible {
+		formatter.Body = comment.Body
+	}
+
+	userFormatter := CommentUserFormatter{}
+	userFormatter.Name = comment.User.Name
+	userFormatter.ImageURL = asset.URL(comment.User.AvatarFileName)
+	formatter.User = userFormatter
+
+	return formatter
+}
+
+// FormatComments menyusun daftar komentar (flat) menjadi thread bersarang
+func FormatComments(comments []Comment) []CommentFormatter {
+	children := map[int][]Comment{}
+	for _, comment := range comments {
+		children[comment.ParentID] = append(children[comment.ParentID], comment)
+	}
+
+	var build func(parentID int) []CommentFormatter
+	build = func(parentID int) []CommentFormatter {
+		formatters := []CommentFormatter{}
+		for _, comment := range children[parentID] {
+			formatter := FormatComment(comment)
+			formatter.Replies = build(comment.ID)
+			formatters = append(formatters, formatter)
+		}
+		return formatters
+	}
+
+	return build(0)
+}
+
+func FormatCommentPage(comments []Comment, page int, limit int, total int64) CommentPageFormatter {
+	formatter := CommentPageFormatter{}
+	formatter.Comments = FormatComments(comments)
+	formatter.Page = page
+	formatter.Limit = limit
+	formatter.Total = total
+	return formatter
+}
@@ -0,0 +1,48 @@
+package comment
+
+import "bwastartup/api/user"
+
+type GetCampaignCommentsInput struct {
+	ID    int `uri:"id" binding:"required"`
+	Page  int
+	Limit int
+}
+
+type GetCommentInput struct {
+	ID int `uri:"id" binding:"required"`
+}
+
+type CreateCommentInput struct {
+	Body     string `json:"body" binding:"required"`
+	ParentID int    `json:"parent_id"`
+	User     user.User
+}
+
+type UpdateCommentInput struct {
+	Body string `json:"body" binding:"required"`
+	User user.User
+}
+
+type FlagCommentInput struct {
+	Reason string `json:"reason"`
+	User   user.User
+}
+
+const (
+	defaultLimit = 10
+	maxLimit     = 50
+)
+
+// NormalizePagination mengisi nilai default page dan limit serta membatasi limit maksimal
+func NormalizePagination(page int, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	return page, limit
+}
@@ -0,0 +1,126 @@
+package comment
+
+import "gorm.io/gorm"
+
+type Repository interface {
+	FindRootsByCampaignID(campaignID int, page int, limit int) ([]Comment, int64, error)
+	FindByRootIDs(rootIDs []int) ([]Comment, error)
+	FindByID(ID int) (Comment, error)
+	FindForModeration() ([]Comment, error)
+	Save(comment Comment) (Comment, error)
+	Update(comment Comment) (Comment, error)
+	UpdateStatus(ID int, status string) (bool, error)
+	HasFlagged(commentID int, userID int) (bool, error)
+	SaveFlag(commentFlag CommentFlag) (CommentFlag, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *repository {
+	return &repository{db}
+}
+
+// FindRootsByCampaignID mengambil komentar paling atas (bukan balasan) per halaman beserta total datanya
+func (r *repository) FindRootsByCampaignID(campaignID int, page int, limit int) ([]Comment, int64, error) {
+	var comments []Comment
+	var total int64
+
+	query := r.db.Model(&Comment{}).Where("campaign_id = ? AND parent_id = 0", campaignID)
+
+	err := query.Count(&total).Error
+	if err != nil {
+		return comments, total, err
+	}
+
+	err = query.Preload("User").Order("id desc").Offset((page - 1) * limit).Limit(limit).Find(&comments).Error
+	if err != nil {
+		return comments, total, err
+	}
+	return comments, total, nil
+}
+
+func (r *repository) FindByRootIDs(rootIDs []int) ([]Comment, error) {
+	var comments []Comment
+	if len(rootIDs) == 0 {
+		return comments, nil
+	}
+
+	err := r.db.Preload("User").Where("root_id IN ?", rootIDs).Order("id asc").Find(&comments).Error
+	if err != nil {
+		return comments, err
+	}
+	return comments, nil
+}
+
+func (r *repository) FindByID(ID int) (Comment, error) {
+	var comment Comment
+
+	err := r.db.Preload("User").Where("id = ?", ID).Find(&comment).Error
+	if err != nil {
+		return comment, err
+	}
+	return comment, nil
+}
+
+// FindForModeration komentar yang paling banyak di-flag ditampilkan paling atas di CMS
+func (r *repository) FindForModeration() ([]Comment, error) {
+	var comments []Comment
+
+	err := r.db.Preload("User").Where("status <> ?", StatusDeleted).Order("flag_count desc").Order("id desc").Find(&comments).Error
+	if err != nil {
+		return comments, err
+	}
+	return comments, nil
+}
+
+func (r *repository) Save(comment Comment) (Comment, error) {
+	err := r.db.Create(&comment).Error
+	if err != nil {
+		return comment, err
+	}
+	return comment, nil
+}
+
+func (r *repository) Update(comment Comment) (Comment, error) {
+	err := r.db.Save(&comment).Error
+	if err != nil {
+		return comment, err
+	}
+	return comment, nil
+}
+
+func (r *repository) UpdateStatus(ID int, status string) (bool, error) {
+	err := r.db.Model(&Comment{}).Where("id = ?", ID).Update("status", status).Error
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *repository) HasFlagged(commentID int, userID int) (bool, error) {
+	var count int64
+
+	err := r.db.Model(&CommentFlag{}).Where("comment_id = ? AND user_id = ?", commentID, userID).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// SaveFlag menyimpan flag dan menaikkan flag_count komentar dalam satu transaksi
+func (r *repository) SaveFlag(commentFlag CommentFlag) (CommentFlag, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&commentFlag).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&Comment{}).Where("id = ?", commentFlag.CommentID).Update("flag_count", gorm.Expr("flag_count + 1")).Error
+	})
+
+	if err != nil {
+		return commentFlag, err
+	}
+	return commentFlag, nil
+}
@@ -0,0 +1,221 @@
+package comment
+
+import (
+	"bwastartup/api/campaign"
+	"bwastartup/api/transaction"
+	"bwastartup/api/user"
+	"errors"
+	"time"
+)
+
+type Service interface {
+	GetCampaignComments(input GetCampaignCommentsInput) ([]Comment, int64, error)
+	CreateComment(inputID GetCampaignCommentsInput, inputData CreateCommentInput) (Comment, error)
+	UpdateComment(inputID GetCommentInput, inputData UpdateCommentInput) (Comment, error)
+	DeleteComment(inputID GetCommentInput, currentUser user.User) error
+	FlagComment(inputID GetCommentInput, inputData FlagCommentInput) error
+	GetCommentsForModeration() ([]Comment, error)
+	SetCommentStatus(ID int, status string) error
+}
+
+type service struct {
+	repository            Repository
+	campaignRepository    campaign.Repository
+	transactionRepository transaction.Repository
+}
+
+func NewService(repository Repository, campaignRepository campaign.Repository, transactionRepository transaction.Repository) *service {
+	return &service{repository, campaignRepository, transactionRepository}
+}
+
+// GetCampaignComments mengembalikan komentar paling atas untuk halaman yang diminta
+// beserta seluruh balasannya (flat), dan total komentar paling atas
+func (s *service) GetCampaignComments(input GetCampaignCommentsInput) ([]Comment, int64, error) {
+	campaign, err := s.campaignRepository.FindByID(input.ID)
+	if err != nil {
+		return []Comment{}, 0, err
+	}
+	if campaign.ID == 0 {
+		return []Comment{}, 0, errors.New("No campaign found with that ID")
+	}
+
+	page, limit := NormalizePagination(input.Page, input.Limit)
+
+	roots, total, err := s.repository.FindRootsByCampaignID(campaign.ID, page, limit)
+	if err != nil {
+		return roots, total, err
+	}
+
+	rootIDs := []int{}
+	for _, root := range roots {
+		rootIDs = append(rootIDs, root.ID)
+	}
+
+	replies, err := s.repository.FindByRootIDs(rootIDs)
+	if err != nil {
+		return roots, total, err
+	}
+
+	comments := append(roots, replies...)
+	for i := range comments {
+		comments[i].IsCreator = comments[i].UserID == campaign.UserID
+	}
+
+	return comments, total, nil
+}
+
+func (s *service) CreateComment(inputID GetCampaignCommentsInput, inputData CreateCommentInput) (Comment, error) {
+	campaign, err := s.campaignRepository.FindByID(inputID.ID)
+	if err != nil {
+		return Comment{}, err
+	}
+	if campaign.ID == 0 {
+		return Comment{}, errors.New("No campaign found with that ID")
+	}
+
+	if campaign.CommentsBackersOnly && campaign.UserID != inputData.User.ID {
+		isBacker, err := s.transactionRepository.IsPaidBacker(campaign.ID, inputData.User.ID)
+		if err != nil {
+			return Comment{}, err
+		}
+		if !isBacker {
+			return Comment{}, errors.New("Only backers can comment on this campaign")
+		}
+	}
+
+	comment := Comment{}
+	comment.CampaignID = campaign.ID
+	comment.UserID = inputData.User.ID
+	comment.Body = inputData.Body
+	comment.Status = StatusVisible
+
+	if inputData.ParentID != 0 {
+		parent, err := s.repository.FindByID(inputData.ParentID)
+		if err != nil {
+			return comment, err
+		}
+		if parent.ID == 0 || parent.CampaignID != campaign.ID || parent.Status != StatusVisible {
+			return comment, errors.New("No comment found to reply")
+		}
+
+		comment.ParentID = parent.ID
+		comment.RootID = parent.RootID
+		if parent.RootID == 0 {
+			comment.RootID = parent.ID
+		}
+	}
+
+	newComment, err := s.repository.Save(comment)
+	if err != nil {
+		return newComment, err
+	}
+	newComment.User = inputData.User
+	newComment.IsCreator = newComment.UserID == campaign.UserID
+
+	return newComment, nil
+}
+
+func (s *service) UpdateComment(inputID GetCommentInput, inputData UpdateCommentInput) (Comment, error) {
+	comment, err := s.repository.FindByID(inputID.ID)
+	if err != nil {
+		return comment, err
+	}
+
+	if comment.ID == 0 || comment.UserID != inputData.User.ID {
+		return comment, errors.New("Not an owner of the comment")
+	}
+
+	now := time.Now()
+	if !comment.IsEditable(now) {
+		return comment, errors.New("Comment can no longer be edited")
+	}
+
+	comment.Body = inputData.Body
+	comment.EditedAt = &now
+
+	updatedComment, err := s.repository.Update(comment)
+	if err != nil {
+		return updatedComment, err
+	}
+	return updatedComment, nil
+}
+
+// DeleteComment komentar tidak benar-benar dihapus supaya balasannya tetap berada di thread
+func (s *service) DeleteComment(inputID GetCommentInput, currentUser user.User) error {
+	comment, err := s.repository.FindByID(inputID.ID)
+	if err != nil {
+		return err
+	}
+
+	if comment.ID == 0 || comment.UserID != currentUser.ID {
+		return errors.New("Not an owner of the comment")
+	}
+
+	if !comment.IsEditable(time.Now()) {
+		return errors.New("Comment can no longer be deleted")
+	}
+
+	_, err = s.repository.UpdateStatus(comment.ID, StatusDeleted)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *service) FlagComment(inputID GetCommentInput, inputData FlagCommentInput) error {
+	comment, err := s.repository.FindByID(inputID.ID)
+	if err != nil {
+		return err
+	}
+	if comment.ID == 0 || comment.Status == StatusDeleted {
+		return errors.New("No comment found with that ID")
+	}
+
+	isFlagged, err := s.repository.HasFlagged(comment.ID, inputData.User.ID)
+	if err != nil {
+		return err
+	}
+	if isFlagged {
+		return errors.New("Comment is already flagged")
+	}
+
+	commentFlag := CommentFlag{}
+	commentFlag.CommentID = comment.ID
+	commentFlag.UserID = inputData.User.ID
+	commentFlag.Reason = inputData.Reason
+
+	_, err = s.repository.SaveFlag(commentFlag)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *service) GetCommentsForModeration() ([]Comment, error) {
+	comments, err := s.repository.FindForModeration()
+	if err != nil {
+		return comments, err
+	}
+	return comments, nil
+}
+
+// SetCommentStatus dipakai admin CMS untuk menyembunyikan atau menampilkan kembali komentar
+func (s *service) SetCommentStatus(ID int, status string) error {
+	if status != StatusVisible && status != StatusHidden {
+		return errors.New("Invalid comment status")
+	}
+
+	comment, err := s.repository.FindByID(ID)
+	if err != nil {
+		return err
+	}
+	if comment.ID == 0 || comment.Status == StatusDeleted {
+		return errors.New("No comment found with that ID")
+	}
+
+	_, err = s.repository.UpdateStatus(comment.ID, status)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package fee
+
+import (
+	"errors"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultBasisPoints fee platform default 5% (1 basis point = 0.01%)
+const DefaultBasisPoints = 500
+
+// Policy fee platform untuk satu transaksi: persentase (dalam basis point) ditambah biaya tetap
+type Policy struct {
+	BasisPoints int
+	Fixed       int
+}
+
+// NewPolicyFromEnv membaca PLATFORM_FEE_PERCENT (misalnya 2.5) dan PLATFORM_FEE_FIXED (dalam rupiah)
+func NewPolicyFromEnv() Policy {
+	policy := Policy{DefaultBasisPoints, 0}
+
+	basisPoints, err := ParsePercent(os.Getenv("PLATFORM_FEE_PERCENT"))
+	if err == nil && basisPoints != nil {
+		policy.BasisPoints = *basisPoints
+	}
+
+	fixed, err := ParseAmount(os.Getenv("PLATFORM_FEE_FIXED"))
+	if err == nil && fixed != nil {
+		policy.Fixed = *fixed
+	}
+	return policy
+}
+
+// Calculate fee dibulatkan ke bawah dan tidak pernah melebihi amount
+func (p Policy) Calculate(amount int) int {
+	fee := amount*p.BasisPoints/10000 + p.Fixed
+	if fee > amount {
+		return amount
+	}
+	if fee < 0 {
+		return 0
+	}
+	return fee
+}
+
+// ParsePercent mengubah persentase ("2.5") menjadi basis point (250), string kosong berarti tidak di-override (nil)
+func ParsePercent(value string) (*int, error) {
+	value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "%"))
+	if value == "" {
+		return nil, nil
+	}
+
+	percent, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	if err != nil || percent < 0 || percent > 100 {
+		return nil, errors.New("Fee percent must be a number between 0 and 100")
+	}
+
+	basisPoints := int(math.Round(percent * 100))
+	return &basisPoints, nil
+}
+
+// ParseAmount string kosong berarti tidak di-override (nil)
+func ParseAmount(value string) (*int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	amount, err := strconv.Atoi(value)
+	if err != nil || amount < 0 {
+		return nil, errors.New("Fixed fee must be a positive number")
+	}
+	return &amount, nil
+}
+
+// FormatPercent kebalikan ParsePercent untuk ditampilkan di form
+func FormatPercent(basisPoints *int) string {
+	if basisPoints == nil {
+		return ""
+	}
+	return strconv.FormatFloat(float64(*basisPoints)/100, 'f', -1, 64)
+}
+
+// FormatAmount kebalikan ParseAmount untuk ditampilkan di form
+func FormatAmount(amount *int) string {
+	if amount == nil {
+		return ""
+	}
+	return strconv.Itoa(*amount)
+}
@@ -0,0 +1,49 @@
+package fee
+
+import (
+	"bwastartup/api/campaign"
+	"bwastartup/api/money"
+)
+
+type Service interface {
+	PolicyFor(campaign campaign.Campaign) Policy
+	Calculate(campaign campaign.Campaign, amount int) int
+}
+
+type service struct {
+	defaultPolicy Policy
+}
+
+func NewService(defaultPolicy Policy) *service {
+	return &service{defaultPolicy}
+}
+
+// PolicyFor override campaign dipakai lebih dulu, lalu override kategori, lalu policy default.
+// Persentase dan biaya tetap di-override masing-masing (nil berarti ikut level di atasnya)
+func (s *service) PolicyFor(campaign campaign.Campaign) Policy {
+	policy := s.defaultPolicy
+
+	if campaign.Category.FeeBasisPoints != nil {
+		policy.BasisPoints = *campaign.Category.FeeBasisPoints
+	}
+	if campaign.Category.FeeFixed != nil {
+		policy.Fixed = *campaign.Category.FeeFixed
+	}
+
+	// biaya tetap default dan kategori dalam rupiah, campaign mata uang lain hanya memakai biaya tetap miliknya sendiri
+	if money.NormalizeCurrency(campaign.Currency) != money.DefaultCurrency {
+		policy.Fixed = 0
+	}
+
+	if campaign.FeeBasisPoints != nil {
+		policy.BasisPoints = *campaign.FeeBasisPoints
+	}
+	if campaign.FeeFixed != nil {
+		policy.Fixed = *campaign.FeeFixed
+	}
+	return policy
+}
+
+func (s *service) Calculate(campaign campaign.Campaign, amount int) int {
+	return s.PolicyFor(campaign).Calculate(amount)
+}
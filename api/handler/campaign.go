@@ -2,9 +2,11 @@ package handler
 
 import (
 	"bwastartup/api/campaign"
+	"bwastartup/api/upload"
 	"bwastartup/api/user"
 	"bwastartup/helper"
 	"fmt"
+	"log"
 	"net/http"
 	"runtime"
 	"strconv"
@@ -20,10 +22,11 @@ import (
 
 type campaignHandler struct{
 	service campaign.Service
+	uploadService upload.Service
 }
 
-func NewCampaignHandler(service campaign.Service) *campaignHandler{
-	return &campaignHandler{service}
+func NewCampaignHandler(service campaign.Service, uploadService upload.Service) *campaignHandler{
+	return &campaignHandler{service, uploadService}
 }
 
 // api/v1/campaigns
@@ -31,7 +34,8 @@ func(h *campaignHandler) GetCampaigns(c *gin.Context){
 	userID, _:= strconv.Atoi(c.Query("user_id"))
 	Order := c.Query("order")
 	q := c.Query("q")
-	campaigns, err := h.service.GetCampaigns(userID, Order, q)
+	tag := c.Query("tag")
+	campaigns, err := h.service.GetCampaigns(userID, Order, q, tag)
 	if err != nil{
 		response := helper.APIResponse("Error to get campaigns", http.StatusBadRequest, "error", nil)
 		c.JSON(http.StatusBadRequest, response)
@@ -82,6 +86,37 @@ func (h *campaignHandler) GetCampaign(c *gin.Context){
 	fmt.Printf("Penggunaan memori GetCampaign(): %d bytes\n", usedMemory)
 }
 
+// api/v1/campaigns/slug/:slug
+// slug lama (sebelum campaign di-rename) di-redirect permanen ke slug terbaru
+func (h *campaignHandler) GetCampaignBySlug(c *gin.Context){
+	var input campaign.GetCampaignSlugInput
+
+	err := c.ShouldBindUri(&input)
+
+	const messageError = "Failed to get detail of campaign"
+
+	if err != nil {
+		response := helper.APIResponse(messageError, http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	campaignDetail, err := h.service.GetCampaignBySlug(input)
+	if err != nil {
+		response := helper.APIResponse(messageError, http.StatusNotFound, "error", nil)
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	if campaignDetail.Slug != input.Slug {
+		c.Redirect(http.StatusMovedPermanently, fmt.Sprintf("/api/v1/campaigns/slug/%s", campaignDetail.Slug))
+		return
+	}
+
+	response := helper.APIResponse("Campaign detail", http.StatusOK, "success", campaign.FormatCampaignDetail(campaignDetail))
+	c.JSON(http.StatusOK,response)
+}
+
 // tangkap parameter dari user ke input struct
 // ambil current user dari jwt/handler
 // panggil Service, parameternya input struct (dan juga buat slug)
@@ -181,7 +216,6 @@ func (h *campaignHandler) UploadImage(c *gin.Context){
 
 	currentUser := c.MustGet("currentUser").(user.User)
 	input.User = currentUser
-	userID := currentUser.ID
 
 	file, err := c.FormFile("file")
 	if err != nil {
@@ -192,10 +226,9 @@ func (h *campaignHandler) UploadImage(c *gin.Context){
 		return
 	}
 
-	path := fmt.Sprintf("images/%d-%s", userID, file.Filename)
-	
-	err = c.SaveUploadedFile(file, path)
-	if err != nil {
+	// kepemilikan dicek sebelum menulis ke storage agar user lain tidak bisa menaruh file di folder campaign ini
+	existingCampaign, err := h.service.GetCampaignByID(campaign.GetCampaignDetailInput{ID: input.CampaignID})
+	if err != nil || existingCampaign.ID == 0 || existingCampaign.UserID != currentUser.ID {
 		data := gin.H{"is_uploaded":false}
 		response := helper.APIResponse("Failed to upload campaign image", http.StatusBadRequest, "error", data)
 
@@ -203,9 +236,20 @@ func (h *campaignHandler) UploadImage(c *gin.Context){
 		return
 	}
 
+	path, err := h.uploadService.SaveImage(file, fmt.Sprintf("images/campaigns/%d", input.CampaignID))
+	if err != nil {
+		status, data := uploadError(err)
+		response := helper.APIResponse("Failed to upload campaign image", status, "error", data)
+
+		c.JSON(status, response)
+		return
+	}
+
 	_, err = h.service.SaveCampaignImage(input, path)
 
 	if err != nil {
+		removeUnusedCampaignImage(h.uploadService, existingCampaign, path)
+
 		data := gin.H{"is_uploaded":false}
 		response := helper.APIResponse("Failed to upload campaign image", http.StatusBadRequest, "error", data)
 
@@ -217,4 +261,123 @@ func (h *campaignHandler) UploadImage(c *gin.Context){
 	response := helper.APIResponse("Campaign image successfully uploaded", http.StatusOK, "success", data)
 
 	c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+}
+
+// api/v1/campaign-images/:id
+// hapus data gambar lalu hapus file-nya dari storage
+func (h *campaignHandler) DeleteImage(c *gin.Context){
+	var input campaign.GetCampaignImageInput
+
+	err := c.ShouldBindUri(&input)
+	if err != nil {
+		response := helper.APIResponse("Failed to delete campaign image", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	currentUser := c.MustGet("currentUser").(user.User)
+	input.User = currentUser
+
+	deletedImage, err := h.service.DeleteCampaignImage(input)
+	if err != nil {
+		response := helper.APIResponse(err.Error(), http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	err = h.uploadService.Delete(deletedImage.FileName)
+	if err != nil {
+		log.Printf("failed to remove campaign image file %s: %s", deletedImage.FileName, err.Error())
+	}
+
+	response := helper.APIResponseMessage("Campaign image successfully deleted", http.StatusOK, "success")
+	c.JSON(http.StatusOK, response)
+}
+
+// api/v1/campaign-images/:id
+// ubah is_primary dan/atau caption gambar
+func (h *campaignHandler) UpdateImage(c *gin.Context){
+	var inputID campaign.GetCampaignImageInput
+
+	err := c.ShouldBindUri(&inputID)
+	if err != nil {
+		response := helper.APIResponse("Failed to update campaign image", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var inputData campaign.UpdateCampaignImageInput
+
+	err = c.ShouldBindJSON(&inputData)
+	if err != nil {
+		response := helper.APIResponse("Failed to update campaign image", http.StatusUnprocessableEntity, "error", nil)
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("currentUser").(user.User)
+	inputData.User = currentUser
+
+	updatedImage, err := h.service.UpdateCampaignImage(inputID, inputData)
+	if err != nil {
+		response := helper.APIResponse(err.Error(), http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("Campaign image successfully updated", http.StatusOK, "success", campaign.FormatCampaignImage(updatedImage))
+	c.JSON(http.StatusOK, response)
+}
+
+// api/v1/campaigns/:id/images/order
+// body: {"image_ids": [3, 1, 2]}
+func (h *campaignHandler) ReorderImages(c *gin.Context){
+	var inputID campaign.GetCampaignDetailInput
+
+	err := c.ShouldBindUri(&inputID)
+	if err != nil {
+		response := helper.APIResponse("Failed to reorder campaign images", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var inputData campaign.ReorderCampaignImagesInput
+
+	err = c.ShouldBindJSON(&inputData)
+	if err != nil {
+		errors := helper.FormatValidationError(err)
+		errorMessage := gin.H{"errors": errors}
+
+		response := helper.APIResponse("Failed to reorder campaign images", http.StatusUnprocessableEntity, "error", errorMessage)
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("currentUser").(user.User)
+	inputData.User = currentUser
+
+	images, err := h.service.ReorderCampaignImages(inputID, inputData)
+	if err != nil {
+		response := helper.APIResponse(err.Error(), http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("Campaign images successfully reordered", http.StatusOK, "success", campaign.FormatCampaignImages(images))
+	c.JSON(http.StatusOK, response)
+}
+
+// removeUnusedCampaignImage hapus file yang sudah terlanjur disimpan saat data gambar gagal dibuat.
+// Nama file dari hash isi file, jadi file yang sama milik gambar campaign yang sudah ada tidak ikut dihapus
+func removeUnusedCampaignImage(uploadService upload.Service, existingCampaign campaign.Campaign, path string) {
+	for _, image := range existingCampaign.CampaignImages {
+		if image.FileName == path {
+			return
+		}
+	}
+
+	err := uploadService.Delete(path)
+	if err != nil {
+		log.Printf("failed to remove campaign image file %s: %s", path, err.Error())
+	}
+}
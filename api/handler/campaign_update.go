@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"bwastartup/api/campaignupdate"
+	"bwastartup/api/user"
+	"bwastartup/helper"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type campaignUpdateHandler struct {
+	service campaignupdate.Service
+}
+
+func NewCampaignUpdateHandler(service campaignupdate.Service) *campaignUpdateHandler {
+	return &campaignUpdateHandler{service}
+}
+
+// api/v1/campaigns/:id/updates
+// user boleh tidak login, update khusus backer hanya muncul untuk pemilik campaign dan backer
+func (h *campaignUpdateHandler) GetCampaignUpdates(c *gin.Context) {
+	var input campaignupdate.GetCampaignUpdatesInput
+
+	err := c.ShouldBindUri(&input)
+	if err != nil {
+		response := helper.APIResponse("Failed to get campaign's updates", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if currentUser, ok := c.Get("currentUser"); ok {
+		input.User = currentUser.(user.User)
+	}
+
+	campaignUpdates, err := h.service.GetCampaignUpdates(input)
+	if err != nil {
+		response := helper.APIResponse("Failed to get campaign's updates", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("Campaign's updates", http.StatusOK, "success", campaignupdate.FormatCampaignUpdates(campaignUpdates))
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *campaignUpdateHandler) CreateCampaignUpdate(c *gin.Context) {
+	var inputID campaignupdate.GetCampaignUpdatesInput
+
+	err := c.ShouldBindUri(&inputID)
+	if err != nil {
+		response := helper.APIResponse("Failed to create campaign update", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var inputData campaignupdate.CreateCampaignUpdateInput
+
+	err = c.ShouldBindJSON(&inputData)
+	if err != nil {
+		errors := helper.FormatValidationError(err)
+		errorMessage := gin.H{"errors": errors}
+
+		response := helper.APIResponse("Failed to create campaign update", http.StatusUnprocessableEntity, "error", errorMessage)
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("currentUser").(user.User)
+	inputData.User = currentUser
+
+	newCampaignUpdate, err := h.service.CreateCampaignUpdate(inputID, inputData)
+	if err != nil {
+		response := helper.APIResponse(err.Error(), http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("Success to create campaign update", http.StatusOK, "success", campaignupdate.FormatCampaignUpdate(newCampaignUpdate))
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"bwastartup/api/campaign"
+	"bwastartup/api/category"
+	"bwastartup/helper"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type categoryHandler struct {
+	service         category.Service
+	campaignService campaign.Service
+}
+
+func NewCategoryHandler(service category.Service, campaignService campaign.Service) *categoryHandler {
+	return &categoryHandler{service, campaignService}
+}
+
+// api/v1/categories
+func (h *categoryHandler) GetCategories(c *gin.Context) {
+	categories, err := h.service.GetCategories()
+	if err != nil {
+		response := helper.APIResponse("Error to get categories", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("List of categories", http.StatusOK, "success", category.FormatCategories(categories))
+	c.JSON(http.StatusOK, response)
+}
+
+// api/v1/categories/:slug/campaigns
+// filter order, q dan tag sama seperti api/v1/campaigns
+func (h *categoryHandler) GetCategoryCampaigns(c *gin.Context) {
+	var input category.GetCategoryInput
+
+	err := c.ShouldBindUri(&input)
+	if err != nil {
+		response := helper.APIResponse("Failed to get category's campaigns", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	existingCategory, err := h.service.GetCategoryBySlug(input)
+	if err != nil {
+		response := helper.APIResponse("Category not found", http.StatusNotFound, "error", nil)
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	Order := c.Query("order")
+	q := c.Query("q")
+	tag := c.Query("tag")
+	campaigns, err := h.campaignService.GetCampaignsByCategoryID(existingCategory.ID, Order, q, tag)
+	if err != nil {
+		response := helper.APIResponse("Failed to get category's campaigns", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("List of category's campaigns", http.StatusOK, "success", campaign.FormatCampaigns(campaigns))
+	c.JSON(http.StatusOK, response)
+}
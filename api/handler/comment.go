@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"bwastartup/api/comment"
+	"bwastartup/api/user"
+	"bwastartup/helper"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type commentHandler struct {
+	service comment.Service
+}
+
+func NewCommentHandler(service comment.Service) *commentHandler {
+	return &commentHandler{service}
+}
+
+// api/v1/campaigns/:id/comments?page=1&limit=10
+func (h *commentHandler) GetCampaignComments(c *gin.Context) {
+	var input comment.GetCampaignCommentsInput
+
+	err := c.ShouldBindUri(&input)
+	if err != nil {
+		response := helper.APIResponse("Failed to get campaign's comments", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.Query("page"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	input.Page, input.Limit = comment.NormalizePagination(page, limit)
+
+	comments, total, err := h.service.GetCampaignComments(input)
+	if err != nil {
+		response := helper.APIResponse("Failed to get campaign's comments", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("Campaign's comments", http.StatusOK, "success", comment.FormatCommentPage(comments, input.Page, input.Limit, total))
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *commentHandler) CreateComment(c *gin.Context) {
+	var inputID comment.GetCampaignCommentsInput
+
+	err := c.ShouldBindUri(&inputID)
+	if err != nil {
+		response := helper.APIResponse("Failed to create comment", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var inputData comment.CreateCommentInput
+
+	err = c.ShouldBindJSON(&inputData)
+	if err != nil {
+		errors := helper.FormatValidationError(err)
+		errorMessage := gin.H{"errors": errors}
+
+		response := helper.APIResponse("Failed to create comment", http.StatusUnprocessableEntity, "error", errorMessage)
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("currentUser").(user.User)
+	inputData.User = currentUser
+
+	newComment, err := h.service.CreateComment(inputID, inputData)
+	if err != nil {
+		response := helper.APIResponse(err.Error(), http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("Success to create comment", http.StatusOK, "success", comment.FormatComment(newComment))
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *commentHandler) UpdateComment(c *gin.Context) {
+	var inputID comment.GetCommentInput
+
+	err := c.ShouldBindUri(&inputID)
+	if err != nil {
+		response := helper.APIResponse("Failed to update comment", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var inputData comment.UpdateCommentInput
+
+	err = c.ShouldBindJSON(&inputData)
+	if err != nil {
+		errors := helper.FormatValidationError(err)
+		errorMessage := gin.H{"errors": errors}
+
+		response := helper.APIResponse("Failed to update comment", http.StatusUnprocessableEntity, "error", errorMessage)
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	currentUser := c.MustGet("currentUser").(user.User)
+	inputData.User = currentUser
+
+	updatedComment, err := h.service.UpdateComment(inputID, inputData)
+	if err != nil {
+		response := helper.APIResponse(err.Error(), http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("Success to update comment", http.StatusOK, "success", comment.FormatComment(updatedComment))
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *commentHandler) DeleteComment(c *gin.Context) {
+	var inputID comment.GetCommentInput
+
+	err := c.ShouldBindUri(&inputID)
+	if err != nil {
+		response := helper.APIResponse("Failed to delete comment", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	err = h.service.DeleteComment(inputID, currentUser)
+	if err != nil {
+		response := helper.APIResponse(err.Error(), http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponseMessage("Comment has been deleted", http.StatusOK, "success")
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *commentHandler) FlagComment(c *gin.Context) {
+	var inputID comment.GetCommentInput
+
+	err := c.ShouldBindUri(&inputID)
+	if err != nil {
+		response := helper.APIResponse("Failed to flag comment", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var inputData comment.FlagCommentInput
+
+	// alasan flag tidak wajib, body boleh kosong
+	_ = c.ShouldBindJSON(&inputData)
+
+	currentUser := c.MustGet("currentUser").(user.User)
+	inputData.User = currentUser
+
+	err = h.service.FlagComment(inputID, inputData)
+	if err != nil {
+		response := helper.APIResponse(err.Error(), http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponseMessage("Comment has been flagged", http.StatusOK, "success")
+	c.JSON(http.StatusOK, response)
+}
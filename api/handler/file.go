@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"bwastartup/api/asset"
+	"bwastartup/api/storage"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fileHandler struct {
+	storage      storage.Storage
+	assetBuilder asset.Builder
+}
+
+func NewFileHandler(storage storage.Storage, assetBuilder asset.Builder) *fileHandler {
+	return &fileHandler{storage, assetBuilder}
+}
+
+// Serve mengirim file dari storage, dipakai saat storage bukan disk lokal
+// (memory atau bucket private) atau saat URL file memakai signature sehingga router.Static tidak bisa dipakai
+func (h *fileHandler) Serve(c *gin.Context) {
+	key := "images" + c.Param("filepath")
+
+	if !h.assetBuilder.Verify(key, c.Query("expires"), c.Query("signature")) {
+		c.Status(http.StatusForbidden)
+		return
+	}
+
+	file, err := h.storage.Get(key)
+	if err == storage.ErrNotFound {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	contentType := mime.TypeByExtension(filepath.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Status(http.StatusOK)
+	io.Copy(c.Writer, file)
+}
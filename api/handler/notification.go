@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"bwastartup/api/notification"
+	"bwastartup/api/user"
+	"bwastartup/helper"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type notificationHandler struct {
+	service notification.Service
+}
+
+func NewNotificationHandler(service notification.Service) *notificationHandler {
+	return &notificationHandler{service}
+}
+
+func (h *notificationHandler) GetNotifications(c *gin.Context) {
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	notifications, err := h.service.GetNotificationsByUserID(currentUser.ID)
+	if err != nil {
+		response := helper.APIResponse("Failed to get user's notifications", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("User's notifications", http.StatusOK, "success", notification.FormatNotifications(notifications))
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *notificationHandler) MarkAsRead(c *gin.Context) {
+	var input notification.GetNotificationInput
+
+	err := c.ShouldBindUri(&input)
+	if err != nil {
+		response := helper.APIResponse("Failed to read notification", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	currentUser := c.MustGet("currentUser").(user.User)
+	input.User = currentUser
+
+	err = h.service.MarkAsRead(input)
+	if err != nil {
+		response := helper.APIResponse(err.Error(), http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponseMessage("Notification has been read", http.StatusOK, "success")
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"bwastartup/api/payment"
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockPaymentHandler struct {
+	provider *payment.MockProvider
+}
+
+func NewMockPaymentHandler(provider *payment.MockProvider) *mockPaymentHandler {
+	return &mockPaymentHandler{provider}
+}
+
+var mockCheckoutTemplate = template.Must(template.New("checkout").Parse(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8" />
+  <title>Mock Checkout #{{ .OrderID }}</title>
+</head>
+<body style="font-family: sans-serif; max-width: 420px; margin: 40px auto">
+  <h2>Mock Payment Gateway</h2>
+  <p>Order <strong>#{{ .OrderID }}</strong></p>
+  <p>Amount <strong>{{ .Amount }}</strong></p>
+  {{ if .Message }}<p><em>{{ .Message }}</em></p>{{ end }}
+  <form method="POST">
+    <input type="hidden" name="amount" value="{{ .Amount }}" />
+    <input type="hidden" name="signature" value="{{ .Signature }}" />
+    <button type="submit" name="status" value="paid">Pay</button>
+    <button type="submit" name="status" value="cancelled">Cancel</button>
+    <button type="submit" name="status" value="expired">Expire</button>
+  </form>
+</body>
+</html>`))
+
+// payments/mock/checkout/:order_id
+// halaman checkout mock provider, amount dan signature dari payment URL
+func (h *mockPaymentHandler) Checkout(c *gin.Context) {
+	orderID := c.Param("order_id")
+	amount, _ := strconv.Atoi(c.Query("amount"))
+	signature := c.Query("signature")
+
+	if !h.provider.VerifyCheckout(orderID, amount, signature) {
+		c.String(http.StatusForbidden, "Invalid checkout signature")
+		return
+	}
+
+	h.render(c, orderID, amount, signature, "")
+}
+
+// payments/mock/checkout/:order_id (POST)
+// selesaikan pembayaran lalu kirim webhook ke endpoint notifikasi
+func (h *mockPaymentHandler) Complete(c *gin.Context) {
+	orderID := c.Param("order_id")
+	amount, _ := strconv.Atoi(c.PostForm("amount"))
+	signature := c.PostForm("signature")
+
+	if !h.provider.VerifyCheckout(orderID, amount, signature) {
+		c.String(http.StatusForbidden, "Invalid checkout signature")
+		return
+	}
+
+	message := "Payment " + c.PostForm("status") + ", webhook delivered"
+	err := h.provider.Complete(orderID, amount, c.PostForm("status"))
+	if err != nil {
+		message = "Failed to complete payment: " + err.Error()
+	}
+
+	h.render(c, orderID, amount, signature, message)
+}
+
+func (h *mockPaymentHandler) render(c *gin.Context, orderID string, amount int, signature string, message string) {
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusOK)
+	mockCheckoutTemplate.Execute(c.Writer, gin.H{
+		"OrderID":   orderID,
+		"Amount":    amount,
+		"Signature": signature,
+		"Message":   message,
+	})
+}
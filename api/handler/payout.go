@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"bwastartup/api/payout"
+	"bwastartup/api/user"
+	"bwastartup/helper"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type payoutHandler struct {
+	service payout.Service
+}
+
+func NewPayoutHandler(service payout.Service) *payoutHandler {
+	return &payoutHandler{service}
+}
+
+// GetPayouts riwayat payout milik user yang login
+func (h *payoutHandler) GetPayouts(c *gin.Context) {
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	payouts, err := h.service.GetPayoutsByUserID(currentUser.ID)
+	if err != nil {
+		response := helper.APIResponse("Failed to get payouts", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("List of payouts", http.StatusOK, "success", payout.FormatPayouts(payouts))
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *payoutHandler) RequestPayout(c *gin.Context) {
+	var input payout.CreatePayoutInput
+
+	err := c.ShouldBindJSON(&input)
+	if err != nil {
+		errors := helper.FormatValidationError(err)
+		errorMessage := gin.H{"errors": errors}
+
+		response := helper.APIResponse("Failed to request payout", http.StatusUnprocessableEntity, "error", errorMessage)
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	input.User = c.MustGet("currentUser").(user.User)
+
+	newPayout, err := h.service.RequestPayout(input)
+	if err != nil {
+		response := helper.APIResponse(err.Error(), http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("Success to request payout", http.StatusOK, "success", payout.FormatPayout(newPayout))
+	c.JSON(http.StatusOK, response)
+}
+
+// GetBalances saldo yang bisa ditarik per campaign milik user yang login
+func (h *payoutHandler) GetBalances(c *gin.Context) {
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	balances, err := h.service.GetBalances(currentUser.ID)
+	if err != nil {
+		response := helper.APIResponse("Failed to get balances", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("List of balances", http.StatusOK, "success", payout.FormatBalances(balances))
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *payoutHandler) GetBankAccounts(c *gin.Context) {
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	bankAccounts, err := h.service.GetBankAccounts(currentUser.ID)
+	if err != nil {
+		response := helper.APIResponse("Failed to get bank accounts", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("List of bank accounts", http.StatusOK, "success", payout.FormatBankAccounts(bankAccounts))
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *payoutHandler) CreateBankAccount(c *gin.Context) {
+	var input payout.CreateBankAccountInput
+
+	err := c.ShouldBindJSON(&input)
+	if err != nil {
+		errors := helper.FormatValidationError(err)
+		errorMessage := gin.H{"errors": errors}
+
+		response := helper.APIResponse("Failed to create bank account", http.StatusUnprocessableEntity, "error", errorMessage)
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	input.User = c.MustGet("currentUser").(user.User)
+
+	bankAccount, err := h.service.SaveBankAccount(input)
+	if err != nil {
+		response := helper.APIResponse("Failed to create bank account", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("Success to create bank account", http.StatusOK, "success", payout.FormatBankAccount(bankAccount))
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *payoutHandler) DeleteBankAccount(c *gin.Context) {
+	var input payout.GetBankAccountInput
+
+	err := c.ShouldBindUri(&input)
+	if err != nil {
+		response := helper.APIResponse("Failed to delete bank account", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	input.User = c.MustGet("currentUser").(user.User)
+
+	err = h.service.DeleteBankAccount(input)
+	if err != nil {
+		response := helper.APIResponse(err.Error(), http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("Success to delete bank account", http.StatusOK, "success", nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// GetNotification callback hasil transfer dari disbursement provider
+func (h *payoutHandler) GetNotification(c *gin.Context) {
+	var input payout.PayoutNotificationInput
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		response := helper.APIResponse("Failed to process notification", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	input.Provider = c.Param("provider")
+	input.Header = c.Request.Header
+	input.Body = body
+
+	err = h.service.ProcessNotification(input)
+	if errors.Is(err, payout.ErrInvalidCallback) {
+		response := helper.APIResponse("Failed to process notification", http.StatusUnauthorized, "error", nil)
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+	if err != nil {
+		response := helper.APIResponse("Failed to process notification", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("Notification processed", http.StatusOK, "success", nil)
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"bwastartup/api/recurring"
+	"bwastartup/api/user"
+	"bwastartup/helper"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type recurringHandler struct {
+	service recurring.Service
+}
+
+func NewRecurringHandler(service recurring.Service) *recurringHandler {
+	return &recurringHandler{service}
+}
+
+// CreatePledge membuat recurring pledge, backer diarahkan ke payment_url untuk tagihan pertama
+func (h *recurringHandler) CreatePledge(c *gin.Context) {
+	var input recurring.CreatePledgeInput
+
+	err := c.ShouldBindJSON(&input)
+	if err != nil {
+		errors := helper.FormatValidationError(err)
+		errorMessage := gin.H{"errors": errors}
+
+		response := helper.APIResponse("Failed to create recurring pledge", http.StatusUnprocessableEntity, "error", errorMessage)
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	input.User = c.MustGet("currentUser").(user.User)
+
+	newPledge, firstTransaction, err := h.service.CreatePledge(input)
+	if err != nil {
+		response := helper.APIResponse(err.Error(), http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("Success to create recurring pledge", http.StatusOK, "success", recurring.FormatCreatedPledge(newPledge, firstTransaction.ID, firstTransaction.PaymentURL))
+	c.JSON(http.StatusOK, response)
+}
+
+// GetPledges recurring pledge milik user yang login
+func (h *recurringHandler) GetPledges(c *gin.Context) {
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	pledges, err := h.service.GetPledgesByUserID(currentUser.ID)
+	if err != nil {
+		response := helper.APIResponse("Failed to get recurring pledges", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("List of recurring pledges", http.StatusOK, "success", recurring.FormatPledges(pledges))
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *recurringHandler) PausePledge(c *gin.Context) {
+	h.changePledge(c, "pause", h.service.PausePledge)
+}
+
+func (h *recurringHandler) ResumePledge(c *gin.Context) {
+	h.changePledge(c, "resume", h.service.ResumePledge)
+}
+
+func (h *recurringHandler) CancelPledge(c *gin.Context) {
+	h.changePledge(c, "cancel", h.service.CancelPledge)
+}
+
+// changePledge alur yang sama untuk pause, resume dan cancel
+func (h *recurringHandler) changePledge(c *gin.Context, action string, change func(recurring.GetPledgeInput) (recurring.Pledge, error)) {
+	var input recurring.GetPledgeInput
+
+	err := c.ShouldBindUri(&input)
+	if err != nil {
+		response := helper.APIResponse("Failed to "+action+" recurring pledge", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	input.User = c.MustGet("currentUser").(user.User)
+
+	pledge, err := change(input)
+	if err != nil {
+		response := helper.APIResponse(err.Error(), http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("Success to "+action+" recurring pledge", http.StatusOK, "success", recurring.FormatPledge(pledge))
+	c.JSON(http.StatusOK, response)
+}
+
+// GetRevenue monthly recurring revenue campaign milik user yang login
+func (h *recurringHandler) GetRevenue(c *gin.Context) {
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	revenues, err := h.service.GetRevenue(currentUser.ID)
+	if err != nil {
+		response := helper.APIResponse("Failed to get recurring revenue", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("Recurring revenue", http.StatusOK, "success", recurring.FormatRevenues(revenues))
+	c.JSON(http.StatusOK, response)
+}
@@ -1,10 +1,14 @@
 package handler
 
 import (
+	"bwastartup/api/payment"
 	"bwastartup/api/transaction"
 	"bwastartup/api/user"
 	"bwastartup/helper"
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -47,6 +51,33 @@ func (h *transactionHandler) GetCampaignTransactions(c *gin.Context){
 
 }
 
+// api/v1/campaigns/:id/supporters?page=1&limit=20
+func (h *transactionHandler) GetCampaignSupporters(c *gin.Context) {
+	var input transaction.GetCampaignSupportersInput
+
+	err := c.ShouldBindUri(&input)
+	if err != nil {
+		response := helper.APIResponse("Failed to get campaign's supporters", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.Query("page"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	input.Page, input.Limit = transaction.NormalizeSupportersPagination(page, limit)
+
+	supporters, total, err := h.service.GetCampaignSupporters(input)
+	if err != nil {
+		errorMessage := gin.H{"errors": err.Error()}
+		response := helper.APIResponse("Failed to get campaign's supporters", http.StatusBadRequest, "error", errorMessage)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("Campaign's supporters", http.StatusOK, "success", transaction.FormatSupporterPage(supporters, input.Page, input.Limit, total))
+	c.JSON(http.StatusOK, response)
+}
+
 // GetUserTransactions
 // handler 
 // ambil nilai user dari jwt/middleware
@@ -101,7 +132,7 @@ func(h *transactionHandler) CreateTransaction(c *gin.Context){
 func (h *transactionHandler) GetNotification(c *gin.Context){
 	var input transaction.TransactionNotificationInput
 
-	err := c.ShouldBindJSON(&input)
+	body, err := io.ReadAll(c.Request.Body)
 
 	if err != nil {
 		response := helper.APIResponse("Failed ro process notification", http.StatusBadRequest, "error", nil)
@@ -110,12 +141,79 @@ func (h *transactionHandler) GetNotification(c *gin.Context){
 		return
 	}
 
+	// endpoint lama tanpa :provider dipakai oleh Midtrans
+	input.Provider = c.Param("provider")
+	if input.Provider == "" {
+		input.Provider = payment.ProviderMidtrans
+	}
+	input.Header = c.Request.Header
+	input.Body = body
+
 	err = h.service.ProcessPayment(input)
+	if errors.Is(err, payment.ErrInvalidSignature) {
+		response := helper.APIResponse("Failed ro process notification", http.StatusUnauthorized, "error", nil)
+		c.JSON(http.StatusUnauthorized, response)
+
+		return
+	}
 	if err != nil {
 		response := helper.APIResponse("Failed ro process notification", http.StatusBadRequest, "error", nil)
 		c.JSON(http.StatusBadRequest, response)
 
 		return
 	}
-	c.JSON(http.StatusOK, input)
-}
\ No newline at end of file
+	response := helper.APIResponse("Notification processed", http.StatusOK, "success", nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// RefundTransaction refund yang diajukan pemilik campaign, amount kosong berarti refund penuh
+func (h *transactionHandler) RefundTransaction(c *gin.Context){
+	var inputID transaction.GetTransactionDetailInput
+
+	err := c.ShouldBindUri(&inputID)
+	if err != nil {
+		response := helper.APIResponse("Failed to refund transaction", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var input transaction.RefundTransactionInput
+
+	err = c.ShouldBindJSON(&input)
+	if err != nil {
+		errors := helper.FormatValidationError(err)
+		errorMessage := gin.H{"errors": errors}
+
+		response := helper.APIResponse("Failed to refund transaction", http.StatusUnprocessableEntity, "error", errorMessage)
+		c.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	input.User = c.MustGet("currentUser").(user.User)
+	input.RequestedBy = transaction.RefundRequestedByCreator
+
+	refund, err := h.service.RefundTransaction(inputID, input)
+	if err != nil {
+		response := helper.APIResponse(err.Error(), http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("Success to refund transaction", http.StatusOK, "success", transaction.FormatRefund(refund))
+	c.JSON(http.StatusOK, response)
+}
+
+// GetFeeReport laporan fee dari semua campaign milik user yang login
+func (h *transactionHandler) GetFeeReport(c *gin.Context){
+	currentUser := c.MustGet("currentUser").(user.User)
+
+	reports, err := h.service.GetFeeReport(currentUser.ID)
+	if err != nil {
+		response := helper.APIResponse("Failed to get fee report", http.StatusBadRequest, "error", nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helper.APIResponse("Fee report", http.StatusOK, "success", transaction.FormatFeeReports(reports))
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"bwastartup/api/upload"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// uploadError menentukan status dan data response ketika upload gagal.
+// Kesalahan validasi file dikembalikan beserta kode-nya, sisanya dianggap bad request
+func uploadError(err error) (int, gin.H) {
+	var validationError *upload.ValidationError
+	if errors.As(err, &validationError) {
+		data := gin.H{
+			"is_uploaded": false,
+			"errors":      []string{validationError.Message},
+			"code":        validationError.Code,
+		}
+		return http.StatusUnprocessableEntity, data
+	}
+
+	return http.StatusBadRequest, gin.H{"is_uploaded": false}
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"bwastartup/api/auth"
+	"bwastartup/api/upload"
 	"bwastartup/api/user"
 	"bwastartup/helper"
 	"fmt"
@@ -14,10 +15,11 @@ import (
 type userHandler struct {
 	userService user.Service
 	authService auth.Service
+	uploadService upload.Service
 }
 
-func NewUserHandler(userService user.Service,authService auth.Service) *userHandler{
-	return &userHandler{userService, authService}
+func NewUserHandler(userService user.Service,authService auth.Service, uploadService upload.Service) *userHandler{
+	return &userHandler{userService, authService, uploadService}
 }
 
 func (h *userHandler) RegisterUser(c *gin.Context){
@@ -165,14 +167,12 @@ func (h *userHandler) UploadAvatar(c *gin.Context){
 	currentUser := c.MustGet("currentUser").(user.User)
 	userID := currentUser.ID
 
-	path := fmt.Sprintf("images/%d-%s", userID, file.Filename)
-
-	err = c.SaveUploadedFile(file, path)
+	path, err := h.uploadService.SaveImage(file, fmt.Sprintf("images/avatars/%d", userID))
 	if err != nil {
-		data := gin.H{"is_uploaded":false}
-		response := helper.APIResponse("Failed to upload avatar image", http.StatusBadRequest, "error", data)
+		status, data := uploadError(err)
+		response := helper.APIResponse("Failed to upload avatar image", status, "error", data)
 
-		c.JSON(http.StatusBadRequest, response)
+		c.JSON(status, response)
 		return
 	}
 
@@ -0,0 +1,90 @@
+package ledger
+
+import (
+	"strconv"
+	"time"
+)
+
+// jenis akun ledger
+const (
+	// AccountBacker dana yang dikeluarkan backer (satu akun per user)
+	AccountBacker = "backer"
+	// AccountCampaignEscrow dana campaign yang ditahan platform (satu akun per campaign)
+	AccountCampaignEscrow = "campaign_escrow"
+	// AccountPlatformFees pendapatan fee platform (satu akun)
+	AccountPlatformFees = "platform_fees"
+	// AccountCreatorPayable dana yang harus dibayarkan ke creator (satu akun per user)
+	AccountCreatorPayable = "creator_payable"
+	// AccountGatewayFees biaya yang dipotong payment provider (satu akun)
+	AccountGatewayFees = "gateway_fees"
+	// AccountDisbursed dana yang sudah ditransfer keluar ke rekening creator (satu akun)
+	AccountDisbursed = "disbursed"
+)
+
+// jenis journal entry
+const (
+	EntryPayment        = "payment"
+	EntryRefund         = "refund"
+	EntryRefundReversal = "refund_reversal"
+	EntryFee            = "fee"
+	// EntryPayout payout disetujui, dana pindah dari escrow campaign ke creator payable
+	EntryPayout = "payout"
+	// EntryPayoutDisbursement dana sudah ditransfer ke rekening creator
+	EntryPayoutDisbursement = "payout_disbursement"
+	// EntryPayoutReversal payout gagal, dana kembali ke escrow campaign
+	EntryPayoutReversal = "payout_reversal"
+)
+
+type Account struct {
+	ID        int
+	Code      string
+	Type      string
+	OwnerID   int
+	CreatedAt time.Time
+}
+
+func (Account) TableName() string {
+	return "ledger_accounts"
+}
+
+// JournalEntry satu kejadian perpindahan dana. Entry tidak pernah diubah atau dihapus,
+// koreksi dicatat sebagai entry baru (misalnya refund_reversal). Reference unik per kejadian
+type JournalEntry struct {
+	ID            int
+	Type          string
+	Reference     string
+	TransactionID int
+	CampaignID    int
+	Description   string
+	CreatedAt     time.Time
+	Lines         []JournalLine
+}
+
+// JournalLine satu sisi entry, setiap line hanya berisi Debit atau Credit
+type JournalLine struct {
+	ID             int
+	JournalEntryID int
+	AccountID      int
+	Debit          int
+	Credit         int
+	CreatedAt      time.Time
+	Account        Account
+}
+
+// BalanceCheck hasil perbandingan CurrentAmount campaign dengan dana di ledger
+type BalanceCheck struct {
+	CampaignID    int
+	CurrentAmount int
+	LedgerAmount  int
+}
+
+func (b BalanceCheck) IsBalanced() bool {
+	return b.CurrentAmount == b.LedgerAmount
+}
+
+func accountCode(accountType string, ownerID int) string {
+	if ownerID == 0 {
+		return accountType
+	}
+	return accountType + ":" + strconv.Itoa(ownerID)
+}
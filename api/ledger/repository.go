@@ -0,0 +1,135 @@
+package ledger
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Repository sengaja tidak punya method update atau delete untuk journal entry
+type Repository interface {
+	FindOrCreateAccount(accountType string, ownerID int) (Account, error)
+	CreateEntry(entry JournalEntry) (JournalEntry, bool, error)
+	GetCampaignFunding(campaignID int) (int, error)
+	GetTransactionRefunded(transactionID int) (int, error)
+	FindUnbalancedEntries() ([]JournalEntry, error)
+	GetAccountBalance(accountType string, ownerID int) (int, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *repository {
+	return &repository{db}
+}
+
+func (r *repository) FindOrCreateAccount(accountType string, ownerID int) (Account, error) {
+	account := Account{Code: accountCode(accountType, ownerID), Type: accountType, OwnerID: ownerID}
+
+	err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&account).Error
+	if err != nil {
+		return account, err
+	}
+
+	err = r.db.Where("code = ?", account.Code).First(&account).Error
+	if err != nil {
+		return account, err
+	}
+	return account, nil
+}
+
+// CreateEntry menyimpan entry beserta line-nya. Mengembalikan false jika entry dengan reference yang sama
+// sudah pernah dicatat, sehingga kejadian yang diproses ulang tidak tercatat dua kali.
+// Tidak memakai ON DUPLICATE KEY UPDATE karena trigger append-only menolak update pada journal_entries
+func (r *repository) CreateEntry(entry JournalEntry) (JournalEntry, bool, error) {
+	var count int64
+
+	err := r.db.Model(&JournalEntry{}).Where("reference = ?", entry.Reference).Count(&count).Error
+	if err != nil {
+		return entry, false, err
+	}
+	if count > 0 {
+		return entry, false, nil
+	}
+
+	lines := entry.Lines
+	entry.Lines = nil
+
+	err = r.db.Create(&entry).Error
+	if err != nil {
+		return entry, false, err
+	}
+
+	for i := range lines {
+		lines[i].JournalEntryID = entry.ID
+	}
+
+	err = r.db.Omit("Account").Create(&lines).Error
+	if err != nil {
+		return entry, false, err
+	}
+
+	entry.Lines = lines
+	return entry, true, nil
+}
+
+// GetCampaignFunding dana bersih dari pembayaran dan refund yang masuk ke escrow campaign
+func (r *repository) GetCampaignFunding(campaignID int) (int, error) {
+	var amount int
+
+	err := r.db.Table("journal_lines").
+		Joins("JOIN journal_entries ON journal_entries.id = journal_lines.journal_entry_id").
+		Joins("JOIN ledger_accounts ON ledger_accounts.id = journal_lines.account_id").
+		Where("ledger_accounts.type = ? AND ledger_accounts.owner_id = ?", AccountCampaignEscrow, campaignID).
+		Where("journal_entries.type IN ?", []string{EntryPayment, EntryRefund, EntryRefundReversal}).
+		Select("COALESCE(SUM(journal_lines.credit - journal_lines.debit), 0)").
+		Scan(&amount).Error
+	if err != nil {
+		return 0, err
+	}
+	return amount, nil
+}
+
+// GetTransactionRefunded total refund transaksi yang sudah tercatat di ledger (dikurangi refund yang dibatalkan)
+func (r *repository) GetTransactionRefunded(transactionID int) (int, error) {
+	var amount int
+
+	err := r.db.Table("journal_lines").
+		Joins("JOIN journal_entries ON journal_entries.id = journal_lines.journal_entry_id").
+		Joins("JOIN ledger_accounts ON ledger_accounts.id = journal_lines.account_id").
+		Where("ledger_accounts.type = ? AND journal_entries.transaction_id = ?", AccountCampaignEscrow, transactionID).
+		Where("journal_entries.type IN ?", []string{EntryRefund, EntryRefundReversal}).
+		Select("COALESCE(SUM(journal_lines.debit - journal_lines.credit), 0)").
+		Scan(&amount).Error
+	if err != nil {
+		return 0, err
+	}
+	return amount, nil
+}
+
+// FindUnbalancedEntries entry yang total debit dan credit-nya tidak sama
+func (r *repository) FindUnbalancedEntries() ([]JournalEntry, error) {
+	var entries []JournalEntry
+
+	subQuery := r.db.Table("journal_lines").Select("journal_entry_id").Group("journal_entry_id").Having("SUM(debit) <> SUM(credit)")
+	err := r.db.Where("id IN (?)", subQuery).Order("id asc").Find(&entries).Error
+	if err != nil {
+		return entries, err
+	}
+	return entries, nil
+}
+
+// GetAccountBalance saldo akun (credit - debit), akun yang belum pernah dipakai bersaldo 0
+func (r *repository) GetAccountBalance(accountType string, ownerID int) (int, error) {
+	var amount int
+
+	err := r.db.Table("journal_lines").
+		Joins("JOIN ledger_accounts ON ledger_accounts.id = journal_lines.account_id").
+		Where("ledger_accounts.code = ?", accountCode(accountType, ownerID)).
+		Select("COALESCE(SUM(journal_lines.credit - journal_lines.debit), 0)").
+		Scan(&amount).Error
+	if err != nil {
+		return 0, err
+	}
+	return amount, nil
+}
@@ -0,0 +1,160 @@
+package ledger
+
+import (
+	"errors"
+	"strconv"
+)
+
+type Service interface {
+	PostPayment(transactionID int, campaignID int, backerID int, amount int) error
+	PostRefund(reference string, transactionID int, campaignID int, backerID int, amount int) error
+	PostRefundReversal(reference string, transactionID int, campaignID int, backerID int, amount int) error
+	PostFee(transactionID int, campaignID int, platformFee int, gatewayFee int) error
+	PostPayout(payoutID int, campaignID int, creatorID int, amount int) error
+	PostPayoutDisbursement(payoutID int, campaignID int, creatorID int, amount int) error
+	PostPayoutReversal(payoutID int, campaignID int, creatorID int, amount int) error
+	GetCampaignEscrowBalance(campaignID int) (int, error)
+	GetTransactionRefunded(transactionID int) (int, error)
+	CheckCampaignBalance(campaignID int, currentAmount int) (BalanceCheck, error)
+	FindUnbalancedEntries() ([]JournalEntry, error)
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(repository Repository) *service {
+	return &service{repository}
+}
+
+// PostPayment dana backer masuk ke escrow campaign
+func (s *service) PostPayment(transactionID int, campaignID int, backerID int, amount int) error {
+	reference := EntryPayment + ":" + strconv.Itoa(transactionID)
+	description := "Payment of transaction " + strconv.Itoa(transactionID)
+	return s.post(EntryPayment, reference, transactionID, campaignID, description, AccountBacker, backerID, AccountCampaignEscrow, campaignID, amount)
+}
+
+// PostRefund dana dikembalikan dari escrow campaign ke backer, reference unik per refund
+func (s *service) PostRefund(reference string, transactionID int, campaignID int, backerID int, amount int) error {
+	description := "Refund of transaction " + strconv.Itoa(transactionID)
+	return s.post(EntryRefund, reference, transactionID, campaignID, description, AccountCampaignEscrow, campaignID, AccountBacker, backerID, amount)
+}
+
+// PostRefundReversal membatalkan refund yang gagal di payment provider, dana kembali ke escrow campaign
+func (s *service) PostRefundReversal(reference string, transactionID int, campaignID int, backerID int, amount int) error {
+	description := "Reversal of failed refund of transaction " + strconv.Itoa(transactionID)
+	return s.post(EntryRefundReversal, reference, transactionID, campaignID, description, AccountBacker, backerID, AccountCampaignEscrow, campaignID, amount)
+}
+
+// PostFee fee platform dan fee payment provider diambil dari escrow campaign
+func (s *service) PostFee(transactionID int, campaignID int, platformFee int, gatewayFee int) error {
+	if platformFee+gatewayFee == 0 {
+		return nil
+	}
+
+	escrowAccount, err := s.repository.FindOrCreateAccount(AccountCampaignEscrow, campaignID)
+	if err != nil {
+		return err
+	}
+
+	entry := JournalEntry{}
+	entry.Type = EntryFee
+	entry.Reference = EntryFee + ":" + strconv.Itoa(transactionID)
+	entry.TransactionID = transactionID
+	entry.CampaignID = campaignID
+	entry.Description = "Fees of transaction " + strconv.Itoa(transactionID)
+	entry.Lines = []JournalLine{{AccountID: escrowAccount.ID, Debit: platformFee + gatewayFee}}
+
+	if platformFee > 0 {
+		platformAccount, err := s.repository.FindOrCreateAccount(AccountPlatformFees, 0)
+		if err != nil {
+			return err
+		}
+		entry.Lines = append(entry.Lines, JournalLine{AccountID: platformAccount.ID, Credit: platformFee})
+	}
+
+	if gatewayFee > 0 {
+		gatewayAccount, err := s.repository.FindOrCreateAccount(AccountGatewayFees, 0)
+		if err != nil {
+			return err
+		}
+		entry.Lines = append(entry.Lines, JournalLine{AccountID: gatewayAccount.ID, Credit: gatewayFee})
+	}
+
+	_, _, err = s.repository.CreateEntry(entry)
+	return err
+}
+
+// PostPayout dana payout yang disetujui dipindahkan dari escrow campaign ke creator payable
+func (s *service) PostPayout(payoutID int, campaignID int, creatorID int, amount int) error {
+	reference := EntryPayout + ":" + strconv.Itoa(payoutID)
+	description := "Approved payout " + strconv.Itoa(payoutID)
+	return s.post(EntryPayout, reference, 0, campaignID, description, AccountCampaignEscrow, campaignID, AccountCreatorPayable, creatorID, amount)
+}
+
+// PostPayoutDisbursement dana sudah diterima rekening creator
+func (s *service) PostPayoutDisbursement(payoutID int, campaignID int, creatorID int, amount int) error {
+	reference := EntryPayoutDisbursement + ":" + strconv.Itoa(payoutID)
+	description := "Disbursement of payout " + strconv.Itoa(payoutID)
+	return s.post(EntryPayoutDisbursement, reference, 0, campaignID, description, AccountCreatorPayable, creatorID, AccountDisbursed, 0, amount)
+}
+
+// PostPayoutReversal payout gagal ditransfer, dana dikembalikan ke escrow campaign
+func (s *service) PostPayoutReversal(payoutID int, campaignID int, creatorID int, amount int) error {
+	reference := EntryPayoutReversal + ":" + strconv.Itoa(payoutID)
+	description := "Reversal of failed payout " + strconv.Itoa(payoutID)
+	return s.post(EntryPayoutReversal, reference, 0, campaignID, description, AccountCreatorPayable, creatorID, AccountCampaignEscrow, campaignID, amount)
+}
+
+// GetCampaignEscrowBalance dana yang masih ditahan di escrow campaign
+func (s *service) GetCampaignEscrowBalance(campaignID int) (int, error) {
+	return s.repository.GetAccountBalance(AccountCampaignEscrow, campaignID)
+}
+
+func (s *service) GetTransactionRefunded(transactionID int) (int, error) {
+	return s.repository.GetTransactionRefunded(transactionID)
+}
+
+// CheckCampaignBalance membandingkan CurrentAmount campaign dengan dana bersih di escrow menurut ledger
+func (s *service) CheckCampaignBalance(campaignID int, currentAmount int) (BalanceCheck, error) {
+	ledgerAmount, err := s.repository.GetCampaignFunding(campaignID)
+	if err != nil {
+		return BalanceCheck{}, err
+	}
+	return BalanceCheck{campaignID, currentAmount, ledgerAmount}, nil
+}
+
+func (s *service) FindUnbalancedEntries() ([]JournalEntry, error) {
+	return s.repository.FindUnbalancedEntries()
+}
+
+// post mencatat entry dengan satu line debit dan satu line credit dengan jumlah yang sama
+func (s *service) post(entryType string, reference string, transactionID int, campaignID int, description string, debitType string, debitOwnerID int, creditType string, creditOwnerID int, amount int) error {
+	if amount <= 0 {
+		return errors.New("Ledger amount must be greater than zero")
+	}
+
+	debitAccount, err := s.repository.FindOrCreateAccount(debitType, debitOwnerID)
+	if err != nil {
+		return err
+	}
+
+	creditAccount, err := s.repository.FindOrCreateAccount(creditType, creditOwnerID)
+	if err != nil {
+		return err
+	}
+
+	entry := JournalEntry{}
+	entry.Type = entryType
+	entry.Reference = reference
+	entry.TransactionID = transactionID
+	entry.CampaignID = campaignID
+	entry.Description = description
+	entry.Lines = []JournalLine{
+		{AccountID: debitAccount.ID, Debit: amount},
+		{AccountID: creditAccount.ID, Credit: amount},
+	}
+
+	_, _, err = s.repository.CreateEntry(entry)
+	return err
+}
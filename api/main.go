@@ -1,15 +1,29 @@
 package main
 
 import (
+	"bwastartup/api/asset"
 	"bwastartup/api/auth"
 	"bwastartup/api/campaign"
+	"bwastartup/api/campaignupdate"
+	"bwastartup/api/category"
+	"bwastartup/api/comment"
+	"bwastartup/api/fee"
 	"bwastartup/api/handler"
+	"bwastartup/api/ledger"
+	"bwastartup/api/money"
+	"bwastartup/api/notification"
 	"bwastartup/api/payment"
+	"bwastartup/api/payout"
+	"bwastartup/api/reconciliation"
+	"bwastartup/api/recurring"
+	"bwastartup/api/storage"
 	"bwastartup/api/transaction"
+	"bwastartup/api/upload"
 	"bwastartup/api/user"
 	"bwastartup/helper"
 	webHandler "bwastartup/web/handler"
 	"fmt"
+	"html/template"
 	"log"
 	"net/http"
 	_ "net/http/pprof"
@@ -22,6 +36,7 @@ import (
 	"github.com/gin-contrib/sessions/cookie"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/joho/godotenv"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
@@ -124,29 +139,84 @@ func main() {
 		}
 	}
 
+	// .env bersifat opsional, di production konfigurasi diambil dari environment variable
+	godotenv.Load(".env")
+
+	storageConfig := storage.NewConfigFromEnv()
+	fileStorage, err := storage.New(storageConfig)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	// URL file di response API dan CMS dibuat lewat asset builder (origin publik/CDN dan signature)
+	assetConfig := asset.NewConfigFromEnv()
+	assetBuilder := asset.NewBuilder(assetConfig, fileStorage)
+	asset.SetDefault(assetBuilder)
+
+	money.SetDefaultLocale(money.LocaleFromEnv())
+	campaign.SetDefaultPledgeRules(campaign.NewPledgeRulesFromEnv())
+
+	paymentConfig := payment.NewConfigFromEnv()
+	paymentProviders, mockPaymentProvider := payment.NewProviders(paymentConfig)
+
 	userRepository := user.NewRepository(db)
+	categoryRepository := category.NewRepository(db)
 	campaignRepository := campaign.NewRepository(db)
 	transactionRepository := transaction.NewRepository(db)
+	notificationRepository := notification.NewRepository(db)
+	campaignUpdateRepository := campaignupdate.NewRepository(db)
+	commentRepository := comment.NewRepository(db)
+	transactionUnitOfWork := transaction.NewUnitOfWork(db)
+	payoutRepository := payout.NewRepository(db)
+	payoutUnitOfWork := payout.NewUnitOfWork(db)
+	reconciliationRepository := reconciliation.NewRepository(db)
+	recurringRepository := recurring.NewRepository(db)
 
 	userService := user.NewService(userRepository)
-	campaignService := campaign.NewService(campaignRepository)
+	categoryService := category.NewService(categoryRepository)
+	campaignService := campaign.NewService(campaignRepository, categoryRepository)
 	authService := auth.NewService()
-	paymentService := payment.NewService()
-	transactionService := transaction.NewService(transactionRepository, campaignRepository, paymentService)
-
-	userHandler := handler.NewUserHandler(userService, authService)
-	campaignHandler := handler.NewCampaignHandler(campaignService)
+	paymentService, err := payment.NewService(paymentProviders, paymentConfig.Provider)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	transactionCodeGenerator := transaction.NewCodeGenerator(transaction.CodePrefixFromEnv())
+	feeService := fee.NewService(fee.NewPolicyFromEnv())
+	rateProvider := money.NewFileRateProvider(money.RateFileFromEnv())
+	transactionService := transaction.NewService(transactionRepository, campaignRepository, paymentService, transactionUnitOfWork, transactionCodeGenerator, feeService, rateProvider)
+	notificationService := notification.NewService(notificationRepository)
+	campaignUpdateService := campaignupdate.NewService(campaignUpdateRepository, campaignRepository, transactionRepository, notificationService)
+	commentService := comment.NewService(commentRepository, campaignRepository, transactionRepository)
+	uploadService := upload.NewService(fileStorage, upload.MaxFileSizeFromEnv())
+	ledgerService := ledger.NewService(ledger.NewRepository(db))
+	reconciliationService := reconciliation.NewService(reconciliationRepository, transactionRepository)
+	payoutService := payout.NewService(payoutRepository, campaignRepository, ledgerService, payoutUnitOfWork, payout.NewDisburser(payout.NewConfigFromEnv()))
+	recurringService := recurring.NewService(recurringRepository, transactionService, notificationService, recurring.NewDunningConfigFromEnv())
+
+	userHandler := handler.NewUserHandler(userService, authService, uploadService)
+	campaignHandler := handler.NewCampaignHandler(campaignService, uploadService)
+	categoryHandler := handler.NewCategoryHandler(categoryService, campaignService)
 	transactionHandler := handler.NewTransactionHandler(transactionService)
+	campaignUpdateHandler := handler.NewCampaignUpdateHandler(campaignUpdateService)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	commentHandler := handler.NewCommentHandler(commentService)
+	payoutHandler := handler.NewPayoutHandler(payoutService)
+	recurringHandler := handler.NewRecurringHandler(recurringService)
 	
-	userWebHandler := webHandler.NewUserHandler(userService)
-	campaignWebHandler := webHandler.NewCampaignHandler(campaignService, userService)
+	userWebHandler := webHandler.NewUserHandler(userService, uploadService)
+	campaignWebHandler := webHandler.NewCampaignHandler(campaignService, userService, categoryService, uploadService)
+	categoryWebHandler := webHandler.NewCategoryHandler(categoryService)
 	transactionWebHandler := webHandler.NewTransactionHandler(transactionService)
 	sessionWebHandler := webHandler.NewSessionHandler(userService)
+	commentWebHandler := webHandler.NewCommentHandler(commentService)
+	payoutWebHandler := webHandler.NewPayoutHandler(payoutService)
+	reconciliationWebHandler := webHandler.NewReconciliationHandler(reconciliationService)
+	recurringWebHandler := webHandler.NewRecurringHandler(recurringService)
 
 	router := gin.Default()
 	config := cors.DefaultConfig()
   config.AllowOrigins = []string{"*"}
-	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE"}
+	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
 	config.AllowCredentials = true
 	config.AllowHeaders = []string{"Access-Control-Allow-Headers", "access-control-allow-origin, access-control-allow-headers", "Content-Type", "X-XSRF-TOKEN", "Accept", "Origin", "X-Requested-With", "Authorization", "ngrok-skip-browser-warning"}
 	router.SetTrustedProxies([]string{"192.168.1.2"})
@@ -157,13 +227,25 @@ func main() {
 	cookieStore := cookie.NewStore([]byte(auth.SECRET_KEY))
 	router.Use(sessions.Sessions("mrsastartup", cookieStore))
 
+	router.SetFuncMap(templateFuncs)
 	router.LoadHTMLGlob("../web/templates/**/*")
 	router.HTMLRender = loadTemplates("../web/templates")
 	
-	router.Static("/images", "../images")
+	// URL dengan signature harus dicek dulu, jadi tidak bisa langsung router.Static
+	if storageConfig.Driver == "local" && !assetBuilder.Signed() {
+		router.Static("/images", filepath.Join(storageConfig.LocalDir, "images"))
+	} else {
+		router.GET("/images/*filepath", handler.NewFileHandler(fileStorage, assetBuilder).Serve)
+	}
 	router.Static("/css", "../web/assets/css")
 	router.Static("/js", "../web/assets/js")
 	router.Static("/image", "../web/assets/image")
+	if mockPaymentProvider != nil {
+		mockPaymentHandler := handler.NewMockPaymentHandler(mockPaymentProvider)
+		router.GET("/payments/mock/checkout/:order_id", mockPaymentHandler.Checkout)
+		router.POST("/payments/mock/checkout/:order_id", mockPaymentHandler.Complete)
+	}
+
 	api := router.Group("/api/v1")
 
 	api.POST("/users", userHandler.RegisterUser)
@@ -176,14 +258,52 @@ func main() {
 
 	api.GET("/campaigns",campaignHandler.GetCampaigns)
 	api.GET("/campaigns/:id",campaignHandler.GetCampaign)
+	api.GET("/campaigns/slug/:slug",campaignHandler.GetCampaignBySlug)
 	api.POST("/campaigns", authMiddleware(authService, userService),campaignHandler.CreateCampaign)
 	api.PUT("/campaigns/:id", authMiddleware(authService, userService),campaignHandler.UpdateCampaign)
 	api.POST("/campaign-images", authMiddleware(authService, userService),campaignHandler.UploadImage)
+	api.PATCH("/campaign-images/:id", authMiddleware(authService, userService),campaignHandler.UpdateImage)
+	api.DELETE("/campaign-images/:id", authMiddleware(authService, userService),campaignHandler.DeleteImage)
+	api.PUT("/campaigns/:id/images/order", authMiddleware(authService, userService),campaignHandler.ReorderImages)
+
+	api.GET("/campaigns/:id/updates", optionalAuthMiddleware(authService, userService), campaignUpdateHandler.GetCampaignUpdates)
+	api.POST("/campaigns/:id/updates", authMiddleware(authService, userService), campaignUpdateHandler.CreateCampaignUpdate)
+
+	api.GET("/campaigns/:id/comments", commentHandler.GetCampaignComments)
+	api.POST("/campaigns/:id/comments", authMiddleware(authService, userService), commentHandler.CreateComment)
+	api.PUT("/comments/:id", authMiddleware(authService, userService), commentHandler.UpdateComment)
+	api.DELETE("/comments/:id", authMiddleware(authService, userService), commentHandler.DeleteComment)
+	api.POST("/comments/:id/flag", authMiddleware(authService, userService), commentHandler.FlagComment)
+
+	api.GET("/notifications", authMiddleware(authService, userService), notificationHandler.GetNotifications)
+	api.PUT("/notifications/:id/read", authMiddleware(authService, userService), notificationHandler.MarkAsRead)
+
+	api.GET("/categories", categoryHandler.GetCategories)
+	api.GET("/categories/:slug/campaigns", categoryHandler.GetCategoryCampaigns)
 
 	api.GET("/campaigns/:id/transactions", authMiddleware(authService, userService), transactionHandler.GetCampaignTransactions)
+	api.GET("/campaigns/:id/supporters", transactionHandler.GetCampaignSupporters)
 	api.GET("/transactions", authMiddleware(authService, userService), transactionHandler.GetUserTransactions)
 	api.POST("/transactions", authMiddleware(authService, userService), transactionHandler.CreateTransaction)
+	api.GET("/reports/fees", authMiddleware(authService, userService), transactionHandler.GetFeeReport)
+	api.POST("/transactions/:id/refunds", authMiddleware(authService, userService), transactionHandler.RefundTransaction)
 	api.POST("/transactions/notification", transactionHandler.GetNotification)
+	api.POST("/transactions/notification/:provider", transactionHandler.GetNotification)
+
+	api.GET("/payouts", authMiddleware(authService, userService), payoutHandler.GetPayouts)
+	api.POST("/payouts", authMiddleware(authService, userService), payoutHandler.RequestPayout)
+	api.GET("/payouts/balances", authMiddleware(authService, userService), payoutHandler.GetBalances)
+	api.GET("/payouts/bank-accounts", authMiddleware(authService, userService), payoutHandler.GetBankAccounts)
+	api.POST("/payouts/bank-accounts", authMiddleware(authService, userService), payoutHandler.CreateBankAccount)
+	api.DELETE("/payouts/bank-accounts/:id", authMiddleware(authService, userService), payoutHandler.DeleteBankAccount)
+	api.POST("/payouts/notification/:provider", payoutHandler.GetNotification)
+
+	api.GET("/recurring-pledges", authMiddleware(authService, userService), recurringHandler.GetPledges)
+	api.POST("/recurring-pledges", authMiddleware(authService, userService), recurringHandler.CreatePledge)
+	api.GET("/recurring-pledges/revenue", authMiddleware(authService, userService), recurringHandler.GetRevenue)
+	api.POST("/recurring-pledges/:id/pause", authMiddleware(authService, userService), recurringHandler.PausePledge)
+	api.POST("/recurring-pledges/:id/resume", authMiddleware(authService, userService), recurringHandler.ResumePledge)
+	api.POST("/recurring-pledges/:id/cancel", authMiddleware(authService, userService), recurringHandler.CancelPledge)
 
 	router.GET("/users", authAdminMiddleware(), userWebHandler.Index)
 	router.GET("/users/new", userWebHandler.New)
@@ -204,12 +324,37 @@ func main() {
 	router.GET("/campaigns/edit/:id", authAdminMiddleware(),campaignWebHandler.Edit)
 	router.POST("/campaigns/update/:id", authAdminMiddleware(),campaignWebHandler.Update)
 	router.GET("/campaigns/show/:id", authAdminMiddleware(),campaignWebHandler.Show)
+	router.POST("/campaigns/images/primary/:id", authAdminMiddleware(),campaignWebHandler.SetPrimaryImage)
+	router.POST("/campaigns/images/caption/:id", authAdminMiddleware(),campaignWebHandler.UpdateImageCaption)
+	router.POST("/campaigns/images/move/:id", authAdminMiddleware(),campaignWebHandler.MoveImage)
+	router.POST("/campaigns/images/delete/:id", authAdminMiddleware(),campaignWebHandler.DeleteImage)
+	router.GET("/categories", authAdminMiddleware(), categoryWebHandler.Index)
+	router.GET("/categories/new", authAdminMiddleware(), categoryWebHandler.New)
+	router.POST("/categories", authAdminMiddleware(), categoryWebHandler.Create)
+	router.GET("/categories/edit/:id", authAdminMiddleware(), categoryWebHandler.Edit)
+	router.POST("/categories/update/:id", authAdminMiddleware(), categoryWebHandler.Update)
 	router.GET("/transactions", authAdminMiddleware(), transactionWebHandler.Index)
+	router.GET("/reports/fees", authAdminMiddleware(), transactionWebHandler.FeeReport)
+	router.GET("/recurring", authAdminMiddleware(), recurringWebHandler.Index)
+	router.GET("/transactions/refund/:id", authAdminMiddleware(), transactionWebHandler.NewRefund)
+	router.POST("/transactions/refund/:id", authAdminMiddleware(), transactionWebHandler.CreateRefund)
+	router.GET("/payouts", authAdminMiddleware(), payoutWebHandler.Index)
+	router.POST("/payouts/approve/:id", authAdminMiddleware(), payoutWebHandler.Approve)
+	router.POST("/payouts/reject/:id", authAdminMiddleware(), payoutWebHandler.Reject)
+	router.GET("/reconciliation", authAdminMiddleware(), reconciliationWebHandler.Index)
+	router.POST("/reconciliation/resolve/:id", authAdminMiddleware(), reconciliationWebHandler.Resolve)
+	router.GET("/comments", authAdminMiddleware(), commentWebHandler.Index)
+	router.POST("/comments/hide/:id", authAdminMiddleware(), commentWebHandler.Hide)
+	router.POST("/comments/show/:id", authAdminMiddleware(), commentWebHandler.Show)
 
 	router.GET("/login",sessionWebHandler.New)
 	router.POST("/session",sessionWebHandler.Create)
 	router.GET("/logout",sessionWebHandler.Destroy)
 
+	// transaksi pending yang ditinggalkan backer dipastikan statusnya ke provider lalu di-expire
+	transaction.NewExpirySweeper(transactionService, transaction.NewExpiryConfigFromEnv()).Start()
+	recurring.NewScheduler(recurringService, recurring.NewSchedulerConfigFromEnv()).Start()
+
 	log.Fatal(router.Run(":8080"))
 
 
@@ -279,19 +424,58 @@ func authMiddleware(authService auth.Service, userService user.Service) gin.Hand
 // ambil user dari db berdasarkan user_id lewat service
 // kalau user ada set context isinya user
 
+// optionalAuthMiddleware sama seperti authMiddleware tetapi request tanpa token (atau token tidak valid)
+// tetap diteruskan ke handler, hanya saja currentUser tidak di-set
+func optionalAuthMiddleware(authService auth.Service, userService user.Service) gin.HandlerFunc {
+	return func (c *gin.Context){
+		authHeader := c.GetHeader("Authorization")
+
+		arrayToken := strings.Split(authHeader, " ")
+		if !strings.Contains(authHeader, "Bearer") || len(arrayToken) != 2 {
+			return
+		}
+
+		token, err := authService.ValidateToken(arrayToken[1])
+		if err != nil {
+			return
+		}
+
+		claim, ok := token.Claims.(jwt.MapClaims)
+		if !ok || !token.Valid {
+			return
+		}
+
+		userID, ok := claim["user_id"].(float64)
+		if !ok {
+			return
+		}
+
+		user, err := userService.GetUserByID(int(userID))
+		if err != nil {
+			return
+		}
+		c.Set("currentUser",user)
+	}
+}
+
 func authAdminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		session := sessions.Default(c)
 
-		// userID
-		userIDSession := session.Get("userID")
-		if userIDSession == nil {
+		// userID, redirect saja tidak menghentikan handler berikutnya sehingga harus di-abort
+		userID, ok := session.Get("userID").(int)
+		if !ok || userID == 0 {
 			c.Redirect(http.StatusFound, "/login")
+			c.Abort()
 			return
 		}
 	}
 }
 
+var templateFuncs = template.FuncMap{
+	"assetURL": asset.URL,
+}
+
 func loadTemplates(templatesDir string) multitemplate.Renderer {
   r := multitemplate.NewRenderer()
 
@@ -310,7 +494,7 @@ func loadTemplates(templatesDir string) multitemplate.Renderer {
     layoutCopy := make([]string, len(layouts))
     copy(layoutCopy, layouts)
     files := append(layoutCopy, include)
-    r.AddFromFiles(filepath.Base(include), files...)
+    r.AddFromFilesFuncs(filepath.Base(include), templateFuncs, files...)
   }
   return r
 }
\ No newline at end of file
@@ -0,0 +1,61 @@
+package money
+
+import (
+	"sort"
+	"strings"
+)
+
+const DefaultCurrency = "IDR"
+
+// Currency mata uang yang didukung. Amount selalu disimpan sebagai integer dalam satuan terkecil (minor unit),
+// Exponent jumlah digit pecahannya (USD 2: 1050 berarti $10.50)
+type Currency struct {
+	Code     string
+	Exponent int
+	Symbol   string
+	// MinimumPledge nominal dukungan terkecil dalam minor unit
+	MinimumPledge int
+}
+
+// rupiah memakai exponent 0 karena sejak awal amount disimpan dalam rupiah utuh (sen tidak dipakai)
+var currencies = map[string]Currency{
+	"IDR": {"IDR", 0, "Rp", 10000},
+	"USD": {"USD", 2, "US$", 100},
+	"EUR": {"EUR", 2, "€", 100},
+	"SGD": {"SGD", 2, "S$", 100},
+	"MYR": {"MYR", 2, "RM", 500},
+	"JPY": {"JPY", 0, "¥", 100},
+}
+
+// NormalizeCurrency kode mata uang dalam huruf besar, kosong berarti DefaultCurrency
+func NormalizeCurrency(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return DefaultCurrency
+	}
+	return code
+}
+
+func IsSupported(code string) bool {
+	_, ok := currencies[NormalizeCurrency(code)]
+	return ok
+}
+
+// LookupCurrency mata uang yang tidak dikenal dianggap DefaultCurrency
+func LookupCurrency(code string) Currency {
+	currency, ok := currencies[NormalizeCurrency(code)]
+	if !ok {
+		return currencies[DefaultCurrency]
+	}
+	return currency
+}
+
+// SupportedCurrencies kode semua mata uang yang didukung, urut abjad
+func SupportedCurrencies() []string {
+	codes := []string{}
+	for code := range currencies {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
@@ -0,0 +1,92 @@
+package money
+
+import (
+	"math"
+	"math/big"
+	"os"
+
+	"github.com/leekchan/accounting"
+)
+
+// Money amount dalam minor unit beserta mata uangnya
+type Money struct {
+	Amount   int
+	Currency string
+}
+
+func New(amount int, currency string) Money {
+	return Money{amount, NormalizeCurrency(currency)}
+}
+
+// Locale pemisah ribuan dan desimal yang dipakai saat menampilkan amount
+type Locale struct {
+	Thousand string
+	Decimal  string
+}
+
+var locales = map[string]Locale{
+	"id": {".", ","},
+	"en": {",", "."},
+}
+
+var defaultLocale = "id"
+
+// SetDefaultLocale locale yang dipakai Format, locale yang tidak dikenal diabaikan
+func SetDefaultLocale(locale string) {
+	if _, ok := locales[locale]; ok {
+		defaultLocale = locale
+	}
+}
+
+// LocaleFromEnv APP_LOCALE: id (default) atau en
+func LocaleFromEnv() string {
+	locale := os.Getenv("APP_LOCALE")
+	if locale == "" {
+		return "id"
+	}
+	return locale
+}
+
+// Format menampilkan amount dengan simbol mata uang sesuai locale default, misalnya Rp10.000 atau US$12,50
+func (m Money) Format() string {
+	return m.FormatLocale(defaultLocale)
+}
+
+func (m Money) FormatLocale(locale string) string {
+	format, ok := locales[locale]
+	if !ok {
+		format = locales[defaultLocale]
+	}
+
+	currency := LookupCurrency(m.Currency)
+	ac := accounting.Accounting{Symbol: currency.Symbol, Precision: currency.Exponent, Thousand: format.Thousand, Decimal: format.Decimal}
+	return ac.FormatMoneyBigRat(big.NewRat(int64(m.Amount), pow10(currency.Exponent)))
+}
+
+// Convert mengubah amount ke mata uang lain dengan rate (nilai 1 unit utuh mata uang asal dalam mata uang tujuan),
+// hasil dibulatkan ke minor unit terdekat
+func (m Money) Convert(to string, rate float64) Money {
+	from := LookupCurrency(m.Currency)
+	target := LookupCurrency(to)
+
+	amount := float64(m.Amount) * rate * float64(pow10(target.Exponent)) / float64(pow10(from.Exponent))
+	return New(int(math.Round(amount)), target.Code)
+}
+
+// Major amount dalam satuan utuh untuk API yang memakai desimal (misalnya Xendit), US$10.50 menjadi 10.5
+func (m Money) Major() float64 {
+	return float64(m.Amount) / float64(pow10(LookupCurrency(m.Currency).Exponent))
+}
+
+// FromMajor kebalikan dari Major, dibulatkan ke minor unit terdekat
+func FromMajor(value float64, currency string) Money {
+	return New(int(math.Round(value*float64(pow10(LookupCurrency(currency).Exponent)))), currency)
+}
+
+func pow10(exponent int) int64 {
+	result := int64(1)
+	for i := 0; i < exponent; i++ {
+		result *= 10
+	}
+	return result
+}
@@ -0,0 +1,118 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+var ErrRateNotFound = errors.New("Exchange rate not found")
+
+// Rate nilai 1 unit utuh mata uang From dalam mata uang To, disimpan sebagai snapshot di transaksi
+type Rate struct {
+	From      string
+	To        string
+	Value     float64
+	Source    string
+	UpdatedAt time.Time
+}
+
+// RateProvider sumber kurs mata uang
+type RateProvider interface {
+	Rate(from string, to string) (Rate, error)
+}
+
+// RateFileFromEnv EXCHANGE_RATE_FILE, default exchange_rates.json di working directory
+func RateFileFromEnv() string {
+	path := os.Getenv("EXCHANGE_RATE_FILE")
+	if path == "" {
+		return "exchange_rates.json"
+	}
+	return path
+}
+
+// rateFile isi file kurs: rates berisi nilai 1 unit base dalam setiap mata uang
+type rateFile struct {
+	Base      string             `json:"base"`
+	UpdatedAt time.Time          `json:"updated_at"`
+	Rates     map[string]float64 `json:"rates"`
+}
+
+// fileRateProvider kurs dari file json untuk development dan deployment sederhana,
+// file dibaca ulang jika berubah sehingga kurs bisa diperbarui tanpa restart
+type fileRateProvider struct {
+	path string
+
+	mu         sync.Mutex
+	modifiedAt time.Time
+	rates      rateFile
+}
+
+func NewFileRateProvider(path string) *fileRateProvider {
+	return &fileRateProvider{path: path}
+}
+
+func (p *fileRateProvider) Rate(from string, to string) (Rate, error) {
+	from = NormalizeCurrency(from)
+	to = NormalizeCurrency(to)
+
+	if from == to {
+		return Rate{From: from, To: to, Value: 1, Source: "identity", UpdatedAt: time.Now()}, nil
+	}
+
+	rates, err := p.load()
+	if err != nil {
+		return Rate{}, err
+	}
+
+	fromRate, ok := rates.rate(from)
+	if !ok {
+		return Rate{}, ErrRateNotFound
+	}
+	toRate, ok := rates.rate(to)
+	if !ok {
+		return Rate{}, ErrRateNotFound
+	}
+
+	return Rate{From: from, To: to, Value: toRate / fromRate, Source: "file:" + p.path, UpdatedAt: rates.UpdatedAt}, nil
+}
+
+func (r rateFile) rate(currency string) (float64, bool) {
+	if currency == NormalizeCurrency(r.Base) {
+		return 1, true
+	}
+	rate, ok := r.Rates[currency]
+	return rate, ok && rate > 0
+}
+
+func (p *fileRateProvider) load() (rateFile, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info, err := os.Stat(p.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return rateFile{}, ErrRateNotFound
+	}
+	if err != nil {
+		return rateFile{}, err
+	}
+
+	if !info.ModTime().Equal(p.modifiedAt) {
+		content, err := os.ReadFile(p.path)
+		if err != nil {
+			return rateFile{}, err
+		}
+
+		var rates rateFile
+		err = json.Unmarshal(content, &rates)
+		if err != nil {
+			return rateFile{}, err
+		}
+
+		p.rates = rates
+		p.modifiedAt = info.ModTime()
+	}
+	return p.rates, nil
+}
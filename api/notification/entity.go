@@ -0,0 +1,15 @@
+package notification
+
+import "time"
+
+type Notification struct {
+	ID        int
+	UserID    int
+	Type      string
+	Title     string
+	Message   string
+	Link      string
+	ReadAt    *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,34 @@
+package notification
+
+import "time"
+
+type NotificationFormatter struct {
+	ID        int       `json:"id"`
+	Type      string    `json:"type"`
+	Title     string    `json:"title"`
+	Message   string    `json:"message"`
+	Link      string    `json:"link"`
+	IsRead    bool      `json:"is_read"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func FormatNotification(notification Notification) NotificationFormatter {
+	formatter := NotificationFormatter{}
+	formatter.ID = notification.ID
+	formatter.Type = notification.Type
+	formatter.Title = notification.Title
+	formatter.Message = notification.Message
+	formatter.Link = notification.Link
+	formatter.IsRead = notification.ReadAt != nil
+	formatter.CreatedAt = notification.CreatedAt
+	return formatter
+}
+
+func FormatNotifications(notifications []Notification) []NotificationFormatter {
+	notificationsFormatter := []NotificationFormatter{}
+
+	for _, notification := range notifications {
+		notificationsFormatter = append(notificationsFormatter, FormatNotification(notification))
+	}
+	return notificationsFormatter
+}
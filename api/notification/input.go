@@ -0,0 +1,16 @@
+package notification
+
+import "bwastartup/api/user"
+
+type GetNotificationInput struct {
+	ID   int `uri:"id" binding:"required"`
+	User user.User
+}
+
+// NotificationInput isi notifikasi yang akan dikirim ke banyak user sekaligus
+type NotificationInput struct {
+	Type    string
+	Title   string
+	Message string
+	Link    string
+}
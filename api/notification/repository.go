@@ -0,0 +1,62 @@
+package notification
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	SaveMany(notifications []Notification) ([]Notification, error)
+	FindByUserID(userID int) ([]Notification, error)
+	FindByID(ID int) (Notification, error)
+	MarkAsRead(ID int, readAt time.Time) (bool, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *repository {
+	return &repository{db}
+}
+
+func (r *repository) SaveMany(notifications []Notification) ([]Notification, error) {
+	if len(notifications) == 0 {
+		return notifications, nil
+	}
+
+	err := r.db.CreateInBatches(&notifications, 100).Error
+	if err != nil {
+		return notifications, err
+	}
+	return notifications, nil
+}
+
+func (r *repository) FindByUserID(userID int) ([]Notification, error) {
+	var notifications []Notification
+
+	err := r.db.Where("user_id = ?", userID).Order("id desc").Find(&notifications).Error
+	if err != nil {
+		return notifications, err
+	}
+	return notifications, nil
+}
+
+func (r *repository) FindByID(ID int) (Notification, error) {
+	var notification Notification
+
+	err := r.db.Where("id = ?", ID).Find(&notification).Error
+	if err != nil {
+		return notification, err
+	}
+	return notification, nil
+}
+
+func (r *repository) MarkAsRead(ID int, readAt time.Time) (bool, error) {
+	err := r.db.Model(&Notification{}).Where("id = ? AND read_at IS NULL", ID).Update("read_at", readAt).Error
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
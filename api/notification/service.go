@@ -0,0 +1,66 @@
+package notification
+
+import (
+	"errors"
+	"time"
+)
+
+type Service interface {
+	NotifyUsers(userIDs []int, input NotificationInput) ([]Notification, error)
+	GetNotificationsByUserID(userID int) ([]Notification, error)
+	MarkAsRead(input GetNotificationInput) error
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(repository Repository) *service {
+	return &service{repository}
+}
+
+func (s *service) NotifyUsers(userIDs []int, input NotificationInput) ([]Notification, error) {
+	notifications := []Notification{}
+
+	for _, userID := range userIDs {
+		notification := Notification{}
+		notification.UserID = userID
+		notification.Type = input.Type
+		notification.Title = input.Title
+		notification.Message = input.Message
+		notification.Link = input.Link
+
+		notifications = append(notifications, notification)
+	}
+
+	newNotifications, err := s.repository.SaveMany(notifications)
+	if err != nil {
+		return newNotifications, err
+	}
+	return newNotifications, nil
+}
+
+func (s *service) GetNotificationsByUserID(userID int) ([]Notification, error) {
+	notifications, err := s.repository.FindByUserID(userID)
+	if err != nil {
+		return notifications, err
+	}
+	return notifications, nil
+}
+
+func (s *service) MarkAsRead(input GetNotificationInput) error {
+	notification, err := s.repository.FindByID(input.ID)
+	if err != nil {
+		return err
+	}
+
+	if notification.ID == 0 || notification.UserID != input.User.ID {
+		return errors.New("No notification found with that ID")
+	}
+
+	_, err = s.repository.MarkAsRead(notification.ID, time.Now())
+	if err != nil {
+		return err
+	}
+	return nil
+}
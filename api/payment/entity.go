@@ -1,6 +1,47 @@
 package payment
 
 type Transaction struct {
-	ID     int
+	ID int
+	// OrderID kode transaksi yang dikirim ke provider sebagai order/reference ID
+	OrderID string
+	// Amount dalam minor unit Currency (lihat package money)
+	Amount      int
+	Currency    string
+	Description string
+	// SavePaymentMethod meminta provider menyimpan metode pembayaran backer untuk tagihan recurring berikutnya
+	SavePaymentMethod bool
+}
+
+// status pembayaran yang sudah diseragamkan dari semua provider
+const (
+	StatusPending   = "pending"
+	StatusPaid      = "paid"
+	StatusCancelled = "cancelled"
+	StatusExpired   = "expired"
+	StatusRefunded  = "refunded"
+)
+
+// Charge hasil pembuatan tagihan di provider
+type Charge struct {
+	PaymentURL string
+	Reference  string
+}
+
+// Notification status pembayaran dari webhook atau status API provider
+type Notification struct {
+	Provider string
+	// EventKey identitas unik kejadian dari provider, notifikasi yang dikirim ulang punya EventKey yang sama
+	EventKey string
+	// OrderID kosong berarti event tidak berhubungan dengan transaksi dan cukup diabaikan
+	OrderID string
+	// Status salah satu dari Status*, kosong jika tidak mengubah status transaksi
+	Status string
 	Amount int
-}
\ No newline at end of file
+	// GatewayFee biaya yang dipotong provider, 0 jika provider tidak mengirimkannya (misalnya Midtrans)
+	GatewayFee int
+	// RawStatus dan StatusCode status asli dari provider, disimpan untuk log
+	RawStatus  string
+	StatusCode string
+	// PaymentToken metode pembayaran tersimpan (hanya jika diminta lewat SavePaymentMethod), dipakai untuk ChargeSaved
+	PaymentToken string
+}
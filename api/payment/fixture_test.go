@@ -0,0 +1,107 @@
+package payment
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// fixtureRoute response rekaman untuk satu endpoint provider
+type fixtureRoute struct {
+	Status  int
+	Fixture string
+}
+
+// recordedRequest request yang diterima fixture server, dipakai untuk memeriksa kontrak request ke provider
+type recordedRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+func (r recordedRequest) Form(t *testing.T) url.Values {
+	t.Helper()
+
+	form, err := url.ParseQuery(string(r.Body))
+	if err != nil {
+		t.Fatalf("parse form body: %v", err)
+	}
+	return form
+}
+
+// fixtureServer server httptest yang membalas request dengan file di testdata sesuai "METHOD path"
+type fixtureServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []recordedRequest
+}
+
+func newFixtureServer(t *testing.T, provider string, routes map[string]fixtureRoute) *fixtureServer {
+	t.Helper()
+
+	server := &fixtureServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		server.mu.Lock()
+		server.requests = append(server.requests, recordedRequest{r.Method, r.URL.Path, r.URL.Query(), r.Header.Clone(), body})
+		server.mu.Unlock()
+
+		route, ok := routes[r.Method+" "+r.URL.Path]
+		if !ok {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+
+		status := route.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write(readFixture(t, provider, route.Fixture))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// Requests semua request yang diterima, sesuai urutan
+func (s *fixtureServer) Requests() []recordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]recordedRequest{}, s.requests...)
+}
+
+// LastRequest request terakhir ke method dan path tersebut
+func (s *fixtureServer) LastRequest(t *testing.T, method string, path string) recordedRequest {
+	t.Helper()
+
+	requests := s.Requests()
+	for i := len(requests) - 1; i >= 0; i-- {
+		if requests[i].Method == method && requests[i].Path == path {
+			return requests[i]
+		}
+	}
+	t.Fatalf("no %s %s request recorded", method, path)
+	return recordedRequest{}
+}
+
+func readFixture(t *testing.T, provider string, name string) []byte {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join("testdata", provider, name))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	return content
+}
@@ -0,0 +1,193 @@
+package payment
+
+import (
+	"bwastartup/api/money"
+	"bwastartup/api/user"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+)
+
+const ProviderMidtrans = "midtrans"
+
+type midtransProvider struct {
+	serverKey    string
+	environment  midtrans.EnvironmentType
+	verifyStatus bool
+}
+
+func NewMidtransProvider(serverKey string, isProduction bool, verifyStatus bool) *midtransProvider {
+	environment := midtrans.Sandbox
+	if isProduction {
+		environment = midtrans.Production
+	}
+	return &midtransProvider{serverKey, environment, verifyStatus}
+}
+
+// midtransNotification isi body webhook dan response status API Midtrans
+type midtransNotification struct {
+	TransactionID     string `json:"transaction_id"`
+	TransactionStatus string `json:"transaction_status"`
+	OrderID           string `json:"order_id"`
+	PaymentType       string `json:"payment_type"`
+	FraudStatus       string `json:"fraud_status"`
+	StatusCode        string `json:"status_code"`
+	GrossAmount       string `json:"gross_amount"`
+	SignatureKey      string `json:"signature_key"`
+}
+
+func (p *midtransProvider) Name() string {
+	return ProviderMidtrans
+}
+
+func (p *midtransProvider) CreateCharge(transaction Transaction, user user.User) (Charge, error) {
+	// Snap hanya menerima rupiah
+	if money.NormalizeCurrency(transaction.Currency) != "IDR" {
+		return Charge{}, errors.New("Midtrans only supports IDR payments")
+	}
+
+	client := snap.Client{}
+	client.New(p.serverKey, p.environment)
+
+	snapReq := &snap.Request{
+		CustomerDetail: &midtrans.CustomerDetails{
+			Email: user.Email,
+			FName: user.Name,
+		},
+		TransactionDetails: midtrans.TransactionDetails{
+			OrderID:  transaction.OrderID,
+			GrossAmt: int64(transaction.Amount),
+		},
+	}
+
+	snapResp, midtransErr := client.CreateTransaction(snapReq)
+	if midtransErr != nil {
+		return Charge{}, midtransErr
+	}
+	return Charge{PaymentURL: snapResp.RedirectURL, Reference: snapResp.Token}, nil
+}
+
+// GetStatus mengambil status transaksi langsung dari Midtrans status API
+func (p *midtransProvider) GetStatus(orderID string) (Notification, error) {
+	client := coreapi.Client{}
+	client.New(p.serverKey, p.environment)
+
+	status, midtransErr := client.CheckTransaction(orderID)
+	if midtransErr != nil && midtransErr.StatusCode == http.StatusNotFound {
+		return Notification{}, ErrPaymentNotFound
+	}
+	if midtransErr != nil {
+		return Notification{}, midtransErr
+	}
+
+	return p.toNotification(midtransNotification{
+		TransactionID:     status.TransactionID,
+		TransactionStatus: status.TransactionStatus,
+		OrderID:           status.OrderID,
+		PaymentType:       status.PaymentType,
+		FraudStatus:       status.FraudStatus,
+		StatusCode:        status.StatusCode,
+		GrossAmount:       status.GrossAmount,
+	})
+}
+
+func (p *midtransProvider) Refund(orderID string, amount int, reason string) (string, error) {
+	client := coreapi.Client{}
+	client.New(p.serverKey, p.environment)
+
+	refundKey := fmt.Sprintf("%s-refund-%d", orderID, time.Now().UnixNano())
+	refund, midtransErr := client.RefundTransaction(orderID, &coreapi.RefundReq{
+		RefundKey: refundKey,
+		Amount:    int64(amount),
+		Reason:    reason,
+	})
+	if midtransErr != nil {
+		return "", midtransErr
+	}
+	if refund.StatusCode != "200" && refund.StatusCode != "201" {
+		return "", errors.New("Midtrans refund failed: " + refund.StatusMessage)
+	}
+	return refundKey, nil
+}
+
+// VerifyWebhook mencocokkan signature_key = SHA512(order_id + status_code + gross_amount + server key).
+// Jika verifyStatus aktif, status diambil ulang dari status API dan isi webhook hanya dipakai untuk order_id
+func (p *midtransProvider) VerifyWebhook(header http.Header, body []byte) (Notification, error) {
+	var input midtransNotification
+	err := json.Unmarshal(body, &input)
+	if err != nil {
+		return Notification{}, err
+	}
+
+	if p.serverKey == "" {
+		return Notification{}, errors.New("MIDTRANS_SERVER_KEY is not set")
+	}
+
+	hash := sha512.Sum512([]byte(input.OrderID + input.StatusCode + input.GrossAmount + p.serverKey))
+	expected := hex.EncodeToString(hash[:])
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(input.SignatureKey)) != 1 {
+		return Notification{}, ErrInvalidSignature
+	}
+
+	if p.verifyStatus {
+		return p.GetStatus(input.OrderID)
+	}
+	return p.toNotification(input)
+}
+
+func (p *midtransProvider) toNotification(input midtransNotification) (Notification, error) {
+	// gross_amount dikirim sebagai string, misalnya "10000.00"
+	grossAmount, err := strconv.ParseFloat(input.GrossAmount, 64)
+	if err != nil {
+		return Notification{}, errors.New("Invalid gross amount")
+	}
+
+	id := input.TransactionID
+	if id == "" {
+		id = input.OrderID
+	}
+
+	notification := Notification{}
+	notification.Provider = ProviderMidtrans
+	notification.EventKey = ProviderMidtrans + ":" + id + ":" + input.TransactionStatus + ":" + input.StatusCode
+	notification.OrderID = input.OrderID
+	notification.Status = midtransStatus(input)
+	notification.Amount = int(math.Round(grossAmount))
+	notification.RawStatus = input.TransactionStatus
+	notification.StatusCode = input.StatusCode
+	return notification, nil
+}
+
+// midtransStatus status transaksi untuk transaction_status dari Midtrans,
+// string kosong berarti status transaksi tidak berubah (misalnya masih pending)
+func midtransStatus(input midtransNotification) string {
+	switch input.TransactionStatus {
+	case "capture":
+		if input.PaymentType == "credit_card" && input.FraudStatus == "accept" {
+			return StatusPaid
+		}
+	case "settlement":
+		return StatusPaid
+	case "deny", "cancel":
+		return StatusCancelled
+	case "expire":
+		return StatusExpired
+	case "refund":
+		return StatusRefunded
+	case "partial_refund":
+		// refund sebagian sudah dicatat saat refund diajukan, notifikasinya tidak mengubah status
+		return ""
+	}
+	return ""
+}
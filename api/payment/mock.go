@@ -0,0 +1,242 @@
+package payment
+
+import (
+	"bwastartup/api/user"
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const ProviderMock = "mock"
+
+const MockSignatureHeader = "X-Mock-Signature"
+
+// MockProvider payment gateway palsu untuk development dan testing tanpa koneksi ke Midtrans.
+// Checkout dilakukan di halaman lokal, lalu webhook ber-signature dikirim ke endpoint notifikasi
+type MockProvider struct {
+	secret     string
+	baseURL    string
+	webhookURL string
+	client     *http.Client
+
+	mu       sync.Mutex
+	statuses map[string]mockPayment
+}
+
+type mockPayment struct {
+	Status   string
+	Amount   int
+	Refunded int
+	// SavePaymentMethod pembayaran pertama recurring pledge, token dikirim saat paid
+	SavePaymentMethod bool
+}
+
+// mockTokenPrefix token metode pembayaran tersimpan di mock provider, diikuti order ID pembayaran pertamanya
+const mockTokenPrefix = "mock-token-"
+
+// mockWebhook isi body webhook yang dikirim mock provider
+type mockWebhook struct {
+	EventID      string `json:"event_id"`
+	OrderID      string `json:"order_id"`
+	Status       string `json:"status"`
+	Amount       int    `json:"amount"`
+	PaymentToken string `json:"payment_token,omitempty"`
+}
+
+// NewMockProvider secret kosong berarti dibuat acak, karena yang membuat dan memeriksa signature adalah proses yang sama
+func NewMockProvider(secret string, baseURL string, webhookURL string) *MockProvider {
+	if secret == "" {
+		randomSecret := make([]byte, 32)
+		rand.Read(randomSecret)
+		secret = hex.EncodeToString(randomSecret)
+	}
+
+	return &MockProvider{
+		secret:     secret,
+		baseURL:    baseURL,
+		webhookURL: webhookURL,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		statuses:   map[string]mockPayment{},
+	}
+}
+
+func (p *MockProvider) Name() string {
+	return ProviderMock
+}
+
+func (p *MockProvider) CreateCharge(transaction Transaction, user user.User) (Charge, error) {
+	orderID := transaction.OrderID
+
+	p.mu.Lock()
+	p.statuses[orderID] = mockPayment{StatusPending, transaction.Amount, 0, transaction.SavePaymentMethod}
+	p.mu.Unlock()
+
+	query := url.Values{}
+	query.Set("amount", strconv.Itoa(transaction.Amount))
+	query.Set("signature", p.sign([]byte(orderID+":"+strconv.Itoa(transaction.Amount))))
+
+	charge := Charge{}
+	charge.PaymentURL = p.baseURL + "/payments/mock/checkout/" + url.PathEscape(orderID) + "?" + query.Encode()
+	charge.Reference = "mock-" + orderID
+	return charge, nil
+}
+
+func (p *MockProvider) GetStatus(orderID string) (Notification, error) {
+	p.mu.Lock()
+	payment, ok := p.statuses[orderID]
+	p.mu.Unlock()
+
+	if !ok {
+		return Notification{}, ErrPaymentNotFound
+	}
+
+	notification := Notification{}
+	notification.Provider = ProviderMock
+	notification.EventKey = ProviderMock + ":status:" + orderID + ":" + payment.Status
+	notification.OrderID = orderID
+	notification.Amount = payment.Amount
+	notification.RawStatus = payment.Status
+	notification.PaymentToken = payment.paymentToken(orderID)
+	// pembayaran yang masih pending tidak mengubah status transaksi
+	if payment.Status != StatusPending {
+		notification.Status = payment.Status
+	}
+	return notification, nil
+}
+
+func (payment mockPayment) paymentToken(orderID string) string {
+	if !payment.SavePaymentMethod || payment.Status != StatusPaid {
+		return ""
+	}
+	return mockTokenPrefix + orderID
+}
+
+// ChargeSaved tagihan recurring di mock provider langsung lunas selama token berasal dari pembayaran yang lunas
+func (p *MockProvider) ChargeSaved(transaction Transaction, paymentToken string) (Notification, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	firstPayment, ok := p.statuses[strings.TrimPrefix(paymentToken, mockTokenPrefix)]
+	if !strings.HasPrefix(paymentToken, mockTokenPrefix) || !ok || !firstPayment.SavePaymentMethod {
+		return Notification{}, errors.New("Invalid mock payment token")
+	}
+
+	p.statuses[transaction.OrderID] = mockPayment{StatusPaid, transaction.Amount, 0, false}
+
+	notification := Notification{}
+	notification.Provider = ProviderMock
+	notification.EventKey = ProviderMock + ":recurring:" + transaction.OrderID
+	notification.OrderID = transaction.OrderID
+	notification.Status = StatusPaid
+	notification.Amount = transaction.Amount
+	notification.RawStatus = StatusPaid
+	return notification, nil
+}
+
+func (p *MockProvider) Refund(orderID string, amount int, reason string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payment, ok := p.statuses[orderID]
+	if !ok || payment.Status != StatusPaid {
+		return "", errors.New("Only paid mock payments can be refunded")
+	}
+
+	if amount <= 0 || payment.Refunded+amount > payment.Amount {
+		return "", errors.New("Refund amount exceeds the mock payment amount")
+	}
+
+	payment.Refunded += amount
+	if payment.Refunded == payment.Amount {
+		payment.Status = StatusRefunded
+	}
+	p.statuses[orderID] = payment
+	return fmt.Sprintf("mock-refund-%s-%d", orderID, time.Now().UnixNano()), nil
+}
+
+func (p *MockProvider) VerifyWebhook(header http.Header, body []byte) (Notification, error) {
+	if !hmac.Equal([]byte(p.sign(body)), []byte(header.Get(MockSignatureHeader))) {
+		return Notification{}, ErrInvalidSignature
+	}
+
+	var webhook mockWebhook
+	err := json.Unmarshal(body, &webhook)
+	if err != nil {
+		return Notification{}, err
+	}
+
+	notification := Notification{}
+	notification.Provider = ProviderMock
+	notification.EventKey = ProviderMock + ":" + webhook.EventID
+	notification.OrderID = webhook.OrderID
+	notification.Status = webhook.Status
+	notification.Amount = webhook.Amount
+	notification.RawStatus = webhook.Status
+	notification.PaymentToken = webhook.PaymentToken
+	return notification, nil
+}
+
+// VerifyCheckout memastikan order dan amount di halaman checkout tidak diubah
+func (p *MockProvider) VerifyCheckout(orderID string, amount int, signature string) bool {
+	expected := p.sign([]byte(orderID + ":" + strconv.Itoa(amount)))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// Complete menyelesaikan pembayaran di halaman checkout (paid, cancelled atau expired)
+// lalu mengirim webhook ke endpoint notifikasi seperti payment gateway sungguhan
+func (p *MockProvider) Complete(orderID string, amount int, status string) error {
+	if status != StatusPaid && status != StatusCancelled && status != StatusExpired {
+		return errors.New("Invalid mock payment status")
+	}
+
+	p.mu.Lock()
+	payment := mockPayment{status, amount, 0, p.statuses[orderID].SavePaymentMethod}
+	p.statuses[orderID] = payment
+	p.mu.Unlock()
+
+	body, err := json.Marshal(mockWebhook{
+		EventID:      fmt.Sprintf("%s-%s-%d", orderID, status, time.Now().UnixNano()),
+		OrderID:      orderID,
+		Status:       status,
+		Amount:       amount,
+		PaymentToken: payment.paymentToken(orderID),
+	})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, p.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(MockSignatureHeader, p.sign(body))
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("Webhook returned status %d", response.StatusCode)
+	}
+	return nil
+}
+
+func (p *MockProvider) sign(data []byte) string {
+	mac := hmac.New(sha256.New, []byte(p.secret))
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
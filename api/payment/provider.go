@@ -0,0 +1,106 @@
+package payment
+
+import (
+	"bwastartup/api/user"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+)
+
+var ErrInvalidSignature = errors.New("Invalid notification signature")
+
+// ErrPaymentNotFound provider tidak punya pembayaran untuk order tersebut (halaman pembayaran tidak pernah diselesaikan)
+var ErrPaymentNotFound = errors.New("Payment not found at provider")
+
+// Provider payment gateway yang dipakai untuk menagih backer
+type Provider interface {
+	Name() string
+	CreateCharge(transaction Transaction, user user.User) (Charge, error)
+	GetStatus(orderID string) (Notification, error)
+	// Refund mengembalikan dana (penuh atau sebagian), yang dikembalikan adalah referensi refund dari provider
+	Refund(orderID string, amount int, reason string) (string, error)
+	// VerifyWebhook memeriksa signature webhook lalu mengubah isinya menjadi Notification
+	VerifyWebhook(header http.Header, body []byte) (Notification, error)
+}
+
+// RecurringProvider provider yang bisa menagih metode pembayaran tersimpan tanpa backer membuka halaman pembayaran
+type RecurringProvider interface {
+	// ChargeSaved menagih langsung, Status notifikasi kosong berarti pembayaran masih diproses provider
+	ChargeSaved(transaction Transaction, paymentToken string) (Notification, error)
+}
+
+type Config struct {
+	// Provider default untuk transaksi baru: midtrans (default), xendit, stripe atau mock
+	Provider string
+	// AppBaseURL alamat publik aplikasi ini, dipakai mock provider untuk halaman checkout dan webhook
+	AppBaseURL string
+	// RedirectURL halaman tujuan backer setelah selesai membayar di halaman provider
+	RedirectURL string
+
+	MidtransServerKey  string
+	MidtransProduction bool
+	// MidtransVerifyStatus jika true status di webhook dicek ulang lewat status API Midtrans
+	MidtransVerifyStatus bool
+
+	XenditSecretKey     string
+	XenditCallbackToken string
+	XenditBaseURL       string
+
+	StripeSecretKey     string
+	StripeWebhookSecret string
+	StripeCurrency      string
+	StripeBaseURL       string
+
+	MockSecret string
+}
+
+func NewConfigFromEnv() Config {
+	config := Config{}
+	config.Provider = getEnv("PAYMENT_PROVIDER", "midtrans")
+	config.AppBaseURL = strings.TrimRight(getEnv("APP_BASE_URL", "http://localhost:8080"), "/")
+	config.MidtransServerKey = os.Getenv("MIDTRANS_SERVER_KEY")
+	config.MidtransProduction = os.Getenv("MIDTRANS_ENVIRONMENT") == "production"
+	config.MidtransVerifyStatus = os.Getenv("MIDTRANS_VERIFY_STATUS") == "true"
+	config.RedirectURL = getEnv("PAYMENT_REDIRECT_URL", config.AppBaseURL)
+	config.XenditSecretKey = os.Getenv("XENDIT_SECRET_KEY")
+	config.XenditCallbackToken = os.Getenv("XENDIT_CALLBACK_TOKEN")
+	config.XenditBaseURL = os.Getenv("XENDIT_BASE_URL")
+	config.StripeSecretKey = os.Getenv("STRIPE_SECRET_KEY")
+	config.StripeWebhookSecret = os.Getenv("STRIPE_WEBHOOK_SECRET")
+	config.StripeCurrency = getEnv("STRIPE_CURRENCY", "idr")
+	config.StripeBaseURL = os.Getenv("STRIPE_BASE_URL")
+	config.MockSecret = os.Getenv("PAYMENT_MOCK_SECRET")
+	return config
+}
+
+func getEnv(key string, defaultValue string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+// NewProviders membuat semua provider yang sudah dikonfigurasi. Midtrans selalu aktif untuk transaksi lama,
+// Xendit dan Stripe aktif jika secret key diisi, mock hanya aktif jika dipilih sebagai provider default
+func NewProviders(config Config) ([]Provider, *MockProvider) {
+	providers := []Provider{
+		NewMidtransProvider(config.MidtransServerKey, config.MidtransProduction, config.MidtransVerifyStatus),
+	}
+
+	if config.XenditSecretKey != "" {
+		providers = append(providers, NewXenditProvider(config.XenditSecretKey, config.XenditCallbackToken, config.XenditBaseURL, config.RedirectURL))
+	}
+
+	if config.StripeSecretKey != "" {
+		providers = append(providers, NewStripeProvider(config.StripeSecretKey, config.StripeWebhookSecret, config.StripeCurrency, config.StripeBaseURL, config.RedirectURL))
+	}
+
+	var mockProvider *MockProvider
+	if config.Provider == ProviderMock {
+		mockProvider = NewMockProvider(config.MockSecret, config.AppBaseURL, config.AppBaseURL+"/api/v1/transactions/notification/"+ProviderMock)
+		providers = append(providers, mockProvider)
+	}
+	return providers, mockProvider
+}
@@ -2,57 +2,113 @@ package payment
 
 import (
 	"bwastartup/api/user"
-	"log"
-	"os"
-	"strconv"
-
-	"github.com/joho/godotenv"
-	"github.com/midtrans/midtrans-go"
-	"github.com/midtrans/midtrans-go/snap"
+	"errors"
+	"net/http"
 )
 
 type service struct {
+	providers       map[string]Provider
+	defaultProvider string
 }
 
 type Service interface {
-	GetPaymentUrl(transaction Transaction, user user.User) (string, error)
+	CreateCharge(providerName string, transaction Transaction, user user.User) (Charge, error)
+	ParseNotification(providerName string, header http.Header, body []byte) (Notification, error)
+	GetTransactionStatus(providerName string, orderID string) (Notification, error)
+	Refund(providerName string, orderID string, amount int, reason string) (string, error)
+	DefaultProvider() string
+	HasProvider(name string) bool
+	SupportsRecurring(providerName string) bool
+	ChargeSaved(providerName string, transaction Transaction, paymentToken string) (Notification, error)
 }
 
-func NewService() *service{
-	return &service{}
+// NewService providers berisi semua provider yang aktif, defaultProvider dipakai untuk transaksi baru
+func NewService(providers []Provider, defaultProvider string) (*service, error) {
+	providerMap := map[string]Provider{}
+	for _, provider := range providers {
+		providerMap[provider.Name()] = provider
+	}
+
+	if _, ok := providerMap[defaultProvider]; !ok {
+		return nil, errors.New("Unknown payment provider: " + defaultProvider)
+	}
+	return &service{providerMap, defaultProvider}, nil
 }
 
-func goDotEnvVariable(key string) string {
+func (s *service) provider(name string) (Provider, error) {
+	if name == "" {
+		name = s.defaultProvider
+	}
 
-  // load .env file
-  err := godotenv.Load(".env")
+	provider, ok := s.providers[name]
+	if !ok {
+		return nil, errors.New("Unknown payment provider: " + name)
+	}
+	return provider, nil
+}
 
-  if err != nil {
-    log.Fatalf("Error loading .env file")
-  }
+// CreateCharge membuat tagihan di provider, providerName kosong berarti provider default
+func (s *service) CreateCharge(providerName string, transaction Transaction, user user.User) (Charge, error) {
+	provider, err := s.provider(providerName)
+	if err != nil {
+		return Charge{}, err
+	}
+	return provider.CreateCharge(transaction, user)
+}
 
-  return os.Getenv(key)
+// ParseNotification memverifikasi webhook dari provider, providerName kosong berarti provider default
+func (s *service) ParseNotification(providerName string, header http.Header, body []byte) (Notification, error) {
+	provider, err := s.provider(providerName)
+	if err != nil {
+		return Notification{}, err
+	}
+	return provider.VerifyWebhook(header, body)
 }
 
+func (s *service) GetTransactionStatus(providerName string, orderID string) (Notification, error) {
+	provider, err := s.provider(providerName)
+	if err != nil {
+		return Notification{}, err
+	}
+	return provider.GetStatus(orderID)
+}
 
-func(s *service) GetPaymentUrl(transaction Transaction, user user.User) (string, error){
-	dotenv := goDotEnvVariable("MIDTRANS_SERVER_KEY")
-	midtrans.ServerKey = dotenv
-	midtrans.Environment = midtrans.Sandbox
+func (s *service) Refund(providerName string, orderID string, amount int, reason string) (string, error) {
+	provider, err := s.provider(providerName)
+	if err != nil {
+		return "", err
+	}
+	return provider.Refund(orderID, amount, reason)
+}
+
+func (s *service) DefaultProvider() string {
+	return s.defaultProvider
+}
+
+func (s *service) HasProvider(name string) bool {
+	_, ok := s.providers[name]
+	return ok
+}
 
-	snapReq := &snap.Request{
-		CustomerDetail: &midtrans.CustomerDetails{
-			Email: user.Email,
-			FName: user.Name,
-		},
-		TransactionDetails: midtrans.TransactionDetails{
-			OrderID: strconv.Itoa(transaction.ID),
-			GrossAmt: int64(transaction.Amount),
-		},
+// SupportsRecurring provider bisa dipakai untuk recurring pledge, providerName kosong berarti provider default
+func (s *service) SupportsRecurring(providerName string) bool {
+	provider, err := s.provider(providerName)
+	if err != nil {
+		return false
 	}
-	snapResp, err := snap.CreateTransaction(snapReq)
+	_, ok := provider.(RecurringProvider)
+	return ok
+}
+
+func (s *service) ChargeSaved(providerName string, transaction Transaction, paymentToken string) (Notification, error) {
+	provider, err := s.provider(providerName)
 	if err != nil {
-		return "", err
+		return Notification{}, err
+	}
+
+	recurringProvider, ok := provider.(RecurringProvider)
+	if !ok {
+		return Notification{}, errors.New("Payment provider does not support recurring pledges")
 	}
-	return snapResp.RedirectURL, nil
+	return recurringProvider.ChargeSaved(transaction, paymentToken)
 }
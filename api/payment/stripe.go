@@ -0,0 +1,435 @@
+package payment
+
+import (
+	"bwastartup/api/money"
+	"bwastartup/api/user"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ProviderStripe = "stripe"
+
+const StripeSignatureHeader = "Stripe-Signature"
+
+// stripeWebhookTolerance batas umur webhook untuk mencegah replay
+const stripeWebhookTolerance = 5 * time.Minute
+
+// mata uang Stripe yang tidak punya pecahan, selain ini amount dikirim dalam satuan sen (x100)
+var stripeZeroDecimalCurrencies = map[string]bool{
+	"bif": true, "clp": true, "djf": true, "gnf": true, "jpy": true, "kmf": true, "krw": true, "mga": true,
+	"pyg": true, "rwf": true, "ugx": true, "vnd": true, "vuv": true, "xaf": true, "xof": true, "xpf": true,
+}
+
+// stripeProvider adapter Stripe Checkout, backer membayar lewat halaman Checkout Session
+type stripeProvider struct {
+	secretKey     string
+	webhookSecret string
+	// currency dipakai jika transaksi tidak membawa mata uang
+	currency    string
+	baseURL     string
+	redirectURL string
+	client      *http.Client
+}
+
+func NewStripeProvider(secretKey string, webhookSecret string, currency string, baseURL string, redirectURL string) *stripeProvider {
+	if baseURL == "" {
+		baseURL = "https://api.stripe.com"
+	}
+	if currency == "" {
+		currency = "idr"
+	}
+	return &stripeProvider{secretKey, webhookSecret, strings.ToLower(currency), baseURL, redirectURL, &http.Client{Timeout: 30 * time.Second}}
+}
+
+type stripeCheckoutSession struct {
+	ID                string            `json:"id"`
+	URL               string            `json:"url"`
+	PaymentStatus     string            `json:"payment_status"`
+	AmountTotal       int64             `json:"amount_total"`
+	Currency          string            `json:"currency"`
+	ClientReferenceID string            `json:"client_reference_id"`
+	PaymentIntent     string            `json:"payment_intent"`
+	Metadata          map[string]string `json:"metadata"`
+}
+
+type stripePaymentIntent struct {
+	ID             string            `json:"id"`
+	Status         string            `json:"status"`
+	Amount         int64             `json:"amount"`
+	AmountReceived int64             `json:"amount_received"`
+	Currency       string            `json:"currency"`
+	Metadata       map[string]string `json:"metadata"`
+	// Customer, PaymentMethod dan SetupFutureUsage terisi jika metode pembayaran disimpan untuk tagihan recurring
+	Customer         string `json:"customer"`
+	PaymentMethod    string `json:"payment_method"`
+	SetupFutureUsage string `json:"setup_future_usage"`
+	// LatestCharge di-expand sampai balance_transaction untuk mengambil fee Stripe
+	LatestCharge *struct {
+		BalanceTransaction *struct {
+			Fee      int64  `json:"fee"`
+			Currency string `json:"currency"`
+			// ExchangeRate kurs dari mata uang pembayaran ke mata uang saldo Stripe, kosong jika sama
+			ExchangeRate float64 `json:"exchange_rate"`
+		} `json:"balance_transaction"`
+	} `json:"latest_charge"`
+}
+
+// gatewayFee fee Stripe dalam mata uang pembayaran. Fee dicatat Stripe dalam mata uang saldo akun,
+// jadi dikonversi balik memakai kurs balance transaction jika mata uangnya berbeda
+func (p *stripeProvider) gatewayFee(paymentIntent stripePaymentIntent) int {
+	if paymentIntent.LatestCharge == nil || paymentIntent.LatestCharge.BalanceTransaction == nil {
+		return 0
+	}
+	balanceTransaction := paymentIntent.LatestCharge.BalanceTransaction
+	fee := p.fromMinorUnit(balanceTransaction.Fee, balanceTransaction.Currency)
+	if balanceTransaction.ExchangeRate == 0 || strings.EqualFold(balanceTransaction.Currency, paymentIntent.Currency) {
+		return fee
+	}
+	return money.New(fee, balanceTransaction.Currency).Convert(paymentIntent.Currency, 1/balanceTransaction.ExchangeRate).Amount
+}
+
+// paymentToken customer dan payment method yang boleh ditagih off-session, format "cus_xxx:pm_xxx"
+func (p *stripeProvider) paymentToken(paymentIntent stripePaymentIntent) string {
+	if paymentIntent.SetupFutureUsage != "off_session" || paymentIntent.Customer == "" || paymentIntent.PaymentMethod == "" {
+		return ""
+	}
+	return paymentIntent.Customer + ":" + paymentIntent.PaymentMethod
+}
+
+type stripeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+func (p *stripeProvider) Name() string {
+	return ProviderStripe
+}
+
+func (p *stripeProvider) CreateCharge(transaction Transaction, user user.User) (Charge, error) {
+	orderID := transaction.OrderID
+
+	form := url.Values{}
+	form.Set("mode", "payment")
+	form.Set("success_url", p.redirectURL)
+	form.Set("cancel_url", p.redirectURL)
+	form.Set("customer_email", user.Email)
+	form.Set("client_reference_id", orderID)
+	form.Set("metadata[order_id]", orderID)
+	form.Set("payment_intent_data[metadata][order_id]", orderID)
+	form.Set("line_items[0][quantity]", "1")
+	currency := p.currency
+	if transaction.Currency != "" {
+		currency = strings.ToLower(transaction.Currency)
+	}
+	form.Set("line_items[0][price_data][currency]", currency)
+	form.Set("line_items[0][price_data][unit_amount]", strconv.FormatInt(p.toMinorUnit(transaction.Amount, currency), 10))
+	form.Set("line_items[0][price_data][product_data][name]", transaction.Description)
+	if transaction.SavePaymentMethod {
+		form.Set("customer_creation", "always")
+		form.Set("payment_intent_data[setup_future_usage]", "off_session")
+	}
+
+	var session stripeCheckoutSession
+	err := p.do(http.MethodPost, "/v1/checkout/sessions", form, &session)
+	if err != nil {
+		return Charge{}, err
+	}
+	return Charge{PaymentURL: session.URL, Reference: session.ID}, nil
+}
+
+func (p *stripeProvider) GetStatus(orderID string) (Notification, error) {
+	paymentIntent, err := p.findPaymentIntent(orderID)
+	if err != nil {
+		return Notification{}, err
+	}
+
+	return p.paymentIntentNotification(orderID, paymentIntent), nil
+}
+
+func (p *stripeProvider) paymentIntentNotification(orderID string, paymentIntent stripePaymentIntent) Notification {
+	notification := Notification{}
+	notification.Provider = ProviderStripe
+	notification.EventKey = ProviderStripe + ":" + paymentIntent.ID + ":" + paymentIntent.Status
+	notification.OrderID = orderID
+	notification.Amount = p.fromMinorUnit(paymentIntent.Amount, paymentIntent.Currency)
+	notification.GatewayFee = p.gatewayFee(paymentIntent)
+	notification.RawStatus = paymentIntent.Status
+	notification.PaymentToken = p.paymentToken(paymentIntent)
+
+	switch paymentIntent.Status {
+	case "succeeded":
+		notification.Status = StatusPaid
+	case "canceled":
+		notification.Status = StatusCancelled
+	}
+	return notification
+}
+
+// ChargeSaved membuat payment intent off-session memakai customer dan payment method dari checkout pertama
+func (p *stripeProvider) ChargeSaved(transaction Transaction, paymentToken string) (Notification, error) {
+	customer, paymentMethod, found := strings.Cut(paymentToken, ":")
+	if !found {
+		return Notification{}, errors.New("Invalid Stripe payment token")
+	}
+
+	currency := p.currency
+	if transaction.Currency != "" {
+		currency = strings.ToLower(transaction.Currency)
+	}
+
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(p.toMinorUnit(transaction.Amount, currency), 10))
+	form.Set("currency", currency)
+	form.Set("customer", customer)
+	form.Set("payment_method", paymentMethod)
+	form.Set("off_session", "true")
+	form.Set("confirm", "true")
+	form.Set("description", transaction.Description)
+	form.Set("metadata[order_id]", transaction.OrderID)
+	form.Add("expand[]", "latest_charge.balance_transaction")
+
+	var paymentIntent stripePaymentIntent
+	// order ID dipakai sebagai idempotency key supaya request yang dikirim ulang tidak menagih dua kali
+	err := p.request(http.MethodPost, "/v1/payment_intents", form, transaction.OrderID, &paymentIntent)
+	if err != nil {
+		return Notification{}, err
+	}
+
+	notification := p.paymentIntentNotification(transaction.OrderID, paymentIntent)
+	// tagihan off-session yang butuh tindakan backer (misalnya 3DS) dianggap gagal
+	if paymentIntent.Status == "requires_payment_method" || paymentIntent.Status == "requires_action" {
+		notification.Status = StatusCancelled
+	}
+	return notification, nil
+}
+
+func (p *stripeProvider) Refund(orderID string, amount int, reason string) (string, error) {
+	paymentIntent, err := p.findPaymentIntent(orderID)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("payment_intent", paymentIntent.ID)
+	form.Set("amount", strconv.FormatInt(p.toMinorUnit(amount, paymentIntent.Currency), 10))
+	form.Set("reason", "requested_by_customer")
+	form.Set("metadata[order_id]", orderID)
+	form.Set("metadata[reason]", reason)
+
+	var refund struct {
+		ID string `json:"id"`
+	}
+	err = p.do(http.MethodPost, "/v1/refunds", form, &refund)
+	if err != nil {
+		return "", err
+	}
+	return refund.ID, nil
+}
+
+// VerifyWebhook memeriksa header Stripe-Signature (t=timestamp,v1=HMAC-SHA256 dari "timestamp.body")
+func (p *stripeProvider) VerifyWebhook(header http.Header, body []byte) (Notification, error) {
+	if p.webhookSecret == "" {
+		return Notification{}, errors.New("STRIPE_WEBHOOK_SECRET is not set")
+	}
+
+	err := p.verifySignature(header.Get(StripeSignatureHeader), body)
+	if err != nil {
+		return Notification{}, err
+	}
+
+	var event stripeEvent
+	err = json.Unmarshal(body, &event)
+	if err != nil {
+		return Notification{}, err
+	}
+
+	notification := Notification{}
+	notification.Provider = ProviderStripe
+	notification.EventKey = ProviderStripe + ":" + event.ID
+	notification.RawStatus = event.Type
+
+	// event lain (misalnya payment_intent.*) tetap dianggap valid tapi tidak mengubah transaksi
+	if !strings.HasPrefix(event.Type, "checkout.session.") {
+		return notification, nil
+	}
+
+	var session stripeCheckoutSession
+	err = json.Unmarshal(event.Data.Object, &session)
+	if err != nil {
+		return Notification{}, err
+	}
+
+	notification.OrderID = session.ClientReferenceID
+	if notification.OrderID == "" {
+		notification.OrderID = session.Metadata["order_id"]
+	}
+	notification.Amount = p.fromMinorUnit(session.AmountTotal, session.Currency)
+
+	switch event.Type {
+	case "checkout.session.completed", "checkout.session.async_payment_succeeded":
+		// pembayaran async (misalnya transfer bank) baru lunas di event async_payment_succeeded
+		if session.PaymentStatus == "paid" {
+			notification.Status = StatusPaid
+
+			// event checkout session tidak berisi fee, fee diambil dari payment intent-nya
+			var paymentIntent stripePaymentIntent
+			err = p.do(http.MethodGet, "/v1/payment_intents/"+url.PathEscape(session.PaymentIntent)+"?expand[]=latest_charge.balance_transaction", nil, &paymentIntent)
+			if err != nil {
+				return Notification{}, err
+			}
+			notification.GatewayFee = p.gatewayFee(paymentIntent)
+			notification.PaymentToken = p.paymentToken(paymentIntent)
+		}
+	case "checkout.session.async_payment_failed":
+		notification.Status = StatusCancelled
+	case "checkout.session.expired":
+		notification.Status = StatusExpired
+	}
+	return notification, nil
+}
+
+func (p *stripeProvider) verifySignature(signatureHeader string, body []byte) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(signatureHeader, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		if key == "t" {
+			timestamp = value
+		}
+		if key == "v1" {
+			signatures = append(signatures, value)
+		}
+	}
+
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+	if time.Since(time.Unix(signedAt, 0)) > stripeWebhookTolerance {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(p.webhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(expected), []byte(signature)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func (p *stripeProvider) findPaymentIntent(orderID string) (stripePaymentIntent, error) {
+	query := url.Values{}
+	query.Set("query", fmt.Sprintf("metadata['order_id']:'%s'", orderID))
+	query.Add("expand[]", "data.latest_charge.balance_transaction")
+
+	var result struct {
+		Data []stripePaymentIntent `json:"data"`
+	}
+	err := p.do(http.MethodGet, "/v1/payment_intents/search?"+query.Encode(), nil, &result)
+	if err != nil {
+		return stripePaymentIntent{}, err
+	}
+	if len(result.Data) == 0 {
+		return stripePaymentIntent{}, ErrPaymentNotFound
+	}
+	return result.Data[0], nil
+}
+
+// stripeExponent jumlah digit pecahan amount di API Stripe, bisa berbeda dengan exponent di package money
+// (rupiah disimpan tanpa sen tapi Stripe tetap memakai x100)
+func (p *stripeProvider) stripeExponent(currency string) int {
+	if stripeZeroDecimalCurrencies[strings.ToLower(currency)] {
+		return 0
+	}
+	return 2
+}
+
+func (p *stripeProvider) toMinorUnit(amount int, currency string) int64 {
+	if currency == "" {
+		currency = p.currency
+	}
+	result := int64(amount)
+	for i := money.LookupCurrency(currency).Exponent; i < p.stripeExponent(currency); i++ {
+		result *= 10
+	}
+	return result
+}
+
+func (p *stripeProvider) fromMinorUnit(amount int64, currency string) int {
+	if currency == "" {
+		currency = p.currency
+	}
+	for i := money.LookupCurrency(currency).Exponent; i < p.stripeExponent(currency); i++ {
+		amount /= 10
+	}
+	return int(amount)
+}
+
+func (p *stripeProvider) do(method string, path string, form url.Values, response interface{}) error {
+	return p.request(method, path, form, "", response)
+}
+
+// request idempotencyKey kosong berarti tanpa header Idempotency-Key
+func (p *stripeProvider) request(method string, path string, form url.Values, idempotencyKey string, response interface{}) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	httpRequest, err := http.NewRequest(method, p.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	httpRequest.Header.Set("Authorization", "Bearer "+p.secretKey)
+	if form != nil {
+		httpRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if idempotencyKey != "" {
+		httpRequest.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	httpResponse, err := p.client.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	responseBody, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return err
+	}
+
+	if httpResponse.StatusCode >= 300 {
+		var stripeError struct {
+			Error struct {
+				Type    string `json:"type"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.Unmarshal(responseBody, &stripeError)
+		return fmt.Errorf("Stripe error %d: %s %s", httpResponse.StatusCode, stripeError.Error.Type, stripeError.Error.Message)
+	}
+
+	return json.Unmarshal(responseBody, response)
+}
@@ -0,0 +1,239 @@
+package payment
+
+import (
+	"bwastartup/api/user"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const (
+	testStripeSecretKey     = "sk_test_contract"
+	testStripeWebhookSecret = "whsec_contract"
+	testOrderID             = "BWA-2026-7KQ2XD"
+)
+
+func newTestStripeProvider(t *testing.T, routes map[string]fixtureRoute) (*stripeProvider, *fixtureServer) {
+	t.Helper()
+
+	server := newFixtureServer(t, "stripe", routes)
+	return NewStripeProvider(testStripeSecretKey, testStripeWebhookSecret, "idr", server.URL, "https://bwastartup.test/payments/done"), server
+}
+
+func stripeSignature(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix + "."))
+	mac.Write(body)
+	return "t=" + unix + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestStripeCreateCharge(t *testing.T) {
+	provider, server := newTestStripeProvider(t, map[string]fixtureRoute{
+		"POST /v1/checkout/sessions": {Fixture: "checkout_session_create.json"},
+	})
+
+	charge, err := provider.CreateCharge(Transaction{ID: 1, OrderID: testOrderID, Amount: 50000, Currency: "IDR", Description: "Concurrent Campaign", SavePaymentMethod: true}, user.User{Email: "backer@example.com"})
+	if err != nil {
+		t.Fatalf("CreateCharge: %v", err)
+	}
+
+	if charge.Reference != "cs_test_a1N8sMqE2bXh3vQ7kT0pLw9ZrYc4uF6dGj" {
+		t.Errorf("Reference = %q", charge.Reference)
+	}
+	if charge.PaymentURL != "https://checkout.stripe.com/c/pay/cs_test_a1N8sMqE2bXh3vQ7kT0pLw9ZrYc4uF6dGj" {
+		t.Errorf("PaymentURL = %q", charge.PaymentURL)
+	}
+
+	request := server.LastRequest(t, http.MethodPost, "/v1/checkout/sessions")
+	if request.Header.Get("Authorization") != "Bearer "+testStripeSecretKey {
+		t.Errorf("Authorization = %q", request.Header.Get("Authorization"))
+	}
+
+	form := request.Form(t)
+	expected := map[string]string{
+		"mode":                "payment",
+		"client_reference_id": testOrderID,
+		"metadata[order_id]":  testOrderID,
+		"payment_intent_data[metadata][order_id]": testOrderID,
+		"customer_email":                      "backer@example.com",
+		"line_items[0][price_data][currency]": "idr",
+		// rupiah tanpa sen di aplikasi, tapi Stripe tetap memakai dua digit pecahan
+		"line_items[0][price_data][unit_amount]":        "5000000",
+		"line_items[0][price_data][product_data][name]": "Concurrent Campaign",
+		"customer_creation":                             "always",
+		"payment_intent_data[setup_future_usage]":       "off_session",
+	}
+	for key, value := range expected {
+		if form.Get(key) != value {
+			t.Errorf("form %s = %q, want %q", key, form.Get(key), value)
+		}
+	}
+}
+
+func TestStripeGetStatus(t *testing.T) {
+	provider, server := newTestStripeProvider(t, map[string]fixtureRoute{
+		"GET /v1/payment_intents/search": {Fixture: "payment_intent_search.json"},
+	})
+
+	notification, err := provider.GetStatus(testOrderID)
+	if err != nil {
+		t.Fatalf("GetStatus: %v", err)
+	}
+
+	if notification.Status != StatusPaid {
+		t.Errorf("Status = %q, want %q", notification.Status, StatusPaid)
+	}
+	if notification.OrderID != testOrderID {
+		t.Errorf("OrderID = %q", notification.OrderID)
+	}
+	if notification.Amount != 50000 {
+		t.Errorf("Amount = %d, want 50000", notification.Amount)
+	}
+	if notification.GatewayFee != 1750 {
+		t.Errorf("GatewayFee = %d, want 1750", notification.GatewayFee)
+	}
+	if notification.EventKey != "stripe:pi_3OqLm2Kx8vR5tN1a0Zb7cD4e:succeeded" {
+		t.Errorf("EventKey = %q", notification.EventKey)
+	}
+	if notification.PaymentToken != "cus_PfG7hJ2kL9mN0q:pm_1OqLm1Kx8vR5tN1aYh6Tg3Ws" {
+		t.Errorf("PaymentToken = %q", notification.PaymentToken)
+	}
+
+	request := server.LastRequest(t, http.MethodGet, "/v1/payment_intents/search")
+	if request.Query.Get("query") != "metadata['order_id']:'"+testOrderID+"'" {
+		t.Errorf("query = %q", request.Query.Get("query"))
+	}
+	if request.Query.Get("expand[]") != "data.latest_charge.balance_transaction" {
+		t.Errorf("expand = %q", request.Query.Get("expand[]"))
+	}
+}
+
+func TestStripeGetStatusNotFound(t *testing.T) {
+	provider, _ := newTestStripeProvider(t, map[string]fixtureRoute{
+		"GET /v1/payment_intents/search": {Fixture: "payment_intent_search_empty.json"},
+	})
+
+	_, err := provider.GetStatus(testOrderID)
+	if !errors.Is(err, ErrPaymentNotFound) {
+		t.Errorf("GetStatus error = %v, want ErrPaymentNotFound", err)
+	}
+}
+
+func TestStripeRefund(t *testing.T) {
+	provider, server := newTestStripeProvider(t, map[string]fixtureRoute{
+		"GET /v1/payment_intents/search": {Fixture: "payment_intent_search.json"},
+		"POST /v1/refunds":               {Fixture: "refund_create.json"},
+	})
+
+	reference, err := provider.Refund(testOrderID, 20000, "Backer request")
+	if err != nil {
+		t.Fatalf("Refund: %v", err)
+	}
+	if reference != "re_3OqLm2Kx8vR5tN1a0H5jK8Lm" {
+		t.Errorf("reference = %q", reference)
+	}
+
+	form := server.LastRequest(t, http.MethodPost, "/v1/refunds").Form(t)
+	if form.Get("payment_intent") != "pi_3OqLm2Kx8vR5tN1a0Zb7cD4e" {
+		t.Errorf("payment_intent = %q", form.Get("payment_intent"))
+	}
+	if form.Get("amount") != "2000000" {
+		t.Errorf("amount = %q, want 2000000", form.Get("amount"))
+	}
+	if form.Get("metadata[reason]") != "Backer request" {
+		t.Errorf("metadata[reason] = %q", form.Get("metadata[reason]"))
+	}
+}
+
+func TestStripeRefundError(t *testing.T) {
+	provider, _ := newTestStripeProvider(t, map[string]fixtureRoute{
+		"GET /v1/payment_intents/search": {Fixture: "payment_intent_search.json"},
+		"POST /v1/refunds":               {Status: http.StatusBadRequest, Fixture: "refund_error.json"},
+	})
+
+	_, err := provider.Refund(testOrderID, 20000, "Backer request")
+	if err == nil {
+		t.Fatal("Refund error = nil, want provider error")
+	}
+	if err.Error() != "Stripe error 400: invalid_request_error Charge ch_3OqLm2Kx8vR5tN1a0b2XyZ9c has already been refunded." {
+		t.Errorf("Refund error = %q", err.Error())
+	}
+}
+
+func TestStripeVerifyWebhook(t *testing.T) {
+	provider, _ := newTestStripeProvider(t, map[string]fixtureRoute{
+		"GET /v1/payment_intents/pi_3OqLm2Kx8vR5tN1a0Zb7cD4e": {Fixture: "payment_intent.json"},
+	})
+	body := readFixture(t, "stripe", "event_checkout_session_completed.json")
+
+	header := http.Header{}
+	header.Set(StripeSignatureHeader, stripeSignature(testStripeWebhookSecret, time.Now(), body))
+
+	notification, err := provider.VerifyWebhook(header, body)
+	if err != nil {
+		t.Fatalf("VerifyWebhook: %v", err)
+	}
+
+	if notification.EventKey != "stripe:evt_1OqLm4Kx8vR5tN1aPq2Rs3Tu" {
+		t.Errorf("EventKey = %q", notification.EventKey)
+	}
+	if notification.OrderID != testOrderID {
+		t.Errorf("OrderID = %q", notification.OrderID)
+	}
+	if notification.Status != StatusPaid {
+		t.Errorf("Status = %q, want %q", notification.Status, StatusPaid)
+	}
+	if notification.Amount != 50000 {
+		t.Errorf("Amount = %d, want 50000", notification.Amount)
+	}
+	if notification.GatewayFee != 1750 {
+		t.Errorf("GatewayFee = %d, want 1750", notification.GatewayFee)
+	}
+	// payment method tidak disimpan untuk dukungan sekali bayar
+	if notification.PaymentToken != "" {
+		t.Errorf("PaymentToken = %q, want empty", notification.PaymentToken)
+	}
+}
+
+func TestStripeVerifyWebhookRejectsInvalidSignature(t *testing.T) {
+	provider, server := newTestStripeProvider(t, map[string]fixtureRoute{})
+	body := readFixture(t, "stripe", "event_checkout_session_completed.json")
+	tampered := append(append([]byte{}, body[:len(body)-1]...), ' ', '}')
+
+	cases := map[string]struct {
+		signature string
+		body      []byte
+	}{
+		"missing header":    {"", body},
+		"wrong secret":      {stripeSignature("whsec_other", time.Now(), body), body},
+		"tampered body":     {stripeSignature(testStripeWebhookSecret, time.Now(), body), tampered},
+		"expired timestamp": {stripeSignature(testStripeWebhookSecret, time.Now().Add(-10*time.Minute), body), body},
+		"malformed header":  {"v1=deadbeef", body},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			header := http.Header{}
+			if c.signature != "" {
+				header.Set(StripeSignatureHeader, c.signature)
+			}
+
+			_, err := provider.VerifyWebhook(header, c.body)
+			if !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("VerifyWebhook error = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+
+	// webhook yang ditolak tidak boleh memicu request ke Stripe
+	if len(server.Requests()) != 0 {
+		t.Errorf("requests = %d, want 0", len(server.Requests()))
+	}
+}
@@ -0,0 +1,192 @@
+package payment
+
+import (
+	"bwastartup/api/money"
+	"bwastartup/api/user"
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const ProviderXendit = "xendit"
+
+const XenditCallbackTokenHeader = "X-Callback-Token"
+
+// xenditProvider adapter Xendit Invoice API, backer membayar lewat halaman invoice Xendit
+type xenditProvider struct {
+	secretKey     string
+	callbackToken string
+	baseURL       string
+	redirectURL   string
+	client        *http.Client
+}
+
+func NewXenditProvider(secretKey string, callbackToken string, baseURL string, redirectURL string) *xenditProvider {
+	if baseURL == "" {
+		baseURL = "https://api.xendit.co"
+	}
+	return &xenditProvider{secretKey, callbackToken, baseURL, redirectURL, &http.Client{Timeout: 30 * time.Second}}
+}
+
+// xenditInvoice invoice dari API dan isi webhook invoice
+type xenditInvoice struct {
+	ID         string  `json:"id"`
+	ExternalID string  `json:"external_id"`
+	Status     string  `json:"status"`
+	Amount     float64 `json:"amount"`
+	Currency   string  `json:"currency"`
+	PaidAmount float64 `json:"paid_amount"`
+	// FeesPaidAmount biaya Xendit yang dipotong dari pembayaran
+	FeesPaidAmount float64 `json:"fees_paid_amount"`
+	InvoiceURL     string  `json:"invoice_url"`
+}
+
+func (p *xenditProvider) Name() string {
+	return ProviderXendit
+}
+
+func (p *xenditProvider) CreateCharge(transaction Transaction, user user.User) (Charge, error) {
+	request := map[string]interface{}{
+		"external_id":          transaction.OrderID,
+		"amount":               money.New(transaction.Amount, transaction.Currency).Major(),
+		"currency":             money.NormalizeCurrency(transaction.Currency),
+		"payer_email":          user.Email,
+		"description":          transaction.Description,
+		"success_redirect_url": p.redirectURL,
+		"failure_redirect_url": p.redirectURL,
+	}
+
+	var invoice xenditInvoice
+	err := p.do(http.MethodPost, "/v2/invoices", request, &invoice)
+	if err != nil {
+		return Charge{}, err
+	}
+	return Charge{PaymentURL: invoice.InvoiceURL, Reference: invoice.ID}, nil
+}
+
+func (p *xenditProvider) GetStatus(orderID string) (Notification, error) {
+	invoice, err := p.findInvoice(orderID)
+	if err != nil {
+		return Notification{}, err
+	}
+	return p.toNotification(invoice), nil
+}
+
+func (p *xenditProvider) Refund(orderID string, amount int, reason string) (string, error) {
+	invoice, err := p.findInvoice(orderID)
+	if err != nil {
+		return "", err
+	}
+
+	request := map[string]interface{}{
+		"invoice_id":   invoice.ID,
+		"reference_id": fmt.Sprintf("%s-refund-%d", orderID, time.Now().UnixNano()),
+		"amount":       money.New(amount, invoice.Currency).Major(),
+		"reason":       "REQUESTED_BY_CUSTOMER",
+		"metadata":     map[string]string{"reason": reason},
+	}
+
+	var refund struct {
+		ID string `json:"id"`
+	}
+	err = p.do(http.MethodPost, "/refunds", request, &refund)
+	if err != nil {
+		return "", err
+	}
+	return refund.ID, nil
+}
+
+// VerifyWebhook Xendit mengirim callback token (bukan signature) di header X-Callback-Token
+func (p *xenditProvider) VerifyWebhook(header http.Header, body []byte) (Notification, error) {
+	if p.callbackToken == "" {
+		return Notification{}, errors.New("XENDIT_CALLBACK_TOKEN is not set")
+	}
+	if subtle.ConstantTimeCompare([]byte(p.callbackToken), []byte(header.Get(XenditCallbackTokenHeader))) != 1 {
+		return Notification{}, ErrInvalidSignature
+	}
+
+	var invoice xenditInvoice
+	err := json.Unmarshal(body, &invoice)
+	if err != nil {
+		return Notification{}, err
+	}
+	return p.toNotification(invoice), nil
+}
+
+func (p *xenditProvider) findInvoice(orderID string) (xenditInvoice, error) {
+	var invoices []xenditInvoice
+	err := p.do(http.MethodGet, "/v2/invoices?external_id="+url.QueryEscape(orderID), nil, &invoices)
+	if err != nil {
+		return xenditInvoice{}, err
+	}
+	if len(invoices) == 0 {
+		return xenditInvoice{}, ErrPaymentNotFound
+	}
+	// invoice terbaru ada di urutan pertama
+	return invoices[0], nil
+}
+
+func (p *xenditProvider) toNotification(invoice xenditInvoice) Notification {
+	notification := Notification{}
+	notification.Provider = ProviderXendit
+	notification.EventKey = ProviderXendit + ":" + invoice.ID + ":" + invoice.Status
+	notification.OrderID = invoice.ExternalID
+	// amount Xendit dalam satuan utuh, dikembalikan ke minor unit mata uang invoice
+	notification.Amount = money.FromMajor(invoice.Amount, invoice.Currency).Amount
+	notification.GatewayFee = money.FromMajor(invoice.FeesPaidAmount, invoice.Currency).Amount
+	notification.RawStatus = invoice.Status
+
+	switch invoice.Status {
+	case "PAID", "SETTLED":
+		notification.Status = StatusPaid
+	case "EXPIRED":
+		notification.Status = StatusExpired
+	}
+	return notification
+}
+
+func (p *xenditProvider) do(method string, path string, request interface{}, response interface{}) error {
+	var body io.Reader
+	if request != nil {
+		payload, err := json.Marshal(request)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	httpRequest, err := http.NewRequest(method, p.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	httpRequest.SetBasicAuth(p.secretKey, "")
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	httpResponse, err := p.client.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	responseBody, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return err
+	}
+
+	if httpResponse.StatusCode >= 300 {
+		var xenditError struct {
+			ErrorCode string `json:"error_code"`
+			Message   string `json:"message"`
+		}
+		json.Unmarshal(responseBody, &xenditError)
+		return fmt.Errorf("Xendit error %d: %s %s", httpResponse.StatusCode, xenditError.ErrorCode, xenditError.Message)
+	}
+
+	return json.Unmarshal(responseBody, response)
+}
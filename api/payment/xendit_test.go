@@ -0,0 +1,221 @@
+package payment
+
+import (
+	"bwastartup/api/user"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+const (
+	testXenditSecretKey     = "xnd_development_contract"
+	testXenditCallbackToken = "xendit-callback-token"
+)
+
+func newTestXenditProvider(t *testing.T, routes map[string]fixtureRoute) (*xenditProvider, *fixtureServer) {
+	t.Helper()
+
+	server := newFixtureServer(t, "xendit", routes)
+	return NewXenditProvider(testXenditSecretKey, testXenditCallbackToken, server.URL, "https://bwastartup.test/payments/done"), server
+}
+
+func assertXenditAuth(t *testing.T, request recordedRequest) {
+	t.Helper()
+
+	httpRequest := http.Request{Header: request.Header}
+	username, password, ok := httpRequest.BasicAuth()
+	if !ok || username != testXenditSecretKey || password != "" {
+		t.Errorf("basic auth = %q:%q (%v)", username, password, ok)
+	}
+}
+
+func TestXenditCreateCharge(t *testing.T) {
+	provider, server := newTestXenditProvider(t, map[string]fixtureRoute{
+		"POST /v2/invoices": {Fixture: "invoice_create.json"},
+	})
+
+	charge, err := provider.CreateCharge(Transaction{ID: 1, OrderID: testOrderID, Amount: 50000, Currency: "IDR", Description: "Concurrent Campaign"}, user.User{Email: "backer@example.com"})
+	if err != nil {
+		t.Fatalf("CreateCharge: %v", err)
+	}
+
+	if charge.Reference != "6530e3f2a1b2c3d4e5f60718" {
+		t.Errorf("Reference = %q", charge.Reference)
+	}
+	if charge.PaymentURL != "https://checkout-staging.xendit.co/web/6530e3f2a1b2c3d4e5f60718" {
+		t.Errorf("PaymentURL = %q", charge.PaymentURL)
+	}
+
+	request := server.LastRequest(t, http.MethodPost, "/v2/invoices")
+	assertXenditAuth(t, request)
+
+	var body map[string]interface{}
+	err = json.Unmarshal(request.Body, &body)
+	if err != nil {
+		t.Fatalf("decode request body: %v", err)
+	}
+
+	if body["external_id"] != testOrderID {
+		t.Errorf("external_id = %v", body["external_id"])
+	}
+	// amount Xendit dalam satuan utuh mata uang
+	if body["amount"] != float64(50000) {
+		t.Errorf("amount = %v, want 50000", body["amount"])
+	}
+	if body["currency"] != "IDR" {
+		t.Errorf("currency = %v", body["currency"])
+	}
+	if body["payer_email"] != "backer@example.com" {
+		t.Errorf("payer_email = %v", body["payer_email"])
+	}
+}
+
+func TestXenditCreateChargeError(t *testing.T) {
+	provider, _ := newTestXenditProvider(t, map[string]fixtureRoute{
+		"POST /v2/invoices": {Status: http.StatusBadRequest, Fixture: "create_invoice_error.json"},
+	})
+
+	_, err := provider.CreateCharge(Transaction{ID: 1, OrderID: testOrderID, Amount: 50000, Currency: "IDR"}, user.User{})
+	if err == nil {
+		t.Fatal("CreateCharge error = nil, want provider error")
+	}
+	if err.Error() != "Xendit error 400: DUPLICATE_ERROR Invoice with external_id BWA-2026-7KQ2XD already exists" {
+		t.Errorf("CreateCharge error = %q", err.Error())
+	}
+}
+
+func TestXenditGetStatus(t *testing.T) {
+	provider, server := newTestXenditProvider(t, map[string]fixtureRoute{
+		"GET /v2/invoices": {Fixture: "invoices_paid.json"},
+	})
+
+	notification, err := provider.GetStatus(testOrderID)
+	if err != nil {
+		t.Fatalf("GetStatus: %v", err)
+	}
+
+	if notification.Status != StatusPaid {
+		t.Errorf("Status = %q, want %q", notification.Status, StatusPaid)
+	}
+	if notification.OrderID != testOrderID {
+		t.Errorf("OrderID = %q", notification.OrderID)
+	}
+	if notification.Amount != 50000 {
+		t.Errorf("Amount = %d, want 50000", notification.Amount)
+	}
+	if notification.GatewayFee != 4995 {
+		t.Errorf("GatewayFee = %d, want 4995", notification.GatewayFee)
+	}
+	if notification.EventKey != "xendit:6530e3f2a1b2c3d4e5f60718:PAID" {
+		t.Errorf("EventKey = %q", notification.EventKey)
+	}
+
+	request := server.LastRequest(t, http.MethodGet, "/v2/invoices")
+	assertXenditAuth(t, request)
+	if request.Query.Get("external_id") != testOrderID {
+		t.Errorf("external_id = %q", request.Query.Get("external_id"))
+	}
+}
+
+func TestXenditGetStatusNotFound(t *testing.T) {
+	provider, _ := newTestXenditProvider(t, map[string]fixtureRoute{
+		"GET /v2/invoices": {Fixture: "invoices_empty.json"},
+	})
+
+	_, err := provider.GetStatus(testOrderID)
+	if !errors.Is(err, ErrPaymentNotFound) {
+		t.Errorf("GetStatus error = %v, want ErrPaymentNotFound", err)
+	}
+}
+
+func TestXenditRefund(t *testing.T) {
+	provider, server := newTestXenditProvider(t, map[string]fixtureRoute{
+		"GET /v2/invoices": {Fixture: "invoices_paid.json"},
+		"POST /refunds":    {Fixture: "refund_create.json"},
+	})
+
+	reference, err := provider.Refund(testOrderID, 20000, "Backer request")
+	if err != nil {
+		t.Fatalf("Refund: %v", err)
+	}
+	if reference != "rfd-3a4b5c6d-7e8f-4a1b-9c2d-3e4f5a6b7c8d" {
+		t.Errorf("reference = %q", reference)
+	}
+
+	request := server.LastRequest(t, http.MethodPost, "/refunds")
+	assertXenditAuth(t, request)
+
+	var body map[string]interface{}
+	err = json.Unmarshal(request.Body, &body)
+	if err != nil {
+		t.Fatalf("decode request body: %v", err)
+	}
+
+	if body["invoice_id"] != "6530e3f2a1b2c3d4e5f60718" {
+		t.Errorf("invoice_id = %v", body["invoice_id"])
+	}
+	if body["amount"] != float64(20000) {
+		t.Errorf("amount = %v, want 20000", body["amount"])
+	}
+	if body["reason"] != "REQUESTED_BY_CUSTOMER" {
+		t.Errorf("reason = %v", body["reason"])
+	}
+}
+
+func TestXenditVerifyWebhook(t *testing.T) {
+	provider, _ := newTestXenditProvider(t, map[string]fixtureRoute{})
+	body := readFixture(t, "xendit", "webhook_invoice_paid.json")
+
+	header := http.Header{}
+	header.Set(XenditCallbackTokenHeader, testXenditCallbackToken)
+
+	notification, err := provider.VerifyWebhook(header, body)
+	if err != nil {
+		t.Fatalf("VerifyWebhook: %v", err)
+	}
+
+	if notification.Status != StatusPaid {
+		t.Errorf("Status = %q, want %q", notification.Status, StatusPaid)
+	}
+	if notification.OrderID != testOrderID {
+		t.Errorf("OrderID = %q", notification.OrderID)
+	}
+	if notification.Amount != 50000 {
+		t.Errorf("Amount = %d, want 50000", notification.Amount)
+	}
+	if notification.GatewayFee != 4995 {
+		t.Errorf("GatewayFee = %d, want 4995", notification.GatewayFee)
+	}
+}
+
+func TestXenditVerifyWebhookRejectsInvalidToken(t *testing.T) {
+	provider, _ := newTestXenditProvider(t, map[string]fixtureRoute{})
+	body := readFixture(t, "xendit", "webhook_invoice_paid.json")
+
+	for name, token := range map[string]string{"missing header": "", "wrong token": "other-token"} {
+		t.Run(name, func(t *testing.T) {
+			header := http.Header{}
+			if token != "" {
+				header.Set(XenditCallbackTokenHeader, token)
+			}
+
+			_, err := provider.VerifyWebhook(header, body)
+			if !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("VerifyWebhook error = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
+
+func TestXenditVerifyWebhookRequiresCallbackToken(t *testing.T) {
+	provider := NewXenditProvider(testXenditSecretKey, "", "http://localhost", "")
+
+	header := http.Header{}
+	header.Set(XenditCallbackTokenHeader, "")
+
+	_, err := provider.VerifyWebhook(header, readFixture(t, "xendit", "webhook_invoice_paid.json"))
+	if err == nil {
+		t.Error("VerifyWebhook error = nil, want error when XENDIT_CALLBACK_TOKEN is not set")
+	}
+}
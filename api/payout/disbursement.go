@@ -0,0 +1,85 @@
+package payout
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+const ProviderFake = "fake"
+
+var ErrInvalidCallback = errors.New("Invalid disbursement callback")
+
+// DisbursementResult hasil pengiriman payout ke provider. Status paid berarti dana sudah diterima,
+// processing berarti hasil akhir dikirim lewat callback
+type DisbursementResult struct {
+	Reference string
+	Status    string
+}
+
+// DisbursementNotification isi callback disbursement provider
+type DisbursementNotification struct {
+	Reference string
+	// PayoutID dari external ID yang kita kirim, dipakai jika callback datang sebelum Reference tersimpan
+	PayoutID int
+	// Status paid atau failed, kosong jika status belum final
+	Status string
+	Reason string
+}
+
+// Disburser disbursement provider yang mentransfer dana payout ke rekening creator
+type Disburser interface {
+	Name() string
+	Disburse(payout Payout) (DisbursementResult, error)
+	// VerifyCallback memeriksa keaslian callback lalu mengubah isinya menjadi DisbursementNotification
+	VerifyCallback(header http.Header, body []byte) (DisbursementNotification, error)
+}
+
+type Config struct {
+	// Provider disbursement provider: fake (default) atau xendit
+	Provider string
+
+	XenditSecretKey     string
+	XenditCallbackToken string
+	XenditBaseURL       string
+}
+
+func NewConfigFromEnv() Config {
+	config := Config{}
+	config.Provider = os.Getenv("PAYOUT_PROVIDER")
+	if config.Provider == "" {
+		config.Provider = ProviderFake
+	}
+	config.XenditSecretKey = os.Getenv("XENDIT_SECRET_KEY")
+	config.XenditCallbackToken = os.Getenv("XENDIT_CALLBACK_TOKEN")
+	config.XenditBaseURL = os.Getenv("XENDIT_BASE_URL")
+	return config
+}
+
+func NewDisburser(config Config) Disburser {
+	if config.Provider == ProviderXendit {
+		return NewXenditDisburser(config.XenditSecretKey, config.XenditCallbackToken, config.XenditBaseURL)
+	}
+	return NewFakeDisburser()
+}
+
+// fakeDisburser disbursement palsu untuk development, payout langsung dianggap terkirim
+type fakeDisburser struct {
+}
+
+func NewFakeDisburser() *fakeDisburser {
+	return &fakeDisburser{}
+}
+
+func (d *fakeDisburser) Name() string {
+	return ProviderFake
+}
+
+func (d *fakeDisburser) Disburse(payout Payout) (DisbursementResult, error) {
+	return DisbursementResult{Reference: "fake-payout-" + strconv.Itoa(payout.ID), Status: StatusPaid}, nil
+}
+
+func (d *fakeDisburser) VerifyCallback(header http.Header, body []byte) (DisbursementNotification, error) {
+	return DisbursementNotification{}, ErrInvalidCallback
+}
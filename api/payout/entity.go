@@ -0,0 +1,87 @@
+package payout
+
+import (
+	"bwastartup/api/campaign"
+	"bwastartup/api/money"
+	"bwastartup/api/user"
+	"time"
+)
+
+// status payout
+const (
+	// StatusRequested diajukan creator, menunggu persetujuan admin
+	StatusRequested = "requested"
+	// StatusApproved disetujui admin, dana sudah dipindahkan dari escrow dan sedang dikirim ke disbursement provider
+	StatusApproved = "approved"
+	// StatusProcessing diterima disbursement provider, menunggu callback
+	StatusProcessing = "processing"
+	StatusPaid       = "paid"
+	StatusRejected   = "rejected"
+	// StatusFailed gagal dikirim, dana dikembalikan ke escrow campaign
+	StatusFailed = "failed"
+)
+
+// BankAccount rekening tujuan payout milik creator
+type BankAccount struct {
+	ID                int
+	UserID            int
+	BankCode          string
+	AccountNumber     string
+	AccountHolderName string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// Payout penarikan dana campaign oleh creator. Data rekening disalin saat payout diajukan
+// sehingga riwayat tetap benar walaupun rekening dihapus
+type Payout struct {
+	ID                int
+	UserID            int
+	CampaignID        int
+	BankAccountID     int
+	Amount            int
+	Status            string
+	BankCode          string
+	AccountNumber     string
+	AccountHolderName string
+	Provider          string
+	ProviderReference string
+	// Note alasan penolakan atau kegagalan
+	Note             string
+	ReviewedByUserID int
+	ReviewedAt       *time.Time
+	PaidAt           *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Campaign         campaign.Campaign
+	User             user.User
+}
+
+// AmountFormatted amount payout dalam mata uang campaign
+func (p Payout) AmountFormatted() string {
+	return money.New(p.Amount, p.Campaign.Currency).Format()
+}
+
+// Balance dana campaign yang bisa ditarik
+type Balance struct {
+	CampaignID   int
+	CampaignName string
+	Currency     string
+	// EscrowAmount dana di escrow campaign menurut ledger (setelah fee, refund dan payout yang disetujui)
+	EscrowAmount int
+	// RequestedAmount payout yang masih menunggu persetujuan
+	RequestedAmount int
+	AvailableAmount int
+}
+
+// MaskedAccountNumber hanya menampilkan 4 digit terakhir nomor rekening
+func MaskedAccountNumber(accountNumber string) string {
+	if len(accountNumber) <= 4 {
+		return accountNumber
+	}
+	masked := make([]byte, len(accountNumber)-4)
+	for i := range masked {
+		masked[i] = '*'
+	}
+	return string(masked) + accountNumber[len(accountNumber)-4:]
+}
@@ -0,0 +1,92 @@
+package payout
+
+import (
+	"bwastartup/api/money"
+	"time"
+)
+
+type BankAccountFormatter struct {
+	ID                int    `json:"id"`
+	BankCode          string `json:"bank_code"`
+	AccountNumber     string `json:"account_number"`
+	AccountHolderName string `json:"account_holder_name"`
+}
+
+func FormatBankAccount(bankAccount BankAccount) BankAccountFormatter {
+	formatter := BankAccountFormatter{}
+	formatter.ID = bankAccount.ID
+	formatter.BankCode = bankAccount.BankCode
+	formatter.AccountNumber = MaskedAccountNumber(bankAccount.AccountNumber)
+	formatter.AccountHolderName = bankAccount.AccountHolderName
+	return formatter
+}
+
+func FormatBankAccounts(bankAccounts []BankAccount) []BankAccountFormatter {
+	bankAccountsFormatter := []BankAccountFormatter{}
+
+	for _, bankAccount := range bankAccounts {
+		bankAccountsFormatter = append(bankAccountsFormatter, FormatBankAccount(bankAccount))
+	}
+	return bankAccountsFormatter
+}
+
+type PayoutFormatter struct {
+	ID                int        `json:"id"`
+	CampaignID        int        `json:"campaign_id"`
+	CampaignName      string     `json:"campaign_name"`
+	Amount            int        `json:"amount"`
+	Currency          string     `json:"currency"`
+	Status            string     `json:"status"`
+	BankCode          string     `json:"bank_code"`
+	AccountNumber     string     `json:"account_number"`
+	AccountHolderName string     `json:"account_holder_name"`
+	Note              string     `json:"note"`
+	CreatedAt         time.Time  `json:"created_at"`
+	ReviewedAt        *time.Time `json:"reviewed_at"`
+	PaidAt            *time.Time `json:"paid_at"`
+}
+
+func FormatPayout(payout Payout) PayoutFormatter {
+	formatter := PayoutFormatter{}
+	formatter.ID = payout.ID
+	formatter.CampaignID = payout.CampaignID
+	formatter.CampaignName = payout.Campaign.Name
+	formatter.Amount = payout.Amount
+	formatter.Currency = money.NormalizeCurrency(payout.Campaign.Currency)
+	formatter.Status = payout.Status
+	formatter.BankCode = payout.BankCode
+	formatter.AccountNumber = MaskedAccountNumber(payout.AccountNumber)
+	formatter.AccountHolderName = payout.AccountHolderName
+	formatter.Note = payout.Note
+	formatter.CreatedAt = payout.CreatedAt
+	formatter.ReviewedAt = payout.ReviewedAt
+	formatter.PaidAt = payout.PaidAt
+	return formatter
+}
+
+func FormatPayouts(payouts []Payout) []PayoutFormatter {
+	payoutsFormatter := []PayoutFormatter{}
+
+	for _, payout := range payouts {
+		payoutsFormatter = append(payoutsFormatter, FormatPayout(payout))
+	}
+	return payoutsFormatter
+}
+
+type BalanceFormatter struct {
+	CampaignID      int    `json:"campaign_id"`
+	CampaignName    string `json:"campaign_name"`
+	Currency        string `json:"currency"`
+	EscrowAmount    int    `json:"escrow_amount"`
+	RequestedAmount int    `json:"requested_amount"`
+	AvailableAmount int    `json:"available_amount"`
+}
+
+func FormatBalances(balances []Balance) []BalanceFormatter {
+	balancesFormatter := []BalanceFormatter{}
+
+	for _, balance := range balances {
+		balancesFormatter = append(balancesFormatter, BalanceFormatter{balance.CampaignID, balance.CampaignName, balance.Currency, balance.EscrowAmount, balance.RequestedAmount, balance.AvailableAmount})
+	}
+	return balancesFormatter
+}
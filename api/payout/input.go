@@ -0,0 +1,32 @@
+package payout
+
+import (
+	"bwastartup/api/user"
+	"net/http"
+)
+
+type CreateBankAccountInput struct {
+	BankCode          string `json:"bank_code" binding:"required"`
+	AccountNumber     string `json:"account_number" binding:"required,numeric"`
+	AccountHolderName string `json:"account_holder_name" binding:"required"`
+	User              user.User
+}
+
+type GetBankAccountInput struct {
+	ID   int `uri:"id" binding:"required"`
+	User user.User
+}
+
+type CreatePayoutInput struct {
+	CampaignID    int `json:"campaign_id" binding:"required"`
+	BankAccountID int `json:"bank_account_id" binding:"required"`
+	Amount        int `json:"amount" binding:"required"`
+	User          user.User
+}
+
+// PayoutNotificationInput callback mentah dari disbursement provider
+type PayoutNotificationInput struct {
+	Provider string
+	Header   http.Header
+	Body     []byte
+}
@@ -0,0 +1,150 @@
+package payout
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	SaveBankAccount(bankAccount BankAccount) (BankAccount, error)
+	FindBankAccountByID(ID int) (BankAccount, error)
+	FindBankAccountsByUserID(userID int) ([]BankAccount, error)
+	DeleteBankAccount(bankAccount BankAccount) error
+	Save(payout Payout) (Payout, error)
+	FindByID(ID int) (Payout, error)
+	FindByUserID(userID int) ([]Payout, error)
+	FindByProviderReference(provider string, reference string) (Payout, error)
+	FindAll() ([]Payout, error)
+	UpdateStatus(payout Payout, fromStatus string) (bool, error)
+	GetRequestedAmount(campaignID int) (int, error)
+	LockCampaign(campaignID int) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *repository {
+	return &repository{db}
+}
+
+func (r *repository) SaveBankAccount(bankAccount BankAccount) (BankAccount, error) {
+	err := r.db.Create(&bankAccount).Error
+	if err != nil {
+		return bankAccount, err
+	}
+	return bankAccount, nil
+}
+
+func (r *repository) FindBankAccountByID(ID int) (BankAccount, error) {
+	var bankAccount BankAccount
+
+	err := r.db.Where("id = ?", ID).Find(&bankAccount).Error
+	if err != nil {
+		return bankAccount, err
+	}
+	return bankAccount, nil
+}
+
+func (r *repository) FindBankAccountsByUserID(userID int) ([]BankAccount, error) {
+	var bankAccounts []BankAccount
+
+	err := r.db.Where("user_id = ?", userID).Order("id desc").Find(&bankAccounts).Error
+	if err != nil {
+		return bankAccounts, err
+	}
+	return bankAccounts, nil
+}
+
+func (r *repository) DeleteBankAccount(bankAccount BankAccount) error {
+	err := r.db.Delete(&bankAccount).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *repository) Save(payout Payout) (Payout, error) {
+	err := r.db.Omit("Campaign", "User").Create(&payout).Error
+	if err != nil {
+		return payout, err
+	}
+	return payout, nil
+}
+
+func (r *repository) FindByID(ID int) (Payout, error) {
+	var payout Payout
+
+	err := r.db.Preload("Campaign").Preload("User").Where("id = ?", ID).Find(&payout).Error
+	if err != nil {
+		return payout, err
+	}
+	return payout, nil
+}
+
+func (r *repository) FindByUserID(userID int) ([]Payout, error) {
+	var payouts []Payout
+
+	err := r.db.Preload("Campaign").Where("user_id = ?", userID).Order("id desc").Find(&payouts).Error
+	if err != nil {
+		return payouts, err
+	}
+	return payouts, nil
+}
+
+func (r *repository) FindByProviderReference(provider string, reference string) (Payout, error) {
+	var payout Payout
+
+	err := r.db.Where("provider = ? AND provider_reference = ?", provider, reference).Find(&payout).Error
+	if err != nil {
+		return payout, err
+	}
+	return payout, nil
+}
+
+// FindAll payout yang menunggu persetujuan ditampilkan lebih dulu
+func (r *repository) FindAll() ([]Payout, error) {
+	var payouts []Payout
+
+	err := r.db.Preload("Campaign").Preload("User").
+		Clauses(clause.OrderBy{Expression: clause.Expr{SQL: "status = ? DESC, id DESC", Vars: []interface{}{StatusRequested}, WithoutParentheses: true}}).
+		Find(&payouts).Error
+	if err != nil {
+		return payouts, err
+	}
+	return payouts, nil
+}
+
+// UpdateStatus menyimpan status dan data review/disbursement hanya jika status saat ini masih fromStatus
+func (r *repository) UpdateStatus(payout Payout, fromStatus string) (bool, error) {
+	result := r.db.Model(&Payout{}).Where("id = ? AND status = ?", payout.ID, fromStatus).
+		Select("status", "provider", "provider_reference", "note", "reviewed_by_user_id", "reviewed_at", "paid_at").
+		Updates(&payout)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *repository) GetRequestedAmount(campaignID int) (int, error) {
+	var amount int
+
+	err := r.db.Model(&Payout{}).Where("campaign_id = ? AND status = ?", campaignID, StatusRequested).
+		Select("COALESCE(SUM(amount), 0)").Scan(&amount).Error
+	if err != nil {
+		return 0, err
+	}
+	return amount, nil
+}
+
+// LockCampaign mengunci baris campaign sampai transaksi database selesai,
+// supaya pengajuan payout yang bersamaan tidak melebihi saldo
+func (r *repository) LockCampaign(campaignID int) error {
+	var campaignIDs []int
+
+	err := r.db.Table("campaigns").Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", campaignID).Pluck("id", &campaignIDs).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
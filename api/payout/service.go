@@ -0,0 +1,378 @@
+package payout
+
+import (
+	"bwastartup/api/campaign"
+	"bwastartup/api/ledger"
+	"bwastartup/api/money"
+	"errors"
+	"time"
+)
+
+type Service interface {
+	SaveBankAccount(input CreateBankAccountInput) (BankAccount, error)
+	GetBankAccounts(userID int) ([]BankAccount, error)
+	DeleteBankAccount(input GetBankAccountInput) error
+	GetBalances(userID int) ([]Balance, error)
+	RequestPayout(input CreatePayoutInput) (Payout, error)
+	GetPayoutsByUserID(userID int) ([]Payout, error)
+	GetAllPayouts() ([]Payout, error)
+	ApprovePayout(ID int, adminUserID int) (Payout, error)
+	RejectPayout(ID int, adminUserID int, reason string) (Payout, error)
+	ProcessNotification(input PayoutNotificationInput) error
+}
+
+type service struct {
+	repository         Repository
+	campaignRepository campaign.Repository
+	ledgerService      ledger.Service
+	unitOfWork         UnitOfWork
+	disburser          Disburser
+}
+
+func NewService(repository Repository, campaignRepository campaign.Repository, ledgerService ledger.Service, unitOfWork UnitOfWork, disburser Disburser) *service {
+	return &service{repository, campaignRepository, ledgerService, unitOfWork, disburser}
+}
+
+func (s *service) SaveBankAccount(input CreateBankAccountInput) (BankAccount, error) {
+	bankAccount := BankAccount{}
+	bankAccount.UserID = input.User.ID
+	bankAccount.BankCode = input.BankCode
+	bankAccount.AccountNumber = input.AccountNumber
+	bankAccount.AccountHolderName = input.AccountHolderName
+
+	return s.repository.SaveBankAccount(bankAccount)
+}
+
+func (s *service) GetBankAccounts(userID int) ([]BankAccount, error) {
+	return s.repository.FindBankAccountsByUserID(userID)
+}
+
+func (s *service) DeleteBankAccount(input GetBankAccountInput) error {
+	bankAccount, err := s.repository.FindBankAccountByID(input.ID)
+	if err != nil {
+		return err
+	}
+
+	if bankAccount.ID == 0 {
+		return errors.New("Bank account not found")
+	}
+
+	if bankAccount.UserID != input.User.ID {
+		return errors.New("Not an owner of the bank account")
+	}
+
+	return s.repository.DeleteBankAccount(bankAccount)
+}
+
+// GetBalances saldo yang bisa ditarik untuk setiap campaign milik creator
+func (s *service) GetBalances(userID int) ([]Balance, error) {
+	balances := []Balance{}
+
+	campaigns, err := s.campaignRepository.FindByUserID(userID, "desc", "", "")
+	if err != nil {
+		return balances, err
+	}
+
+	for _, campaign := range campaigns {
+		balance, err := s.getBalance(s.repository, s.ledgerService, campaign)
+		if err != nil {
+			return balances, err
+		}
+		balances = append(balances, balance)
+	}
+	return balances, nil
+}
+
+func (s *service) getBalance(repository Repository, ledgerService ledger.Service, campaign campaign.Campaign) (Balance, error) {
+	balance := Balance{}
+	balance.CampaignID = campaign.ID
+	balance.CampaignName = campaign.Name
+	balance.Currency = money.NormalizeCurrency(campaign.Currency)
+
+	escrowAmount, err := ledgerService.GetCampaignEscrowBalance(campaign.ID)
+	if err != nil {
+		return balance, err
+	}
+
+	requestedAmount, err := repository.GetRequestedAmount(campaign.ID)
+	if err != nil {
+		return balance, err
+	}
+
+	balance.EscrowAmount = escrowAmount
+	balance.RequestedAmount = requestedAmount
+	balance.AvailableAmount = escrowAmount - requestedAmount
+	if balance.AvailableAmount < 0 {
+		balance.AvailableAmount = 0
+	}
+	return balance, nil
+}
+
+func (s *service) RequestPayout(input CreatePayoutInput) (Payout, error) {
+	payout := Payout{}
+
+	if input.Amount <= 0 {
+		return payout, errors.New("Payout amount must be greater than zero")
+	}
+
+	campaign, err := s.campaignRepository.FindByID(input.CampaignID)
+	if err != nil {
+		return payout, err
+	}
+
+	if campaign.ID == 0 || campaign.UserID != input.User.ID {
+		return payout, errors.New("Not an owner of the campaign")
+	}
+
+	bankAccount, err := s.repository.FindBankAccountByID(input.BankAccountID)
+	if err != nil {
+		return payout, err
+	}
+
+	if bankAccount.ID == 0 || bankAccount.UserID != input.User.ID {
+		return payout, errors.New("Not an owner of the bank account")
+	}
+
+	payout.UserID = input.User.ID
+	payout.CampaignID = campaign.ID
+	payout.BankAccountID = bankAccount.ID
+	payout.Amount = input.Amount
+	payout.Status = StatusRequested
+	payout.BankCode = bankAccount.BankCode
+	payout.AccountNumber = bankAccount.AccountNumber
+	payout.AccountHolderName = bankAccount.AccountHolderName
+
+	// saldo dihitung ulang saat baris campaign terkunci supaya pengajuan bersamaan tidak melebihi saldo
+	err = s.unitOfWork.Do(func(repository Repository, ledgerService ledger.Service) error {
+		err := repository.LockCampaign(campaign.ID)
+		if err != nil {
+			return err
+		}
+
+		balance, err := s.getBalance(repository, ledgerService, campaign)
+		if err != nil {
+			return err
+		}
+
+		if payout.Amount > balance.AvailableAmount {
+			return errors.New("Payout amount exceeds the available balance")
+		}
+
+		payout, err = repository.Save(payout)
+		return err
+	})
+	if err != nil {
+		return payout, err
+	}
+
+	payout.Campaign = campaign
+	return payout, nil
+}
+
+func (s *service) GetPayoutsByUserID(userID int) ([]Payout, error) {
+	return s.repository.FindByUserID(userID)
+}
+
+func (s *service) GetAllPayouts() ([]Payout, error) {
+	return s.repository.FindAll()
+}
+
+// ApprovePayout dana dipindahkan dari escrow campaign ke creator payable lalu dikirim ke disbursement provider.
+// Jika pengiriman gagal, payout ditandai failed dan dana dikembalikan ke escrow
+func (s *service) ApprovePayout(ID int, adminUserID int) (Payout, error) {
+	if adminUserID <= 0 {
+		return Payout{}, errors.New("Admin user is required to review a payout")
+	}
+
+	payout, err := s.repository.FindByID(ID)
+	if err != nil {
+		return payout, err
+	}
+
+	if payout.ID == 0 {
+		return payout, errors.New("Payout not found")
+	}
+
+	if payout.Status != StatusRequested {
+		return payout, errors.New("Only requested payouts can be approved")
+	}
+
+	now := time.Now()
+	payout.Status = StatusApproved
+	payout.Provider = s.disburser.Name()
+	payout.ReviewedByUserID = adminUserID
+	payout.ReviewedAt = &now
+
+	err = s.unitOfWork.Do(func(repository Repository, ledgerService ledger.Service) error {
+		err := repository.LockCampaign(payout.CampaignID)
+		if err != nil {
+			return err
+		}
+
+		// escrow bisa berkurang karena refund setelah payout diajukan
+		escrowAmount, err := ledgerService.GetCampaignEscrowBalance(payout.CampaignID)
+		if err != nil {
+			return err
+		}
+
+		if payout.Amount > escrowAmount {
+			return errors.New("Payout amount exceeds the campaign escrow balance")
+		}
+
+		isUpdated, err := repository.UpdateStatus(payout, StatusRequested)
+		if err != nil {
+			return err
+		}
+		if !isUpdated {
+			return errors.New("Payout has been changed, please try again")
+		}
+
+		return ledgerService.PostPayout(payout.ID, payout.CampaignID, payout.UserID, payout.Amount)
+	})
+	if err != nil {
+		return payout, err
+	}
+
+	result, disburseErr := s.disburser.Disburse(payout)
+	if disburseErr != nil {
+		payout, err = s.failPayout(payout, StatusApproved, disburseErr.Error())
+		if err != nil {
+			return payout, err
+		}
+		return payout, errors.New("Payout disbursement failed: " + disburseErr.Error())
+	}
+
+	payout.ProviderReference = result.Reference
+	if result.Status == StatusPaid {
+		return s.completePayout(payout, StatusApproved)
+	}
+
+	payout.Status = StatusProcessing
+	isUpdated, err := s.repository.UpdateStatus(payout, StatusApproved)
+	if err != nil {
+		return payout, err
+	}
+
+	// callback provider bisa datang lebih dulu dan sudah menyelesaikan payout
+	if !isUpdated {
+		return s.repository.FindByID(payout.ID)
+	}
+	return payout, nil
+}
+
+func (s *service) RejectPayout(ID int, adminUserID int, reason string) (Payout, error) {
+	if adminUserID <= 0 {
+		return Payout{}, errors.New("Admin user is required to review a payout")
+	}
+
+	payout, err := s.repository.FindByID(ID)
+	if err != nil {
+		return payout, err
+	}
+
+	if payout.ID == 0 {
+		return payout, errors.New("Payout not found")
+	}
+
+	if payout.Status != StatusRequested {
+		return payout, errors.New("Only requested payouts can be rejected")
+	}
+
+	now := time.Now()
+	payout.Status = StatusRejected
+	payout.Note = reason
+	payout.ReviewedByUserID = adminUserID
+	payout.ReviewedAt = &now
+
+	isUpdated, err := s.repository.UpdateStatus(payout, StatusRequested)
+	if err != nil {
+		return payout, err
+	}
+	if !isUpdated {
+		return payout, errors.New("Payout has been changed, please try again")
+	}
+	return payout, nil
+}
+
+// ProcessNotification menyelesaikan payout berstatus processing dari callback disbursement provider.
+// Payout yang masih approved juga diproses karena callback bisa datang sebelum Disburse selesai
+func (s *service) ProcessNotification(input PayoutNotificationInput) error {
+	if input.Provider != s.disburser.Name() {
+		return ErrInvalidCallback
+	}
+
+	notification, err := s.disburser.VerifyCallback(input.Header, input.Body)
+	if err != nil {
+		return err
+	}
+
+	if notification.Status == "" {
+		return nil
+	}
+
+	payout, err := s.repository.FindByProviderReference(input.Provider, notification.Reference)
+	if err != nil {
+		return err
+	}
+
+	// reference dari provider belum tersimpan jika callback datang sebelum Disburse selesai
+	if payout.ID == 0 && notification.PayoutID > 0 {
+		payout, err = s.repository.FindByID(notification.PayoutID)
+		if err != nil {
+			return err
+		}
+
+		if payout.Provider != input.Provider || (payout.ProviderReference != "" && payout.ProviderReference != notification.Reference) {
+			payout = Payout{}
+		}
+		payout.ProviderReference = notification.Reference
+	}
+
+	if payout.ID == 0 {
+		return errors.New("Payout not found")
+	}
+
+	// callback yang dikirim ulang diabaikan
+	if payout.Status != StatusApproved && payout.Status != StatusProcessing {
+		return nil
+	}
+
+	if notification.Status == StatusPaid {
+		_, err = s.completePayout(payout, payout.Status)
+		return err
+	}
+
+	_, err = s.failPayout(payout, payout.Status, notification.Reason)
+	return err
+}
+
+func (s *service) completePayout(payout Payout, fromStatus string) (Payout, error) {
+	now := time.Now()
+	payout.Status = StatusPaid
+	payout.PaidAt = &now
+
+	err := s.unitOfWork.Do(func(repository Repository, ledgerService ledger.Service) error {
+		isUpdated, err := repository.UpdateStatus(payout, fromStatus)
+		if err != nil || !isUpdated {
+			return err
+		}
+
+		return ledgerService.PostPayoutDisbursement(payout.ID, payout.CampaignID, payout.UserID, payout.Amount)
+	})
+	return payout, err
+}
+
+func (s *service) failPayout(payout Payout, fromStatus string, reason string) (Payout, error) {
+	payout.Status = StatusFailed
+	payout.Note = reason
+
+	err := s.unitOfWork.Do(func(repository Repository, ledgerService ledger.Service) error {
+		isUpdated, err := repository.UpdateStatus(payout, fromStatus)
+		if err != nil || !isUpdated {
+			return err
+		}
+
+		return ledgerService.PostPayoutReversal(payout.ID, payout.CampaignID, payout.UserID, payout.Amount)
+	})
+	return payout, err
+}
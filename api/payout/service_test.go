@@ -0,0 +1,147 @@
+package payout
+
+import (
+	"bwastartup/api/campaign"
+	"bwastartup/api/ledger"
+	"bwastartup/api/money"
+	"bwastartup/api/user"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := "file:" + filepath.Join(t.TempDir(), "bwastartup.db") + "?_busy_timeout=10000&_txlock=immediate"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger:                                   logger.Default.LogMode(logger.Silent),
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+
+	err = db.AutoMigrate(&user.User{}, &campaign.Campaign{}, &BankAccount{}, &Payout{}, &ledger.Account{}, &ledger.JournalEntry{}, &ledger.JournalLine{})
+	if err != nil {
+		t.Fatalf("migrate database: %v", err)
+	}
+
+	for _, statement := range []string{
+		"CREATE UNIQUE INDEX ledger_accounts_code_unique ON ledger_accounts (code)",
+		"CREATE UNIQUE INDEX journal_entries_reference_unique ON journal_entries (reference)",
+	} {
+		err = db.Exec(statement).Error
+		if err != nil {
+			t.Fatalf("create index: %v", err)
+		}
+	}
+	return db
+}
+
+// callbackFirstDisburser disbursement provider yang mengirim callback hasil transfer
+// sebelum request disbursement selesai dijawab
+type callbackFirstDisburser struct {
+	service      *service
+	notification DisbursementNotification
+	callbackErr  error
+}
+
+func (d *callbackFirstDisburser) Name() string {
+	return "test"
+}
+
+func (d *callbackFirstDisburser) Disburse(payout Payout) (DisbursementResult, error) {
+	d.callbackErr = d.service.ProcessNotification(PayoutNotificationInput{Provider: d.Name()})
+	return DisbursementResult{Reference: d.notification.Reference, Status: StatusProcessing}, nil
+}
+
+func (d *callbackFirstDisburser) VerifyCallback(header http.Header, body []byte) (DisbursementNotification, error) {
+	return d.notification, nil
+}
+
+func TestApprovePayoutCallbackBeforeProcessing(t *testing.T) {
+	cases := []struct {
+		name       string
+		status     string
+		wantStatus string
+		// wantEscrow saldo escrow campaign setelah payout selesai, dana 100.000 dan payout 40.000
+		wantEscrow int
+	}{
+		{"paid callback", StatusPaid, StatusPaid, 60000},
+		{"failed callback", StatusFailed, StatusFailed, 100000},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db := newTestDB(t)
+
+			testCampaign := campaign.Campaign{UserID: 1, Name: "Payout Campaign", Slug: "payout-campaign", GoalAmount: 1000000, Currency: money.DefaultCurrency}
+			err := db.Create(&testCampaign).Error
+			if err != nil {
+				t.Fatalf("create campaign: %v", err)
+			}
+
+			ledgerService := ledger.NewService(ledger.NewRepository(db))
+			err = ledgerService.PostPayment(1, testCampaign.ID, 2, 100000)
+			if err != nil {
+				t.Fatalf("post payment: %v", err)
+			}
+
+			repository := NewRepository(db)
+			requestedPayout, err := repository.Save(Payout{UserID: 1, CampaignID: testCampaign.ID, Amount: 40000, Status: StatusRequested, BankCode: "BCA", AccountNumber: "1234567890", AccountHolderName: "Creator"})
+			if err != nil {
+				t.Fatalf("save payout: %v", err)
+			}
+
+			disburser := &callbackFirstDisburser{}
+			disburser.notification = DisbursementNotification{Reference: "disb-1", PayoutID: requestedPayout.ID, Status: c.status, Reason: "INVALID_DESTINATION"}
+			service := NewService(repository, campaign.NewRepository(db), ledgerService, NewUnitOfWork(db), disburser)
+			disburser.service = service
+
+			approvedPayout, err := service.ApprovePayout(requestedPayout.ID, 99)
+			if err != nil {
+				t.Fatalf("ApprovePayout: %v", err)
+			}
+			if disburser.callbackErr != nil {
+				t.Fatalf("ProcessNotification: %v", disburser.callbackErr)
+			}
+			if approvedPayout.Status != c.wantStatus {
+				t.Errorf("ApprovePayout status = %s, want %s", approvedPayout.Status, c.wantStatus)
+			}
+
+			storedPayout, err := repository.FindByID(requestedPayout.ID)
+			if err != nil {
+				t.Fatalf("find payout: %v", err)
+			}
+			if storedPayout.Status != c.wantStatus || storedPayout.ProviderReference != "disb-1" {
+				t.Errorf("stored payout = %s (%q), want %s (%q)", storedPayout.Status, storedPayout.ProviderReference, c.wantStatus, "disb-1")
+			}
+
+			escrowAmount, err := ledgerService.GetCampaignEscrowBalance(testCampaign.ID)
+			if err != nil {
+				t.Fatalf("escrow balance: %v", err)
+			}
+			if escrowAmount != c.wantEscrow {
+				t.Errorf("escrow = %d, want %d", escrowAmount, c.wantEscrow)
+			}
+
+			// callback yang dikirim ulang setelah payout selesai tidak mengubah apa pun
+			err = service.ProcessNotification(PayoutNotificationInput{Provider: disburser.Name()})
+			if err != nil {
+				t.Fatalf("resent callback: %v", err)
+			}
+			escrowAmount, err = ledgerService.GetCampaignEscrowBalance(testCampaign.ID)
+			if err != nil {
+				t.Fatalf("escrow balance: %v", err)
+			}
+			if escrowAmount != c.wantEscrow {
+				t.Errorf("escrow after resent callback = %d, want %d", escrowAmount, c.wantEscrow)
+			}
+		})
+	}
+}
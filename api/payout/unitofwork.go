@@ -0,0 +1,26 @@
+package payout
+
+import (
+	"bwastartup/api/ledger"
+
+	"gorm.io/gorm"
+)
+
+// UnitOfWork menjalankan operasi repository payout dan ledger dalam satu transaksi database
+type UnitOfWork interface {
+	Do(fn func(repository Repository, ledgerService ledger.Service) error) error
+}
+
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) *unitOfWork {
+	return &unitOfWork{db}
+}
+
+func (u *unitOfWork) Do(fn func(repository Repository, ledgerService ledger.Service) error) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		return fn(NewRepository(tx), ledger.NewService(ledger.NewRepository(tx)))
+	})
+}
@@ -0,0 +1,149 @@
+package payout
+
+import (
+	"bwastartup/api/money"
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ProviderXendit = "xendit"
+
+const xenditCallbackTokenHeader = "X-Callback-Token"
+
+const xenditExternalIDPrefix = "payout-"
+
+// xenditDisburser adapter Xendit Disbursement API, hasil akhir transfer dikirim lewat callback
+type xenditDisburser struct {
+	secretKey     string
+	callbackToken string
+	baseURL       string
+	client        *http.Client
+}
+
+func NewXenditDisburser(secretKey string, callbackToken string, baseURL string) *xenditDisburser {
+	if baseURL == "" {
+		baseURL = "https://api.xendit.co"
+	}
+	return &xenditDisburser{secretKey, callbackToken, baseURL, &http.Client{Timeout: 30 * time.Second}}
+}
+
+type xenditDisbursement struct {
+	ID            string `json:"id"`
+	ExternalID    string `json:"external_id"`
+	Status        string `json:"status"`
+	FailureCode   string `json:"failure_code"`
+	FailureReason string `json:"failure_reason"`
+}
+
+func (d *xenditDisburser) Name() string {
+	return ProviderXendit
+}
+
+func (d *xenditDisburser) Disburse(payout Payout) (DisbursementResult, error) {
+	// Xendit Disbursement hanya mentransfer rupiah ke rekening bank lokal
+	if money.NormalizeCurrency(payout.Campaign.Currency) != "IDR" {
+		return DisbursementResult{}, errors.New("Xendit disbursement only supports IDR payouts")
+	}
+
+	externalID := xenditExternalIDPrefix + strconv.Itoa(payout.ID)
+	request := map[string]interface{}{
+		"external_id":         externalID,
+		"amount":              payout.Amount,
+		"bank_code":           payout.BankCode,
+		"account_holder_name": payout.AccountHolderName,
+		"account_number":      payout.AccountNumber,
+		"description":         "Payout campaign " + strconv.Itoa(payout.CampaignID),
+	}
+
+	var disbursement xenditDisbursement
+	// idempotency key mencegah transfer ganda jika request dikirim ulang
+	err := d.do(http.MethodPost, "/disbursements", externalID, request, &disbursement)
+	if err != nil {
+		return DisbursementResult{}, err
+	}
+
+	result := DisbursementResult{Reference: disbursement.ID, Status: StatusProcessing}
+	switch disbursement.Status {
+	case "COMPLETED":
+		result.Status = StatusPaid
+	case "FAILED":
+		return result, fmt.Errorf("Xendit disbursement failed: %s", disbursement.FailureCode)
+	}
+	return result, nil
+}
+
+func (d *xenditDisburser) VerifyCallback(header http.Header, body []byte) (DisbursementNotification, error) {
+	if d.callbackToken == "" {
+		return DisbursementNotification{}, errors.New("XENDIT_CALLBACK_TOKEN is not set")
+	}
+	if subtle.ConstantTimeCompare([]byte(d.callbackToken), []byte(header.Get(xenditCallbackTokenHeader))) != 1 {
+		return DisbursementNotification{}, ErrInvalidCallback
+	}
+
+	var disbursement xenditDisbursement
+	err := json.Unmarshal(body, &disbursement)
+	if err != nil {
+		return DisbursementNotification{}, err
+	}
+
+	notification := DisbursementNotification{Reference: disbursement.ID}
+	if strings.HasPrefix(disbursement.ExternalID, xenditExternalIDPrefix) {
+		notification.PayoutID, _ = strconv.Atoi(strings.TrimPrefix(disbursement.ExternalID, xenditExternalIDPrefix))
+	}
+	switch disbursement.Status {
+	case "COMPLETED":
+		notification.Status = StatusPaid
+	case "FAILED":
+		notification.Status = StatusFailed
+		notification.Reason = disbursement.FailureCode
+		if disbursement.FailureReason != "" {
+			notification.Reason = disbursement.FailureReason
+		}
+	}
+	return notification, nil
+}
+
+func (d *xenditDisburser) do(method string, path string, idempotencyKey string, request interface{}, response interface{}) error {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	httpRequest, err := http.NewRequest(method, d.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	httpRequest.SetBasicAuth(d.secretKey, "")
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("X-IDEMPOTENCY-KEY", idempotencyKey)
+
+	httpResponse, err := d.client.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	responseBody, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return err
+	}
+
+	if httpResponse.StatusCode >= 300 {
+		var xenditError struct {
+			ErrorCode string `json:"error_code"`
+			Message   string `json:"message"`
+		}
+		json.Unmarshal(responseBody, &xenditError)
+		return fmt.Errorf("Xendit error %d: %s %s", httpResponse.StatusCode, xenditError.ErrorCode, xenditError.Message)
+	}
+
+	return json.Unmarshal(responseBody, response)
+}
@@ -0,0 +1,69 @@
+package reconciliation
+
+import "time"
+
+// jenis selisih antara laporan settlement provider dan transaksi kita
+const (
+	// DiscrepancyMissingTransaction ada di laporan provider tetapi tidak ada transaksi dengan kode tersebut
+	DiscrepancyMissingTransaction = "missing_transaction"
+	// DiscrepancyMissingSettlement transaksi sudah dibayar menurut kita tetapi tidak ada di laporan provider
+	DiscrepancyMissingSettlement = "missing_settlement"
+	// DiscrepancyProviderMismatch kode order ada tetapi transaksinya dibayar lewat provider lain
+	DiscrepancyProviderMismatch = "provider_mismatch"
+	DiscrepancyAmountMismatch   = "amount_mismatch"
+	DiscrepancyStatusMismatch   = "status_mismatch"
+)
+
+// Run satu kali import laporan settlement
+type Run struct {
+	ID               int
+	Provider         string
+	FileName         string
+	RecordCount      int
+	MatchedCount     int
+	DiscrepancyCount int
+	CreatedAt        time.Time
+}
+
+func (Run) TableName() string {
+	return "reconciliation_runs"
+}
+
+// Discrepancy selisih yang ditemukan saat reconciliation, tetap terbuka sampai di-resolve admin
+// atau order tersebut cocok pada run berikutnya
+type Discrepancy struct {
+	ID            int
+	RunID         int
+	Provider      string
+	OrderID       string
+	TransactionID int
+	Type          string
+	// ExpectedAmount dan ExpectedStatus menurut transaksi kita, ReportedAmount dan ReportedStatus menurut provider
+	ExpectedAmount   int
+	ReportedAmount   int
+	ExpectedStatus   string
+	ReportedStatus   string
+	Detail           string
+	Note             string
+	ResolvedByUserID int
+	ResolvedAt       *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+func (Discrepancy) TableName() string {
+	return "reconciliation_discrepancies"
+}
+
+func (d Discrepancy) IsResolved() bool {
+	return d.ResolvedAt != nil
+}
+
+// SettlementRecord satu baris laporan settlement, Status sudah dinormalisasi ke status transaksi
+type SettlementRecord struct {
+	OrderID   string
+	Amount    int
+	Status    string
+	RawStatus string
+	Reference string
+}
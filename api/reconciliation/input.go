@@ -0,0 +1,17 @@
+package reconciliation
+
+import "time"
+
+// ReconcileInput laporan settlement yang akan dicocokkan. Jika CreatedFrom dan CreatedTo diisi,
+// transaksi paid pada rentang tersebut yang tidak ada di laporan ditandai missing_settlement
+type ReconcileInput struct {
+	Provider    string
+	FileName    string
+	Records     []SettlementRecord
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+}
+
+type ResolveDiscrepancyInput struct {
+	Note string `form:"note" binding:"required"`
+}
@@ -0,0 +1,104 @@
+package reconciliation
+
+import (
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	SaveRun(run Run) (Run, error)
+	UpdateRun(run Run) (Run, error)
+	FindRuns(limit int) ([]Run, error)
+	SaveDiscrepancy(discrepancy Discrepancy) (Discrepancy, error)
+	FindDiscrepancyByID(ID int) (Discrepancy, error)
+	FindDiscrepancies(includeResolved bool) ([]Discrepancy, error)
+	FindOpenDiscrepancies(provider string, orderID string) ([]Discrepancy, error)
+	Resolve(discrepancy Discrepancy) (bool, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *repository {
+	return &repository{db}
+}
+
+func (r *repository) SaveRun(run Run) (Run, error) {
+	err := r.db.Create(&run).Error
+	if err != nil {
+		return run, err
+	}
+	return run, nil
+}
+
+func (r *repository) UpdateRun(run Run) (Run, error) {
+	err := r.db.Save(&run).Error
+	if err != nil {
+		return run, err
+	}
+	return run, nil
+}
+
+func (r *repository) FindRuns(limit int) ([]Run, error) {
+	var runs []Run
+
+	err := r.db.Order("id desc").Limit(limit).Find(&runs).Error
+	if err != nil {
+		return runs, err
+	}
+	return runs, nil
+}
+
+func (r *repository) SaveDiscrepancy(discrepancy Discrepancy) (Discrepancy, error) {
+	err := r.db.Create(&discrepancy).Error
+	if err != nil {
+		return discrepancy, err
+	}
+	return discrepancy, nil
+}
+
+func (r *repository) FindDiscrepancyByID(ID int) (Discrepancy, error) {
+	var discrepancy Discrepancy
+
+	err := r.db.Where("id = ?", ID).Find(&discrepancy).Error
+	if err != nil {
+		return discrepancy, err
+	}
+	return discrepancy, nil
+}
+
+func (r *repository) FindDiscrepancies(includeResolved bool) ([]Discrepancy, error) {
+	var discrepancies []Discrepancy
+
+	query := r.db.Order("id desc")
+	if !includeResolved {
+		query = query.Where("resolved_at IS NULL")
+	}
+
+	err := query.Find(&discrepancies).Error
+	if err != nil {
+		return discrepancies, err
+	}
+	return discrepancies, nil
+}
+
+func (r *repository) FindOpenDiscrepancies(provider string, orderID string) ([]Discrepancy, error) {
+	var discrepancies []Discrepancy
+
+	err := r.db.Where("provider = ? AND order_id = ? AND resolved_at IS NULL", provider, orderID).Find(&discrepancies).Error
+	if err != nil {
+		return discrepancies, err
+	}
+	return discrepancies, nil
+}
+
+// Resolve menutup discrepancy jika belum pernah di-resolve
+func (r *repository) Resolve(discrepancy Discrepancy) (bool, error) {
+	result := r.db.Model(&Discrepancy{}).Where("id = ? AND resolved_at IS NULL", discrepancy.ID).
+		Select("note", "resolved_by_user_id", "resolved_at").
+		Updates(&discrepancy)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,280 @@
+package reconciliation
+
+import (
+	"bwastartup/api/transaction"
+	"errors"
+	"strconv"
+	"time"
+)
+
+type Service interface {
+	Reconcile(input ReconcileInput) (Run, []Discrepancy, error)
+	GetRuns() ([]Run, error)
+	GetDiscrepancies(includeResolved bool) ([]Discrepancy, error)
+	ResolveDiscrepancy(ID int, adminUserID int, input ResolveDiscrepancyInput) error
+}
+
+type service struct {
+	repository            Repository
+	transactionRepository transaction.Repository
+}
+
+func NewService(repository Repository, transactionRepository transaction.Repository) *service {
+	return &service{repository, transactionRepository}
+}
+
+// settlement gabungan baris laporan untuk satu order, pembayaran dan refund bisa muncul di baris terpisah
+type settlement struct {
+	OrderID        string
+	Amount         int
+	RefundedAmount int
+	Status         string
+}
+
+func groupRecords(records []SettlementRecord) []settlement {
+	settlements := []settlement{}
+	indexes := map[string]int{}
+
+	for _, record := range records {
+		index, ok := indexes[record.OrderID]
+		if !ok {
+			index = len(settlements)
+			indexes[record.OrderID] = index
+			settlements = append(settlements, settlement{OrderID: record.OrderID})
+		}
+
+		current := &settlements[index]
+		if record.Status == transaction.StatusRefunded || record.Status == transaction.StatusPartiallyRefunded {
+			current.RefundedAmount += record.Amount
+			if current.Amount == 0 {
+				current.Status = record.Status
+			}
+			continue
+		}
+		current.Amount = record.Amount
+		current.Status = record.Status
+	}
+
+	for index := range settlements {
+		current := &settlements[index]
+		if current.RefundedAmount > 0 && current.Amount > 0 {
+			current.Status = transaction.StatusPartiallyRefunded
+			if current.RefundedAmount >= current.Amount {
+				current.Status = transaction.StatusRefunded
+			}
+		}
+	}
+	return settlements
+}
+
+// Reconcile mencocokkan laporan settlement dengan transaksi berdasarkan kode order lalu mencatat selisihnya.
+// Selisih yang masih terbuka dari run sebelumnya tidak dicatat ulang, dan ditutup otomatis jika order sudah cocok
+func (s *service) Reconcile(input ReconcileInput) (Run, []Discrepancy, error) {
+	discrepancies := []Discrepancy{}
+
+	if input.Provider == "" {
+		return Run{}, discrepancies, errors.New("Provider is required")
+	}
+
+	run := Run{}
+	run.Provider = input.Provider
+	run.FileName = input.FileName
+	run.RecordCount = len(input.Records)
+
+	run, err := s.repository.SaveRun(run)
+	if err != nil {
+		return run, discrepancies, err
+	}
+
+	reportedOrderIDs := map[string]bool{}
+	for _, reported := range groupRecords(input.Records) {
+		reportedOrderIDs[reported.OrderID] = true
+
+		existingTransaction, err := s.transactionRepository.GetByCode(reported.OrderID)
+		if err != nil {
+			return run, discrepancies, err
+		}
+
+		found := compare(run.Provider, reported, existingTransaction)
+		if len(found) == 0 {
+			run.MatchedCount++
+		}
+
+		saved, err := s.record(run, reported.OrderID, found)
+		if err != nil {
+			return run, discrepancies, err
+		}
+		discrepancies = append(discrepancies, saved...)
+	}
+
+	if input.CreatedFrom != nil && input.CreatedTo != nil {
+		settledTransactions, err := s.transactionRepository.FindSettledByProvider(input.Provider, *input.CreatedFrom, *input.CreatedTo)
+		if err != nil {
+			return run, discrepancies, err
+		}
+
+		for _, settledTransaction := range settledTransactions {
+			if reportedOrderIDs[settledTransaction.Code] {
+				continue
+			}
+
+			discrepancy := Discrepancy{}
+			discrepancy.TransactionID = settledTransaction.ID
+			discrepancy.Type = DiscrepancyMissingSettlement
+			discrepancy.ExpectedAmount = settledTransaction.Amount
+			discrepancy.ExpectedStatus = settledTransaction.Status
+			discrepancy.Detail = "Transaction is not in the settlement report"
+
+			saved, err := s.record(run, settledTransaction.Code, []Discrepancy{discrepancy})
+			if err != nil {
+				return run, discrepancies, err
+			}
+			discrepancies = append(discrepancies, saved...)
+		}
+	}
+
+	run.DiscrepancyCount = len(discrepancies)
+	run, err = s.repository.UpdateRun(run)
+	if err != nil {
+		return run, discrepancies, err
+	}
+	return run, discrepancies, nil
+}
+
+// compare membandingkan satu order di laporan provider dengan transaksi kita (ID 0 berarti transaksi tidak ditemukan).
+// Transaksi milik provider lain tidak dianggap cocok walaupun amount dan statusnya sama
+func compare(provider string, reported settlement, existingTransaction transaction.Transaction) []Discrepancy {
+	found := []Discrepancy{}
+
+	discrepancy := Discrepancy{}
+	discrepancy.TransactionID = existingTransaction.ID
+	discrepancy.ReportedAmount = reported.Amount
+	discrepancy.ReportedStatus = reported.Status
+	discrepancy.ExpectedAmount = existingTransaction.Amount
+	discrepancy.ExpectedStatus = existingTransaction.Status
+
+	if existingTransaction.ID == 0 {
+		discrepancy.Type = DiscrepancyMissingTransaction
+		discrepancy.Detail = "No transaction with this order code"
+		return append(found, discrepancy)
+	}
+
+	if existingTransaction.PaymentProvider != provider {
+		discrepancy.Type = DiscrepancyProviderMismatch
+		discrepancy.Detail = "Transaction was paid with " + existingTransaction.PaymentProvider
+		return append(found, discrepancy)
+	}
+
+	if reported.Amount > 0 && reported.Amount != existingTransaction.Amount {
+		amountMismatch := discrepancy
+		amountMismatch.Type = DiscrepancyAmountMismatch
+		amountMismatch.Detail = "Paid amount differs"
+		found = append(found, amountMismatch)
+	}
+
+	if reported.RefundedAmount > 0 && reported.RefundedAmount != existingTransaction.RefundedAmount {
+		refundMismatch := discrepancy
+		refundMismatch.Type = DiscrepancyAmountMismatch
+		refundMismatch.ExpectedAmount = existingTransaction.RefundedAmount
+		refundMismatch.ReportedAmount = reported.RefundedAmount
+		refundMismatch.Detail = "Refunded amount differs"
+		found = append(found, refundMismatch)
+	}
+
+	if reported.Status != existingTransaction.Status {
+		statusMismatch := discrepancy
+		statusMismatch.Type = DiscrepancyStatusMismatch
+		statusMismatch.Detail = "Status differs"
+		found = append(found, statusMismatch)
+	}
+	return found
+}
+
+// record menyimpan selisih baru untuk satu order dan menutup selisih lama yang sudah tidak ditemukan lagi
+func (s *service) record(run Run, orderID string, found []Discrepancy) ([]Discrepancy, error) {
+	saved := []Discrepancy{}
+
+	openDiscrepancies, err := s.repository.FindOpenDiscrepancies(run.Provider, orderID)
+	if err != nil {
+		return saved, err
+	}
+
+	isStillOpen := map[int]bool{}
+	for _, discrepancy := range found {
+		isDuplicate := false
+		for _, openDiscrepancy := range openDiscrepancies {
+			if openDiscrepancy.Type == discrepancy.Type && openDiscrepancy.Detail == discrepancy.Detail {
+				isStillOpen[openDiscrepancy.ID] = true
+				isDuplicate = true
+			}
+		}
+
+		if isDuplicate {
+			saved = append(saved, discrepancy)
+			continue
+		}
+
+		discrepancy.RunID = run.ID
+		discrepancy.Provider = run.Provider
+		discrepancy.OrderID = orderID
+
+		discrepancy, err = s.repository.SaveDiscrepancy(discrepancy)
+		if err != nil {
+			return saved, err
+		}
+		saved = append(saved, discrepancy)
+	}
+
+	now := time.Now()
+	for _, openDiscrepancy := range openDiscrepancies {
+		if isStillOpen[openDiscrepancy.ID] {
+			continue
+		}
+
+		openDiscrepancy.Note = "Matched in reconciliation run #" + strconv.Itoa(run.ID)
+		openDiscrepancy.ResolvedAt = &now
+
+		_, err = s.repository.Resolve(openDiscrepancy)
+		if err != nil {
+			return saved, err
+		}
+	}
+	return saved, nil
+}
+
+func (s *service) GetRuns() ([]Run, error) {
+	return s.repository.FindRuns(20)
+}
+
+func (s *service) GetDiscrepancies(includeResolved bool) ([]Discrepancy, error) {
+	return s.repository.FindDiscrepancies(includeResolved)
+}
+
+func (s *service) ResolveDiscrepancy(ID int, adminUserID int, input ResolveDiscrepancyInput) error {
+	if adminUserID <= 0 {
+		return errors.New("Admin user is required to resolve a discrepancy")
+	}
+
+	discrepancy, err := s.repository.FindDiscrepancyByID(ID)
+	if err != nil {
+		return err
+	}
+
+	if discrepancy.ID == 0 {
+		return errors.New("Discrepancy not found")
+	}
+
+	now := time.Now()
+	discrepancy.Note = input.Note
+	discrepancy.ResolvedByUserID = adminUserID
+	discrepancy.ResolvedAt = &now
+
+	isResolved, err := s.repository.Resolve(discrepancy)
+	if err != nil {
+		return err
+	}
+	if !isResolved {
+		return errors.New("Discrepancy has already been resolved")
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package reconciliation
+
+import (
+	"bwastartup/api/transaction"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	paidTransaction := transaction.Transaction{ID: 7, Code: "BWA-7", Amount: 50000, Status: transaction.StatusPaid, PaymentProvider: "midtrans"}
+	reported := settlement{OrderID: "BWA-7", Amount: 50000, Status: transaction.StatusPaid}
+
+	found := compare("midtrans", reported, paidTransaction)
+	if len(found) != 0 {
+		t.Errorf("compare with the same provider = %v, want no discrepancy", found)
+	}
+
+	// kode order Midtrans di laporan Xendit tidak boleh dianggap cocok
+	found = compare("xendit", reported, paidTransaction)
+	if len(found) != 1 || found[0].Type != DiscrepancyProviderMismatch || found[0].TransactionID != paidTransaction.ID {
+		t.Errorf("compare with another provider = %v, want one %s discrepancy", found, DiscrepancyProviderMismatch)
+	}
+
+	found = compare("midtrans", reported, transaction.Transaction{})
+	if len(found) != 1 || found[0].Type != DiscrepancyMissingTransaction {
+		t.Errorf("compare without transaction = %v, want one %s discrepancy", found, DiscrepancyMissingTransaction)
+	}
+}
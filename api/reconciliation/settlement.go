@@ -0,0 +1,166 @@
+package reconciliation
+
+import (
+	"bwastartup/api/transaction"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// nama kolom yang dikenali, laporan Midtrans, Xendit dan Stripe memakai nama yang berbeda
+var columnAliases = map[string][]string{
+	"order_id":  {"order_id", "order id", "external_id", "external id", "code", "reference_id"},
+	"amount":    {"amount", "gross_amount", "gross amount", "paid_amount"},
+	"status":    {"status", "transaction_status", "transaction status"},
+	"reference": {"reference", "transaction_id", "transaction id", "id", "payment_intent"},
+}
+
+// statusAliases status laporan provider yang dipetakan ke status transaksi
+var statusAliases = map[string]string{
+	"settlement":         transaction.StatusPaid,
+	"capture":            transaction.StatusPaid,
+	"paid":               transaction.StatusPaid,
+	"settled":            transaction.StatusPaid,
+	"succeeded":          transaction.StatusPaid,
+	"completed":          transaction.StatusPaid,
+	"refund":             transaction.StatusRefunded,
+	"refunded":           transaction.StatusRefunded,
+	"partial_refund":     transaction.StatusPartiallyRefunded,
+	"partially_refunded": transaction.StatusPartiallyRefunded,
+	"expire":             transaction.StatusExpired,
+	"expired":            transaction.StatusExpired,
+	"cancel":             transaction.StatusCancelled,
+	"cancelled":          transaction.StatusCancelled,
+	"canceled":           transaction.StatusCancelled,
+	"deny":               transaction.StatusCancelled,
+	"failed":             transaction.StatusCancelled,
+	"pending":            transaction.StatusPending,
+}
+
+// ParseSettlementFile membaca laporan settlement dalam format csv (baris pertama header) atau json (array object)
+func ParseSettlementFile(reader io.Reader, format string) ([]SettlementRecord, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(reader)
+	case FormatJSON:
+		return parseJSON(reader)
+	}
+	return nil, errors.New("Unsupported settlement format: " + format)
+}
+
+func parseCSV(reader io.Reader) ([]SettlementRecord, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for index, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		for column, aliases := range columnAliases {
+			if _, ok := columns[column]; ok {
+				continue
+			}
+			for _, alias := range aliases {
+				if name == alias {
+					columns[column] = index
+				}
+			}
+		}
+	}
+
+	for _, column := range []string{"order_id", "amount", "status"} {
+		if _, ok := columns[column]; !ok {
+			return nil, errors.New("Settlement file has no " + column + " column")
+		}
+	}
+
+	records := []SettlementRecord{}
+	line := 1
+	for {
+		row, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, err
+		}
+
+		value := func(column string) string {
+			index, ok := columns[column]
+			if !ok || index >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[index])
+		}
+
+		record, err := newSettlementRecord(value("order_id"), value("amount"), value("status"), value("reference"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err.Error())
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func parseJSON(reader io.Reader) ([]SettlementRecord, error) {
+	var rows []struct {
+		OrderID   string      `json:"order_id"`
+		Amount    json.Number `json:"amount"`
+		Status    string      `json:"status"`
+		Reference string      `json:"reference"`
+	}
+
+	err := json.NewDecoder(reader).Decode(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []SettlementRecord{}
+	for index, row := range rows {
+		record, err := newSettlementRecord(row.OrderID, row.Amount.String(), row.Status, row.Reference)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %s", index+1, err.Error())
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func newSettlementRecord(orderID string, amount string, status string, reference string) (SettlementRecord, error) {
+	record := SettlementRecord{}
+
+	if orderID == "" {
+		return record, errors.New("order_id is empty")
+	}
+
+	parsedAmount, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return record, errors.New("invalid amount " + strconv.Quote(amount))
+	}
+
+	record.OrderID = orderID
+	record.Amount = int(math.Round(parsedAmount))
+	record.RawStatus = status
+	record.Status = statusAliases[strings.ToLower(status)]
+	record.Reference = reference
+
+	if record.Status == "" {
+		return record, errors.New("unknown status " + strconv.Quote(status))
+	}
+	return record, nil
+}
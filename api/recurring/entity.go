@@ -0,0 +1,89 @@
+package recurring
+
+import (
+	"bwastartup/api/campaign"
+	"bwastartup/api/money"
+	"bwastartup/api/user"
+	"time"
+)
+
+// status recurring pledge
+const (
+	// StatusPending menunggu pembayaran pertama di halaman payment provider
+	StatusPending = "pending"
+	StatusActive  = "active"
+	// StatusPastDue tagihan terakhir gagal, dicoba lagi sesuai jadwal dunning
+	StatusPastDue = "past_due"
+	// StatusPaused dihentikan sementara oleh backer, tidak ditagih sampai dilanjutkan
+	StatusPaused    = "paused"
+	StatusCancelled = "cancelled"
+)
+
+// Pledge dukungan bulanan backer. Setiap periode ditagih sebagai transaksi biasa (transaction.Transaction)
+// dengan RecurringPledgeID dan BillingPeriod, sehingga total campaign, ledger dan fee tetap lewat jalur yang sama
+type Pledge struct {
+	ID         int
+	CampaignID int
+	UserID     int
+	// Amount dalam minor unit Currency, ditagih setiap bulan
+	Amount   int
+	Currency string
+	// CampaignAmount Amount dalam mata uang campaign dari tagihan terakhir, dipakai untuk MRR
+	CampaignAmount  int
+	PaymentProvider string
+	// PaymentToken metode pembayaran tersimpan dari pembayaran pertama
+	PaymentToken string
+	Status       string
+	Visibility   string
+	DisplayName  string
+	Message      string
+	// BillingDay tanggal penagihan setiap bulan, di bulan yang lebih pendek ditagih di akhir bulan
+	BillingDay int
+	// CurrentPeriod jumlah periode yang sudah lunas
+	CurrentPeriod int
+	// DueAt jadwal tagihan periode berikutnya, NextChargeAt waktu percobaan tagihan berikutnya (bisa bergeser karena dunning)
+	DueAt        *time.Time
+	NextChargeAt *time.Time
+	// FailedAttempts tagihan gagal berturut-turut untuk periode berikutnya
+	FailedAttempts    int
+	LastTransactionID int
+	// Note alasan kegagalan atau pembatalan terakhir
+	Note        string
+	CancelledAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Campaign    campaign.Campaign
+	User        user.User
+}
+
+func (Pledge) TableName() string {
+	return "recurring_pledges"
+}
+
+// AmountFormatted amount bulanan dalam mata uang backer
+func (p Pledge) AmountFormatted() string {
+	return money.New(p.Amount, p.Currency).Format()
+}
+
+// Revenue ringkasan recurring pledge per campaign untuk dashboard creator, amount dalam mata uang campaign
+type Revenue struct {
+	CampaignID   int
+	CampaignName string
+	Currency     string
+	ActiveCount  int
+	PastDueCount int
+	PausedCount  int
+	// MonthlyRecurringRevenue total pledge aktif, AtRiskRevenue total pledge yang sedang dalam dunning
+	MonthlyRecurringRevenue int
+	AtRiskRevenue           int
+}
+
+// nextBillingDate tanggal billingDay pada bulan setelah from, dipotong ke hari terakhir jika bulan tersebut lebih pendek
+func nextBillingDate(from time.Time, billingDay int) time.Time {
+	lastDay := time.Date(from.Year(), from.Month()+2, 0, 0, 0, 0, 0, from.Location()).Day()
+	day := billingDay
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(from.Year(), from.Month()+1, day, from.Hour(), from.Minute(), from.Second(), 0, from.Location())
+}
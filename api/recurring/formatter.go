@@ -0,0 +1,143 @@
+package recurring
+
+import (
+	"bwastartup/api/money"
+	"time"
+)
+
+type PledgeFormatter struct {
+	ID              int        `json:"id"`
+	CampaignID      int        `json:"campaign_id"`
+	CampaignName    string     `json:"campaign_name"`
+	Amount          int        `json:"amount"`
+	Currency        string     `json:"currency"`
+	PaymentProvider string     `json:"payment_provider"`
+	Status          string     `json:"status"`
+	Visibility      string     `json:"visibility"`
+	DisplayName     string     `json:"display_name"`
+	BillingDay      int        `json:"billing_day"`
+	CurrentPeriod   int        `json:"current_period"`
+	NextChargeAt    *time.Time `json:"next_charge_at"`
+	FailedAttempts  int        `json:"failed_attempts"`
+	Note            string     `json:"note"`
+	CreatedAt       time.Time  `json:"created_at"`
+	CancelledAt     *time.Time `json:"cancelled_at"`
+}
+
+// FormatPledge token metode pembayaran tidak pernah dikirim ke client
+func FormatPledge(pledge Pledge) PledgeFormatter {
+	formatter := PledgeFormatter{}
+	formatter.ID = pledge.ID
+	formatter.CampaignID = pledge.CampaignID
+	formatter.CampaignName = pledge.Campaign.Name
+	formatter.Amount = pledge.Amount
+	formatter.Currency = money.NormalizeCurrency(pledge.Currency)
+	formatter.PaymentProvider = pledge.PaymentProvider
+	formatter.Status = pledge.Status
+	formatter.Visibility = pledge.Visibility
+	formatter.DisplayName = pledge.DisplayName
+	formatter.BillingDay = pledge.BillingDay
+	formatter.CurrentPeriod = pledge.CurrentPeriod
+	formatter.NextChargeAt = pledge.NextChargeAt
+	formatter.FailedAttempts = pledge.FailedAttempts
+	formatter.Note = pledge.Note
+	formatter.CreatedAt = pledge.CreatedAt
+	formatter.CancelledAt = pledge.CancelledAt
+	return formatter
+}
+
+func FormatPledges(pledges []Pledge) []PledgeFormatter {
+	pledgesFormatter := []PledgeFormatter{}
+
+	for _, pledge := range pledges {
+		pledgesFormatter = append(pledgesFormatter, FormatPledge(pledge))
+	}
+	return pledgesFormatter
+}
+
+// CreatedPledgeFormatter pledge baru beserta halaman pembayaran tagihan pertama
+type CreatedPledgeFormatter struct {
+	PledgeFormatter
+	TransactionID int    `json:"transaction_id"`
+	PaymentURL    string `json:"payment_url"`
+}
+
+func FormatCreatedPledge(pledge Pledge, transactionID int, paymentURL string) CreatedPledgeFormatter {
+	formatter := CreatedPledgeFormatter{}
+	formatter.PledgeFormatter = FormatPledge(pledge)
+	formatter.TransactionID = transactionID
+	formatter.PaymentURL = paymentURL
+	return formatter
+}
+
+type RevenueFormatter struct {
+	CampaignID              int    `json:"campaign_id"`
+	CampaignName            string `json:"campaign_name"`
+	Currency                string `json:"currency"`
+	ActiveCount             int    `json:"active_count"`
+	PastDueCount            int    `json:"past_due_count"`
+	PausedCount             int    `json:"paused_count"`
+	MonthlyRecurringRevenue int    `json:"monthly_recurring_revenue"`
+	AtRiskRevenue           int    `json:"at_risk_revenue"`
+}
+
+type RevenueSummaryFormatter struct {
+	Campaigns []RevenueFormatter `json:"campaigns"`
+	// Totals total per mata uang, amount dari mata uang berbeda tidak dijumlahkan
+	Totals []RevenueFormatter `json:"totals"`
+}
+
+func FormatRevenue(revenue Revenue) RevenueFormatter {
+	formatter := RevenueFormatter{}
+	formatter.CampaignID = revenue.CampaignID
+	formatter.CampaignName = revenue.CampaignName
+	formatter.Currency = money.NormalizeCurrency(revenue.Currency)
+	formatter.ActiveCount = revenue.ActiveCount
+	formatter.PastDueCount = revenue.PastDueCount
+	formatter.PausedCount = revenue.PausedCount
+	formatter.MonthlyRecurringRevenue = revenue.MonthlyRecurringRevenue
+	formatter.AtRiskRevenue = revenue.AtRiskRevenue
+	return formatter
+}
+
+// FormatRevenues MRR per campaign beserta total per mata uang
+func FormatRevenues(revenues []Revenue) RevenueSummaryFormatter {
+	summary := RevenueSummaryFormatter{}
+	summary.Campaigns = []RevenueFormatter{}
+	summary.Totals = []RevenueFormatter{}
+
+	for _, total := range revenueTotals(revenues) {
+		summary.Totals = append(summary.Totals, FormatRevenue(total))
+	}
+	for _, revenue := range revenues {
+		summary.Campaigns = append(summary.Campaigns, FormatRevenue(revenue))
+	}
+	return summary
+}
+
+// revenueTotals menjumlahkan revenue per mata uang, urutan mengikuti kemunculan pertama
+func revenueTotals(revenues []Revenue) []Revenue {
+	totals := map[string]*Revenue{}
+	currencies := []string{}
+	for _, revenue := range revenues {
+		currency := money.NormalizeCurrency(revenue.Currency)
+		total, ok := totals[currency]
+		if !ok {
+			total = &Revenue{Currency: currency}
+			totals[currency] = total
+			currencies = append(currencies, currency)
+		}
+
+		total.ActiveCount += revenue.ActiveCount
+		total.PastDueCount += revenue.PastDueCount
+		total.PausedCount += revenue.PausedCount
+		total.MonthlyRecurringRevenue += revenue.MonthlyRecurringRevenue
+		total.AtRiskRevenue += revenue.AtRiskRevenue
+	}
+
+	result := []Revenue{}
+	for _, currency := range currencies {
+		result = append(result, *totals[currency])
+	}
+	return result
+}
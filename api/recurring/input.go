@@ -0,0 +1,22 @@
+package recurring
+
+import "bwastartup/api/user"
+
+type CreatePledgeInput struct {
+	CampaignID int `json:"campaign_id" binding:"required"`
+	// Amount ditagih setiap bulan dalam minor unit Currency
+	Amount int `json:"amount" binding:"required"`
+	// Currency mata uang yang dibayar backer, kosong berarti mata uang campaign
+	Currency string `json:"currency"`
+	// PaymentProvider harus mendukung tagihan recurring, kosong berarti provider default
+	PaymentProvider string `json:"payment_provider"`
+	Visibility      string `json:"visibility"`
+	DisplayName     string `json:"display_name"`
+	Message         string `json:"message"`
+	User            user.User
+}
+
+type GetPledgeInput struct {
+	ID   int `uri:"id" binding:"required"`
+	User user.User
+}
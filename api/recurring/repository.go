@@ -0,0 +1,129 @@
+package recurring
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Save(pledge Pledge) (Pledge, error)
+	Delete(pledge Pledge) error
+	FindByID(ID int) (Pledge, error)
+	FindByUserID(userID int) ([]Pledge, error)
+	FindPending(afterID int, limit int) ([]Pledge, error)
+	FindDue(now time.Time, limit int) ([]Pledge, error)
+	UpdateState(pledge Pledge, fromStatus string, fromNextChargeAt *time.Time) (bool, error)
+	GetRevenue(userID int) ([]Revenue, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *repository {
+	return &repository{db}
+}
+
+func (r *repository) Save(pledge Pledge) (Pledge, error) {
+	err := r.db.Omit("Campaign", "User").Create(&pledge).Error
+	if err != nil {
+		return pledge, err
+	}
+	return pledge, nil
+}
+
+func (r *repository) Delete(pledge Pledge) error {
+	err := r.db.Delete(&Pledge{}, pledge.ID).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *repository) FindByID(ID int) (Pledge, error) {
+	var pledge Pledge
+
+	err := r.db.Preload("Campaign").Preload("User").Where("id = ?", ID).Find(&pledge).Error
+	if err != nil {
+		return pledge, err
+	}
+	return pledge, nil
+}
+
+func (r *repository) FindByUserID(userID int) ([]Pledge, error) {
+	var pledges []Pledge
+
+	err := r.db.Preload("Campaign").Where("user_id = ?", userID).Order("id desc").Find(&pledges).Error
+	if err != nil {
+		return pledges, err
+	}
+	return pledges, nil
+}
+
+// FindPending pledge yang menunggu pembayaran pertama, dibaca bertahap setelah afterID
+func (r *repository) FindPending(afterID int, limit int) ([]Pledge, error) {
+	var pledges []Pledge
+
+	err := r.db.Where("status = ? AND id > ?", StatusPending, afterID).Order("id asc").Limit(limit).Find(&pledges).Error
+	if err != nil {
+		return pledges, err
+	}
+	return pledges, nil
+}
+
+// FindDue pledge aktif atau past due yang sudah waktunya ditagih
+func (r *repository) FindDue(now time.Time, limit int) ([]Pledge, error) {
+	var pledges []Pledge
+
+	err := r.db.Preload("User").Where("status IN ? AND next_charge_at <= ?", []string{StatusActive, StatusPastDue}, now).
+		Order("next_charge_at asc").Limit(limit).Find(&pledges).Error
+	if err != nil {
+		return pledges, err
+	}
+	return pledges, nil
+}
+
+// UpdateState compare-and-set dari status (dan next_charge_at jika tidak nil), false berarti pledge sudah diubah proses lain
+func (r *repository) UpdateState(pledge Pledge, fromStatus string, fromNextChargeAt *time.Time) (bool, error) {
+	query := r.db.Model(&Pledge{}).Where("id = ? AND status = ?", pledge.ID, fromStatus)
+	if fromNextChargeAt != nil {
+		query = query.Where("next_charge_at = ?", *fromNextChargeAt)
+	}
+
+	result := query.Select("status", "currency", "campaign_amount", "payment_provider", "payment_token", "visibility", "display_name",
+		"billing_day", "current_period", "due_at", "next_charge_at", "failed_attempts", "last_transaction_id", "note", "cancelled_at").
+		Updates(&pledge)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// GetRevenue ringkasan pledge yang belum dibatalkan per campaign, userID 0 berarti semua campaign
+func (r *repository) GetRevenue(userID int) ([]Revenue, error) {
+	var revenues []Revenue
+
+	query := r.db.Table("recurring_pledges").
+		Select("recurring_pledges.campaign_id, campaigns.name AS campaign_name, campaigns.currency, "+
+			"SUM(CASE WHEN recurring_pledges.status = ? THEN 1 ELSE 0 END) AS active_count, "+
+			"SUM(CASE WHEN recurring_pledges.status = ? THEN 1 ELSE 0 END) AS past_due_count, "+
+			"SUM(CASE WHEN recurring_pledges.status = ? THEN 1 ELSE 0 END) AS paused_count, "+
+			"SUM(CASE WHEN recurring_pledges.status = ? THEN recurring_pledges.campaign_amount ELSE 0 END) AS monthly_recurring_revenue, "+
+			"SUM(CASE WHEN recurring_pledges.status = ? THEN recurring_pledges.campaign_amount ELSE 0 END) AS at_risk_revenue",
+			StatusActive, StatusPastDue, StatusPaused, StatusActive, StatusPastDue).
+		Joins("JOIN campaigns ON campaigns.id = recurring_pledges.campaign_id").
+		Where("recurring_pledges.status IN ?", []string{StatusActive, StatusPastDue, StatusPaused}).
+		Group("recurring_pledges.campaign_id, campaigns.name, campaigns.currency").
+		Order("recurring_pledges.campaign_id asc")
+
+	if userID != 0 {
+		query = query.Where("campaigns.user_id = ?", userID)
+	}
+
+	err := query.Scan(&revenues).Error
+	if err != nil {
+		return revenues, err
+	}
+	return revenues, nil
+}
@@ -0,0 +1,117 @@
+package recurring
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultSchedulerInterval = 15 * time.Minute
+	DefaultSchedulerBatch    = 100
+)
+
+// DefaultRetryDays jarak (hari) antar percobaan ulang tagihan yang gagal sebelum pledge dibatalkan
+var DefaultRetryDays = []int{1, 3, 5}
+
+// DunningConfig jadwal percobaan ulang tagihan yang gagal, RetryDelays[n] dipakai setelah kegagalan ke-n+1
+type DunningConfig struct {
+	RetryDelays []time.Duration
+}
+
+// NewDunningConfigFromEnv membaca RECURRING_RETRY_DAYS, daftar hari dipisah koma (contoh "1,3,5")
+func NewDunningConfigFromEnv() DunningConfig {
+	days := DefaultRetryDays
+
+	value := strings.TrimSpace(os.Getenv("RECURRING_RETRY_DAYS"))
+	if value != "" {
+		parsed := []int{}
+		for _, part := range strings.Split(value, ",") {
+			day, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || day <= 0 {
+				parsed = nil
+				break
+			}
+			parsed = append(parsed, day)
+		}
+		if parsed != nil {
+			days = parsed
+		}
+	}
+
+	config := DunningConfig{}
+	for _, day := range days {
+		config.RetryDelays = append(config.RetryDelays, time.Duration(day)*24*time.Hour)
+	}
+	return config
+}
+
+// SchedulerConfig konfigurasi penagihan recurring pledge berkala
+type SchedulerConfig struct {
+	// Interval jarak antar putaran, 0 berarti scheduler tidak dijalankan
+	Interval  time.Duration
+	BatchSize int
+}
+
+// NewSchedulerConfigFromEnv membaca RECURRING_SCHEDULER_INTERVAL (dalam detik) dan RECURRING_BATCH_SIZE
+func NewSchedulerConfigFromEnv() SchedulerConfig {
+	config := SchedulerConfig{DefaultSchedulerInterval, DefaultSchedulerBatch}
+
+	interval, err := strconv.Atoi(os.Getenv("RECURRING_SCHEDULER_INTERVAL"))
+	if err == nil && interval >= 0 {
+		config.Interval = time.Duration(interval) * time.Second
+	}
+
+	batchSize, err := strconv.Atoi(os.Getenv("RECURRING_BATCH_SIZE"))
+	if err == nil && batchSize > 0 {
+		config.BatchSize = batchSize
+	}
+	return config
+}
+
+// Scheduler mengaktifkan pledge yang tagihan pertamanya sudah lunas dan menagih pledge yang jatuh tempo.
+// Aman dijalankan di beberapa instance sekaligus karena setiap pledge diklaim dengan compare-and-set
+type Scheduler struct {
+	service Service
+	config  SchedulerConfig
+}
+
+func NewScheduler(service Service, config SchedulerConfig) *Scheduler {
+	return &Scheduler{service, config}
+}
+
+// Start menjalankan scheduler di goroutine terpisah, putaran pertama langsung dijalankan
+func (s *Scheduler) Start() {
+	if s.config.Interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			s.Run()
+			<-ticker.C
+		}
+	}()
+}
+
+// Run satu kali putaran scheduler
+func (s *Scheduler) Run() {
+	activated, err := s.service.ActivatePendingPledges(s.config.BatchSize)
+	if err != nil {
+		log.Printf("recurring scheduler: activate pending pledges: %v", err)
+	}
+
+	charged, err := s.service.ChargeDuePledges(time.Now(), s.config.BatchSize)
+	if err != nil {
+		log.Printf("recurring scheduler: charge due pledges: %v", err)
+	}
+
+	if activated > 0 || charged > 0 {
+		log.Printf("recurring scheduler: activated %d pledges, charged %d pledges", activated, charged)
+	}
+}
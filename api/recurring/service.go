@@ -0,0 +1,440 @@
+package recurring
+
+import (
+	"bwastartup/api/notification"
+	"bwastartup/api/transaction"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// chargeLease lama pledge diklaim satu proses scheduler selama tagihan berjalan, setelahnya bisa diambil ulang
+const chargeLease = time.Hour
+
+type Service interface {
+	CreatePledge(input CreatePledgeInput) (Pledge, transaction.Transaction, error)
+	GetPledgesByUserID(userID int) ([]Pledge, error)
+	PausePledge(input GetPledgeInput) (Pledge, error)
+	ResumePledge(input GetPledgeInput) (Pledge, error)
+	CancelPledge(input GetPledgeInput) (Pledge, error)
+	ActivatePendingPledges(batchSize int) (int, error)
+	ChargeDuePledges(now time.Time, batchSize int) (int, error)
+	GetRevenue(userID int) ([]Revenue, error)
+}
+
+type service struct {
+	repository          Repository
+	transactionService  transaction.Service
+	notificationService notification.Service
+	dunning             DunningConfig
+}
+
+func NewService(repository Repository, transactionService transaction.Service, notificationService notification.Service, dunning DunningConfig) *service {
+	return &service{repository, transactionService, notificationService, dunning}
+}
+
+// CreatePledge menyimpan pledge pending lalu membuat tagihan pertama lewat halaman pembayaran provider.
+// Metode pembayaran disimpan provider dan pledge aktif setelah tagihan pertama lunas (ActivatePendingPledges)
+func (s *service) CreatePledge(input CreatePledgeInput) (Pledge, transaction.Transaction, error) {
+	pledge := Pledge{}
+	pledge.CampaignID = input.CampaignID
+	pledge.UserID = input.User.ID
+	pledge.Amount = input.Amount
+	pledge.Currency = input.Currency
+	pledge.PaymentProvider = input.PaymentProvider
+	pledge.Status = StatusPending
+	pledge.Message = input.Message
+
+	newPledge, err := s.repository.Save(pledge)
+	if err != nil {
+		return newPledge, transaction.Transaction{}, err
+	}
+
+	transactionInput := transaction.CreateTransactionInput{}
+	transactionInput.Amount = input.Amount
+	transactionInput.CampaignID = input.CampaignID
+	transactionInput.PaymentProvider = input.PaymentProvider
+	transactionInput.Currency = input.Currency
+	transactionInput.Visibility = input.Visibility
+	transactionInput.DisplayName = input.DisplayName
+	transactionInput.Message = input.Message
+	transactionInput.RecurringPledgeID = newPledge.ID
+	transactionInput.BillingPeriod = 1
+	transactionInput.User = input.User
+
+	firstTransaction, err := s.transactionService.CreateTransaction(transactionInput)
+	if err != nil {
+		if firstTransaction.ID == 0 {
+			// validasi dukungan gagal, belum ada transaksi yang menunjuk ke pledge
+			deleteErr := s.repository.Delete(newPledge)
+			if deleteErr != nil {
+				return newPledge, firstTransaction, deleteErr
+			}
+			return Pledge{}, firstTransaction, err
+		}
+
+		cancelled := newPledge
+		cancelled.Status = StatusCancelled
+		cancelled.Note = err.Error()
+		now := time.Now()
+		cancelled.CancelledAt = &now
+		_, updateErr := s.repository.UpdateState(cancelled, StatusPending, nil)
+		if updateErr != nil {
+			return newPledge, firstTransaction, updateErr
+		}
+		return cancelled, firstTransaction, err
+	}
+
+	// nilai yang sudah dinormalisasi transaksi pertama dipakai untuk semua tagihan berikutnya
+	updated := newPledge
+	updated.Currency = firstTransaction.Currency
+	updated.CampaignAmount = firstTransaction.CampaignAmount
+	updated.PaymentProvider = firstTransaction.PaymentProvider
+	updated.Visibility = firstTransaction.Visibility
+	updated.DisplayName = firstTransaction.DisplayName
+	updated.LastTransactionID = firstTransaction.ID
+
+	_, err = s.repository.UpdateState(updated, StatusPending, nil)
+	if err != nil {
+		return newPledge, firstTransaction, err
+	}
+
+	return updated, firstTransaction, nil
+}
+
+func (s *service) GetPledgesByUserID(userID int) ([]Pledge, error) {
+	pledges, err := s.repository.FindByUserID(userID)
+	if err != nil {
+		return pledges, err
+	}
+	return pledges, nil
+}
+
+func (s *service) findOwnedPledge(input GetPledgeInput) (Pledge, error) {
+	pledge, err := s.repository.FindByID(input.ID)
+	if err != nil {
+		return pledge, err
+	}
+
+	if pledge.ID == 0 {
+		return pledge, errors.New("No recurring pledge found with that ID")
+	}
+
+	if pledge.UserID != input.User.ID {
+		return pledge, errors.New("Not an owner of the recurring pledge")
+	}
+	return pledge, nil
+}
+
+// PausePledge menghentikan tagihan sementara, periode selama pause tidak ditagih
+func (s *service) PausePledge(input GetPledgeInput) (Pledge, error) {
+	pledge, err := s.findOwnedPledge(input)
+	if err != nil {
+		return pledge, err
+	}
+
+	if pledge.Status != StatusActive && pledge.Status != StatusPastDue {
+		return pledge, errors.New("Only active recurring pledges can be paused")
+	}
+
+	fromStatus := pledge.Status
+	pledge.Status = StatusPaused
+	pledge.NextChargeAt = nil
+
+	updated, err := s.repository.UpdateState(pledge, fromStatus, nil)
+	if err != nil {
+		return pledge, err
+	}
+	if !updated {
+		return pledge, errors.New("Recurring pledge was changed by another process, please try again")
+	}
+	return pledge, nil
+}
+
+// ResumePledge melanjutkan pledge yang di-pause, jadwal yang sudah lewat ditagih pada putaran scheduler berikutnya
+func (s *service) ResumePledge(input GetPledgeInput) (Pledge, error) {
+	pledge, err := s.findOwnedPledge(input)
+	if err != nil {
+		return pledge, err
+	}
+
+	if pledge.Status != StatusPaused {
+		return pledge, errors.New("Only paused recurring pledges can be resumed")
+	}
+
+	now := time.Now()
+	dueAt := now
+	if pledge.DueAt != nil && pledge.DueAt.After(now) {
+		dueAt = *pledge.DueAt
+	}
+
+	pledge.Status = StatusActive
+	pledge.DueAt = &dueAt
+	pledge.NextChargeAt = &dueAt
+	pledge.FailedAttempts = 0
+	pledge.Note = ""
+
+	updated, err := s.repository.UpdateState(pledge, StatusPaused, nil)
+	if err != nil {
+		return pledge, err
+	}
+	if !updated {
+		return pledge, errors.New("Recurring pledge was changed by another process, please try again")
+	}
+	return pledge, nil
+}
+
+// CancelPledge membatalkan pledge, dukungan yang sudah dibayar tetap tercatat di campaign
+func (s *service) CancelPledge(input GetPledgeInput) (Pledge, error) {
+	pledge, err := s.findOwnedPledge(input)
+	if err != nil {
+		return pledge, err
+	}
+
+	if pledge.Status == StatusCancelled {
+		return pledge, errors.New("Recurring pledge is already cancelled")
+	}
+
+	fromStatus := pledge.Status
+	now := time.Now()
+	pledge.Status = StatusCancelled
+	pledge.NextChargeAt = nil
+	pledge.CancelledAt = &now
+	pledge.Note = "Cancelled by backer"
+
+	updated, err := s.repository.UpdateState(pledge, fromStatus, nil)
+	if err != nil {
+		return pledge, err
+	}
+	if !updated {
+		return pledge, errors.New("Recurring pledge was changed by another process, please try again")
+	}
+	return pledge, nil
+}
+
+// ActivatePendingPledges memeriksa tagihan pertama pledge pending. Lunas berarti pledge aktif dan ditagih
+// lagi bulan depan di tanggal yang sama, gagal atau expired berarti pledge dibatalkan
+func (s *service) ActivatePendingPledges(batchSize int) (int, error) {
+	activated := 0
+	lastID := 0
+
+	for {
+		pledges, err := s.repository.FindPending(lastID, batchSize)
+		if err != nil {
+			return activated, err
+		}
+
+		for _, pledge := range pledges {
+			lastID = pledge.ID
+
+			changed, err := s.activatePledge(pledge)
+			if err != nil {
+				log.Printf("recurring pledge %d: %v", pledge.ID, err)
+				continue
+			}
+			if changed {
+				activated++
+			}
+		}
+
+		if len(pledges) < batchSize {
+			return activated, nil
+		}
+	}
+}
+
+func (s *service) activatePledge(pledge Pledge) (bool, error) {
+	firstTransaction, err := s.transactionService.GetRecurringTransaction(pledge.ID, 1)
+	if err != nil {
+		return false, err
+	}
+
+	switch firstTransaction.Status {
+	case transaction.StatusPending:
+		return false, nil
+	case transaction.StatusPaid, transaction.StatusPartiallyRefunded:
+		if firstTransaction.PaymentToken == "" {
+			return s.cancelPending(pledge, "Payment method was not saved by the payment provider")
+		}
+
+		paidAt := firstTransaction.UpdatedAt
+		dueAt := nextBillingDate(paidAt, paidAt.Day())
+
+		pledge.Status = StatusActive
+		pledge.PaymentToken = firstTransaction.PaymentToken
+		pledge.CampaignAmount = firstTransaction.CampaignAmount
+		pledge.BillingDay = paidAt.Day()
+		pledge.CurrentPeriod = 1
+		pledge.DueAt = &dueAt
+		pledge.NextChargeAt = &dueAt
+		pledge.LastTransactionID = firstTransaction.ID
+		return s.repository.UpdateState(pledge, StatusPending, nil)
+	default:
+		return s.cancelPending(pledge, "First payment was not completed")
+	}
+}
+
+func (s *service) cancelPending(pledge Pledge, note string) (bool, error) {
+	now := time.Now()
+	pledge.Status = StatusCancelled
+	pledge.Note = note
+	pledge.CancelledAt = &now
+	return s.repository.UpdateState(pledge, StatusPending, nil)
+}
+
+// ChargeDuePledges menagih pledge yang sudah jatuh tempo. Setiap pledge diklaim dengan compare-and-set
+// sehingga aman dijalankan di beberapa instance sekaligus
+func (s *service) ChargeDuePledges(now time.Time, batchSize int) (int, error) {
+	pledges, err := s.repository.FindDue(now, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	charged := 0
+	for _, pledge := range pledges {
+		ok, err := s.chargePledge(pledge, now)
+		if err != nil {
+			log.Printf("recurring pledge %d: %v", pledge.ID, err)
+			continue
+		}
+		if ok {
+			charged++
+		}
+	}
+	return charged, nil
+}
+
+func (s *service) chargePledge(pledge Pledge, now time.Time) (bool, error) {
+	fromStatus := pledge.Status
+	lease := now.Add(chargeLease)
+
+	claimed := pledge
+	claimed.NextChargeAt = &lease
+	ok, err := s.repository.UpdateState(claimed, fromStatus, pledge.NextChargeAt)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	period := pledge.CurrentPeriod + 1
+
+	// tagihan periode ini bisa sudah dibuat proses sebelumnya yang terhenti sebelum pledge diperbarui
+	existing, err := s.transactionService.GetRecurringTransaction(pledge.ID, period)
+	if err != nil {
+		return false, err
+	}
+
+	var chargedTransaction transaction.Transaction
+	var chargeErr error
+
+	switch existing.Status {
+	case transaction.StatusPaid, transaction.StatusPartiallyRefunded:
+		chargedTransaction = existing
+	case transaction.StatusPending:
+		// menunggu hasil dari provider, diperiksa lagi setelah lease habis
+		return false, nil
+	default:
+		chargedTransaction, chargeErr = s.transactionService.ChargeRecurring(s.transactionInput(pledge, period), pledge.PaymentToken)
+		if chargeErr == nil && chargedTransaction.Status != transaction.StatusPaid {
+			chargeErr = errors.New("Recurring charge was not paid")
+		}
+	}
+
+	result := claimed
+	if chargedTransaction.ID != 0 {
+		result.LastTransactionID = chargedTransaction.ID
+	}
+
+	if chargeErr == nil {
+		dueAt := now
+		if pledge.DueAt != nil {
+			dueAt = *pledge.DueAt
+		}
+		dueAt = nextBillingDate(dueAt, pledge.BillingDay)
+
+		result.Status = StatusActive
+		result.CurrentPeriod = period
+		result.CampaignAmount = chargedTransaction.CampaignAmount
+		result.FailedAttempts = 0
+		result.DueAt = &dueAt
+		result.NextChargeAt = &dueAt
+		result.Note = ""
+	} else {
+		s.applyDunning(&result, chargeErr, now)
+	}
+
+	updated, err := s.repository.UpdateState(result, fromStatus, &lease)
+	if err != nil {
+		return false, err
+	}
+	if !updated {
+		// pledge di-pause atau dibatalkan selama tagihan berjalan, tagihan yang lunas dihitung saat periode ini diperiksa lagi
+		return false, nil
+	}
+
+	if chargeErr != nil {
+		s.notifyFailure(result)
+		return false, nil
+	}
+	return true, nil
+}
+
+// applyDunning menjadwalkan ulang tagihan yang gagal sesuai RetryDelays, pledge dibatalkan jika semua percobaan gagal
+func (s *service) applyDunning(pledge *Pledge, chargeErr error, now time.Time) {
+	pledge.FailedAttempts++
+	pledge.Note = chargeErr.Error()
+
+	if pledge.FailedAttempts > len(s.dunning.RetryDelays) {
+		pledge.Status = StatusCancelled
+		pledge.NextChargeAt = nil
+		pledge.CancelledAt = &now
+		pledge.Note = fmt.Sprintf("Cancelled after %d failed charges: %s", pledge.FailedAttempts, chargeErr.Error())
+		return
+	}
+
+	retryAt := now.Add(s.dunning.RetryDelays[pledge.FailedAttempts-1])
+	pledge.Status = StatusPastDue
+	pledge.NextChargeAt = &retryAt
+}
+
+func (s *service) transactionInput(pledge Pledge, period int) transaction.CreateTransactionInput {
+	input := transaction.CreateTransactionInput{}
+	input.Amount = pledge.Amount
+	input.CampaignID = pledge.CampaignID
+	input.PaymentProvider = pledge.PaymentProvider
+	input.Currency = pledge.Currency
+	input.Visibility = pledge.Visibility
+	input.DisplayName = pledge.DisplayName
+	input.RecurringPledgeID = pledge.ID
+	input.BillingPeriod = period
+	input.User = pledge.User
+	return input
+}
+
+// notifyFailure memberi tahu backer bahwa tagihan bulanan gagal, notifikasi gagal dikirim hanya dicatat di log
+func (s *service) notifyFailure(pledge Pledge) {
+	notificationInput := notification.NotificationInput{}
+	notificationInput.Type = "recurring_payment_failed"
+	notificationInput.Link = "/api/v1/recurring-pledges"
+
+	if pledge.Status == StatusCancelled {
+		notificationInput.Title = "Recurring pledge cancelled"
+		notificationInput.Message = fmt.Sprintf("We could not charge %s after %d attempts, your monthly pledge has been cancelled", pledge.AmountFormatted(), pledge.FailedAttempts)
+	} else {
+		notificationInput.Title = "Recurring payment failed"
+		notificationInput.Message = fmt.Sprintf("We could not charge %s for your monthly pledge, we will try again on %s", pledge.AmountFormatted(), pledge.NextChargeAt.Format("2 January 2006"))
+	}
+
+	_, err := s.notificationService.NotifyUsers([]int{pledge.UserID}, notificationInput)
+	if err != nil {
+		log.Printf("recurring pledge %d: notify failure: %v", pledge.ID, err)
+	}
+}
+
+func (s *service) GetRevenue(userID int) ([]Revenue, error) {
+	revenues, err := s.repository.GetRevenue(userID)
+	if err != nil {
+		return revenues, err
+	}
+	return revenues, nil
+}
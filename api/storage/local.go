@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type localStorage struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStorage menyimpan file di disk, key "images/x.jpg" disimpan di dir/images/x.jpg
+func NewLocalStorage(dir string, baseURL string) *localStorage {
+	return &localStorage{dir, strings.TrimRight(baseURL, "/")}
+}
+
+func (s *localStorage) path(key string) (string, error) {
+	cleanKey := filepath.Clean("/" + key)
+	if cleanKey == "/" {
+		return "", errors.New("Invalid file key")
+	}
+	return filepath.Join(s.dir, cleanKey), nil
+}
+
+func (s *localStorage) Put(key string, file io.Reader, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0750)
+	if err != nil {
+		return err
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, file)
+	return err
+}
+
+func (s *localStorage) Get(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+func (s *localStorage) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *localStorage) URL(key string) string {
+	return s.baseURL + "/" + strings.TrimLeft(key, "/")
+}
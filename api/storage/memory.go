@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+)
+
+// memoryStorage menyimpan file di memory, dipakai untuk development dan testing tanpa disk/S3
+type memoryStorage struct {
+	mu      sync.RWMutex
+	files   map[string][]byte
+	baseURL string
+}
+
+func NewMemoryStorage(baseURL string) *memoryStorage {
+	return &memoryStorage{files: map[string][]byte{}, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+func (s *memoryStorage) Put(key string, file io.Reader, contentType string) error {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[key] = data
+	return nil
+}
+
+func (s *memoryStorage) Get(key string) (io.ReadCloser, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, ok := s.files[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *memoryStorage) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.files, key)
+	return nil
+}
+
+func (s *memoryStorage) URL(key string) string {
+	return s.baseURL + "/" + strings.TrimLeft(key, "/")
+}
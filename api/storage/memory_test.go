@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func readAll(t *testing.T, file io.ReadCloser) string {
+	t.Helper()
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	return string(data)
+}
+
+func TestMemoryStorage(t *testing.T) {
+	fileStorage := NewMemoryStorage("https://files.bwastartup.test/")
+	key := "images/avatars/1/photo.jpg"
+
+	_, err := fileStorage.Get(key)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get before Put error = %v, want ErrNotFound", err)
+	}
+
+	err = fileStorage.Put(key, strings.NewReader("first"), "image/jpeg")
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	file, err := fileStorage.Get(key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if content := readAll(t, file); content != "first" {
+		t.Errorf("Get = %q, want %q", content, "first")
+	}
+
+	// Put ke key yang sama menimpa isi file
+	err = fileStorage.Put(key, strings.NewReader("second"), "image/jpeg")
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	file, err = fileStorage.Get(key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if content := readAll(t, file); content != "second" {
+		t.Errorf("Get after overwrite = %q, want %q", content, "second")
+	}
+
+	err = fileStorage.Delete(key)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	_, err = fileStorage.Get(key)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete error = %v, want ErrNotFound", err)
+	}
+
+	// menghapus file yang tidak ada bukan error
+	err = fileStorage.Delete(key)
+	if err != nil {
+		t.Errorf("Delete missing file: %v", err)
+	}
+}
+
+func TestMemoryStorageURL(t *testing.T) {
+	cases := []struct {
+		baseURL string
+		key     string
+		want    string
+	}{
+		{"https://files.bwastartup.test/", "images/avatars/1/photo.jpg", "https://files.bwastartup.test/images/avatars/1/photo.jpg"},
+		{"https://files.bwastartup.test", "/images/avatars/1/photo.jpg", "https://files.bwastartup.test/images/avatars/1/photo.jpg"},
+		{"", "images/avatars/1/photo.jpg", "/images/avatars/1/photo.jpg"},
+	}
+
+	for _, c := range cases {
+		if got := NewMemoryStorage(c.baseURL).URL(c.key); got != c.want {
+			t.Errorf("URL(%q) with base %q = %q, want %q", c.key, c.baseURL, got, c.want)
+		}
+	}
+}
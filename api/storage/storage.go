@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+var ErrNotFound = errors.New("File not found")
+
+// Storage tempat menyimpan file upload (avatar dan gambar campaign).
+// key adalah path relatif file, misalnya "images/3-photo.jpg", dan disimpan di database
+type Storage interface {
+	Put(key string, file io.Reader, contentType string) error
+	Get(key string) (io.ReadCloser, error)
+	Delete(key string) error
+	URL(key string) string
+}
+
+type Config struct {
+	// Driver: local (default), s3 atau memory
+	Driver string
+
+	LocalDir string
+	BaseURL  string
+
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	// S3PublicURL origin publik bucket (misalnya CDN), kosong berarti pakai endpoint/bucket
+	S3PublicURL string
+}
+
+// NewConfigFromEnv membaca konfigurasi storage dari environment variable
+func NewConfigFromEnv() Config {
+	config := Config{}
+	config.Driver = getEnv("STORAGE_DRIVER", "local")
+	config.LocalDir = getEnv("STORAGE_LOCAL_DIR", "..")
+	config.BaseURL = getEnv("STORAGE_BASE_URL", "")
+	config.S3Endpoint = os.Getenv("STORAGE_S3_ENDPOINT")
+	config.S3Region = getEnv("STORAGE_S3_REGION", "us-east-1")
+	config.S3Bucket = os.Getenv("STORAGE_S3_BUCKET")
+	config.S3AccessKey = os.Getenv("STORAGE_S3_ACCESS_KEY")
+	config.S3SecretKey = os.Getenv("STORAGE_S3_SECRET_KEY")
+	config.S3PublicURL = os.Getenv("STORAGE_S3_PUBLIC_URL")
+	return config
+}
+
+func New(config Config) (Storage, error) {
+	switch config.Driver {
+	case "", "local":
+		return NewLocalStorage(config.LocalDir, config.BaseURL), nil
+	case "s3":
+		if config.S3Endpoint == "" || config.S3Bucket == "" {
+			return nil, errors.New("STORAGE_S3_ENDPOINT and STORAGE_S3_BUCKET are required for s3 storage")
+		}
+		return NewS3Storage(config.S3Endpoint, config.S3Region, config.S3Bucket, config.S3AccessKey, config.S3SecretKey, config.S3PublicURL), nil
+	case "memory":
+		return NewMemoryStorage(config.BaseURL), nil
+	}
+	return nil, errors.New("Unknown storage driver: " + config.Driver)
+}
+
+func getEnv(key string, defaultValue string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
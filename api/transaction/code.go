@@ -0,0 +1,55 @@
+package transaction
+
+import (
+	"crypto/rand"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultCodePrefix = "BWA"
+
+// huruf dan angka yang mudah dibaca, tanpa 0/O dan 1/I/L yang sering tertukar
+const codeAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+
+const codeLength = 6
+
+// CodeGenerator membuat kode transaksi, misalnya BWA-2026-7KQ2XD. Kode juga dipakai sebagai order ID di payment provider
+type CodeGenerator interface {
+	Generate() (string, error)
+}
+
+type codeGenerator struct {
+	prefix string
+}
+
+func NewCodeGenerator(prefix string) *codeGenerator {
+	if prefix == "" {
+		prefix = DefaultCodePrefix
+	}
+	return &codeGenerator{strings.ToUpper(prefix)}
+}
+
+// CodePrefixFromEnv membaca TRANSACTION_CODE_PREFIX, gunakan prefix berbeda untuk setiap environment
+// yang memakai akun sandbox payment provider yang sama
+func CodePrefixFromEnv() string {
+	return os.Getenv("TRANSACTION_CODE_PREFIX")
+}
+
+// Generate bagian acak diambil dari crypto/rand sehingga kode tidak bisa ditebak dari kode sebelumnya
+func (g *codeGenerator) Generate() (string, error) {
+	random := make([]byte, codeLength)
+	max := big.NewInt(int64(len(codeAlphabet)))
+
+	for i := range random {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		random[i] = codeAlphabet[n.Int64()]
+	}
+
+	return g.prefix + "-" + strconv.Itoa(time.Now().Year()) + "-" + string(random), nil
+}
@@ -3,9 +3,8 @@ package transaction
 import (
 	"bwastartup/api/campaign"
 	"bwastartup/api/user"
+	"bwastartup/api/money"
 	"time"
-
-	"github.com/leekchan/accounting"
 )
 
 type Transaction struct {
@@ -14,15 +13,169 @@ type Transaction struct {
 	UserID     int
 	Amount     int
 	Status     string
+	// StatusReason alasan perubahan status terakhir (notifikasi provider atau sweeper expiry)
+	StatusReason string
 	Code       string
 	PaymentURL string
+	PaymentProvider  string
+	PaymentReference string
+	RefundedAmount   int
+	// PlatformFee, GatewayFee dan NetAmount (Amount - kedua fee) diisi saat transaksi menjadi paid.
+	// Fee tidak dikembalikan saat refund
+	PlatformFee      int
+	GatewayFee       int
+	NetAmount        int
+	// Currency mata uang yang dibayar backer, Amount dan RefundedAmount dalam minor unit mata uang ini.
+	// CampaignAmount adalah Amount dalam mata uang campaign dengan kurs ExchangeRate saat transaksi dibuat,
+	// dipakai untuk total campaign, ledger dan fee
+	Currency         string
+	ExchangeRate     float64
+	CampaignAmount   int
+	// Visibility pilihan backer bagaimana namanya ditampilkan (Visibility*), DisplayName dipakai jika VisibilityDisplayName
+	Visibility       string
+	DisplayName      string
+	// Message pesan dukungan opsional dari backer
+	Message          string
+	// RecurringPledgeID dan BillingPeriod (mulai dari 1) terisi untuk tagihan recurring pledge, 0 untuk dukungan sekali bayar
+	RecurringPledgeID int
+	BillingPeriod     int
+	// PaymentToken metode pembayaran tersimpan dari provider, hanya untuk tagihan pertama recurring pledge
+	PaymentToken      string
 	User 			 user.User
 	Campaign   campaign.Campaign
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
 }
 
-func (t Transaction) AmountFormatIDR() string {
-	ac := accounting.Accounting{Symbol: "Rp.", Precision: 2, Thousand: ".", Decimal: ","}
-	return ac.FormatMoney(t.Amount)
+const (
+	StatusPending   = "pending"
+	StatusPaid      = "paid"
+	StatusCancelled = "cancelled"
+	StatusExpired   = "expired"
+	StatusRefunded  = "refunded"
+	// StatusPartiallyRefunded sebagian dana sudah dikembalikan, sisanya masih dihitung di campaign
+	StatusPartiallyRefunded = "partially_refunded"
+)
+
+// pilihan tampilan backer, berlaku untuk pemilik campaign maupun supporter wall
+const (
+	VisibilityPublic      = "public"
+	VisibilityDisplayName = "display_name"
+	VisibilityAnonymous   = "anonymous"
+)
+
+const AnonymousBackerName = "Anonymous"
+
+// BackerName nama backer sesuai pilihan tampilannya
+func (t Transaction) BackerName() string {
+	switch t.Visibility {
+	case VisibilityAnonymous:
+		return AnonymousBackerName
+	case VisibilityDisplayName:
+		return t.DisplayName
+	}
+	return t.User.Name
+}
+
+// backerCount tagihan lanjutan recurring pledge tidak menambah jumlah backer campaign
+func (t Transaction) backerCount() int {
+	if t.BillingPeriod > 1 {
+		return 0
+	}
+	return 1
+}
+
+// transitions perpindahan status yang diperbolehkan, selain ini notifikasi diabaikan
+// (misalnya settlement yang datang setelah transaksi di-refund)
+var transitions = map[string][]string{
+	StatusPending: {StatusPaid, StatusCancelled, StatusExpired},
+	StatusPaid:    {StatusRefunded, StatusPartiallyRefunded},
+	StatusPartiallyRefunded: {StatusRefunded, StatusPartiallyRefunded},
+}
+
+func CanTransition(from string, to string) bool {
+	for _, status := range transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// PaymentNotification log notifikasi pembayaran yang sudah diproses, EventKey unik per kejadian dari provider
+type PaymentNotification struct {
+	ID                int
+	TransactionID     int
+	Provider          string
+	EventKey          string
+	TransactionStatus string
+	StatusCode        string
+	PreviousStatus    string
+	NewStatus         string
+	CreatedAt         time.Time
+}
+
+// RefundableAmount sisa dana transaksi yang masih bisa dikembalikan
+func (t Transaction) RefundableAmount() int {
+	if t.Status != StatusPaid && t.Status != StatusPartiallyRefunded {
+		return 0
+	}
+	return t.Amount - t.RefundedAmount
+}
+
+// status refund
+const (
+	RefundStatusPending   = "pending"
+	RefundStatusSucceeded = "succeeded"
+	RefundStatusFailed    = "failed"
+)
+
+// siapa yang mengajukan refund
+const (
+	RefundRequestedByAdmin   = "admin"
+	RefundRequestedByCreator = "creator"
+)
+
+// Refund pengembalian dana (penuh atau sebagian) dari satu transaksi
+type Refund struct {
+	ID                int
+	TransactionID     int
+	Amount            int
+	Reason            string
+	Status            string
+	RequestedBy       string
+	RequestedByUserID int
+	ProviderReference string
+	FailureReason     string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// FeeReport total transaksi yang sudah dibayar per campaign, semua amount dalam mata uang campaign
+type FeeReport struct {
+	CampaignID       int
+	CampaignName     string
+	Currency         string
+	TransactionCount int
+	GrossAmount      int
+	RefundedAmount   int
+	PlatformFee      int
+	GatewayFee       int
+	NetAmount        int
+}
+
+func (t Transaction) AmountFormatted() string {
+	return money.New(t.Amount, t.Currency).Format()
+}
+
+// CampaignAmountFor mengubah amount dalam mata uang transaksi (misalnya jumlah refund) ke mata uang campaign
+// secara proporsional terhadap CampaignAmount, sehingga amount penuh selalu menghasilkan CampaignAmount
+func (t Transaction) CampaignAmountFor(amount int) int {
+	if t.Amount == 0 {
+		return 0
+	}
+	if amount == t.Amount {
+		return t.CampaignAmount
+	}
+	return int((int64(amount)*int64(t.CampaignAmount)*2 + int64(t.Amount)) / (int64(t.Amount) * 2))
 }
\ No newline at end of file
@@ -0,0 +1,86 @@
+package transaction
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultPendingTTL     = 24 * time.Hour
+	DefaultExpiryInterval = 15 * time.Minute
+	DefaultExpiryBatch    = 100
+)
+
+// ExpiryConfig konfigurasi pemeriksaan transaksi pending yang sudah terlalu lama
+type ExpiryConfig struct {
+	// PendingTTL umur transaksi pending sebelum statusnya dipastikan ke payment provider
+	PendingTTL time.Duration
+	// Interval jarak antar pemeriksaan, 0 berarti sweeper tidak dijalankan
+	Interval  time.Duration
+	BatchSize int
+}
+
+// NewExpiryConfigFromEnv membaca TRANSACTION_PENDING_TTL, TRANSACTION_EXPIRY_INTERVAL (dalam detik)
+// dan TRANSACTION_EXPIRY_BATCH_SIZE
+func NewExpiryConfigFromEnv() ExpiryConfig {
+	config := ExpiryConfig{DefaultPendingTTL, DefaultExpiryInterval, DefaultExpiryBatch}
+
+	ttl, err := strconv.Atoi(os.Getenv("TRANSACTION_PENDING_TTL"))
+	if err == nil && ttl > 0 {
+		config.PendingTTL = time.Duration(ttl) * time.Second
+	}
+
+	interval, err := strconv.Atoi(os.Getenv("TRANSACTION_EXPIRY_INTERVAL"))
+	if err == nil && interval >= 0 {
+		config.Interval = time.Duration(interval) * time.Second
+	}
+
+	batchSize, err := strconv.Atoi(os.Getenv("TRANSACTION_EXPIRY_BATCH_SIZE"))
+	if err == nil && batchSize > 0 {
+		config.BatchSize = batchSize
+	}
+	return config
+}
+
+// ExpirySweeper menjalankan ExpireStaleTransactions secara berkala. Aman dijalankan di beberapa instance
+// sekaligus karena perubahan status memakai compare-and-set
+type ExpirySweeper struct {
+	service Service
+	config  ExpiryConfig
+}
+
+func NewExpirySweeper(service Service, config ExpiryConfig) *ExpirySweeper {
+	return &ExpirySweeper{service, config}
+}
+
+// Start menjalankan sweeper di goroutine terpisah, pemeriksaan pertama langsung dijalankan
+func (s *ExpirySweeper) Start() {
+	if s.config.Interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			s.Sweep()
+			<-ticker.C
+		}
+	}()
+}
+
+// Sweep satu kali pemeriksaan transaksi pending yang dibuat sebelum sekarang dikurangi PendingTTL
+func (s *ExpirySweeper) Sweep() {
+	createdBefore := time.Now().Add(-s.config.PendingTTL)
+
+	checked, err := s.service.ExpireStaleTransactions(createdBefore, s.config.BatchSize)
+	if err != nil {
+		log.Println("transaction expiry:", err.Error())
+	}
+	if checked > 0 {
+		log.Printf("transaction expiry: %d stale pending transactions checked", checked)
+	}
+}
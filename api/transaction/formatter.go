@@ -1,19 +1,39 @@
 package transaction
 
-import "time"
+import (
+	"bwastartup/api/asset"
+	"bwastartup/api/money"
+	"time"
+)
 
 type CampaignTransactionFormatter struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	Amount    int       `json:"amount"`
-	CreatedAt time.Time `json:"created_at"`
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Amount      int       `json:"amount"`
+	Currency    string    `json:"currency"`
+	// CampaignAmount, PlatformFee, GatewayFee dan NetAmount dalam mata uang campaign
+	CampaignAmount int    `json:"campaign_amount"`
+	PlatformFee int       `json:"platform_fee"`
+	GatewayFee  int       `json:"gateway_fee"`
+	NetAmount   int       `json:"net_amount"`
+	// Name mengikuti pilihan tampilan backer, backer anonymous juga tidak diketahui pemilik campaign
+	Visibility  string    `json:"visibility"`
+	Message     string    `json:"message"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 func FormatCampaignTransaction(transaction Transaction) CampaignTransactionFormatter{
 	formatter := CampaignTransactionFormatter{}
 	formatter.ID = transaction.ID
-	formatter.Name = transaction.User.Name
+	formatter.Name = transaction.BackerName()
 	formatter.Amount = transaction.Amount
+	formatter.Currency = money.NormalizeCurrency(transaction.Currency)
+	formatter.CampaignAmount = transaction.CampaignAmount
+	formatter.PlatformFee = transaction.PlatformFee
+	formatter.GatewayFee = transaction.GatewayFee
+	formatter.NetAmount = transaction.NetAmount
+	formatter.Visibility = transaction.Visibility
+	formatter.Message = transaction.Message
 	formatter.CreatedAt = transaction.CreatedAt
 	return formatter
 }
@@ -34,8 +54,16 @@ func FormatCampaignTransactions(transactions []Transaction) []CampaignTransactio
 
 type UserTransactionFormatter struct{
 	ID 		 		int 							`json:"id"`
+	Code 		 	string 						`json:"code"`
 	Amount 		int 							`json:"amount"`
+	Currency 	string 						`json:"currency"`
 	Status 		string 						`json:"status"`
+	Visibility  string            `json:"visibility"`
+	DisplayName string            `json:"display_name"`
+	Message     string            `json:"message"`
+	// RecurringPledgeID dan BillingPeriod 0 untuk dukungan sekali bayar
+	RecurringPledgeID int           `json:"recurring_pledge_id"`
+	BillingPeriod     int           `json:"billing_period"`
 	CreatedAt time.Time 				`json:"created_at"`
 	Campaign  CampaignFormatter `json:"campaign"`
 }
@@ -48,15 +76,22 @@ type CampaignFormatter struct{
 func FormatUserTransaction(transaction Transaction) UserTransactionFormatter{
 	formatter := UserTransactionFormatter{}
 	formatter.ID = transaction.ID
+	formatter.Code = transaction.Code
 	formatter.Amount = transaction.Amount
+	formatter.Currency = money.NormalizeCurrency(transaction.Currency)
 	formatter.Status = transaction.Status
+	formatter.Visibility = transaction.Visibility
+	formatter.DisplayName = transaction.DisplayName
+	formatter.Message = transaction.Message
+	formatter.RecurringPledgeID = transaction.RecurringPledgeID
+	formatter.BillingPeriod = transaction.BillingPeriod
 	formatter.CreatedAt = transaction.CreatedAt
 
 	campaignFormatter := CampaignFormatter{}
 	campaignFormatter.Name = transaction.Campaign.Name
 	campaignFormatter.ImageURL = ""
 	if len(transaction.Campaign.CampaignImages) > 0 {
-		campaignFormatter.ImageURL = transaction.Campaign.CampaignImages[0].FileName
+		campaignFormatter.ImageURL = asset.URL(transaction.Campaign.CampaignImages[0].FileName)
 	}
 	formatter.Campaign = campaignFormatter
 	return formatter
@@ -81,9 +116,17 @@ type TransactionFormatter struct {
 	CampaignID  int    `json:"campaign_id"`
 	UserID      int    `json:"user_id"`
 	Amount    	int    `json:"amount"`
+	Currency    string `json:"currency"`
+	ExchangeRate float64 `json:"exchange_rate"`
+	CampaignAmount int  `json:"campaign_amount"`
 	Status    	string `json:"status"`
 	Code    		string `json:"code"`
 	PaymentURL  string `json:"payment_url"`
+	PaymentProvider string `json:"payment_provider"`
+	RefundedAmount  int    `json:"refunded_amount"`
+	Visibility      string `json:"visibility"`
+	DisplayName     string `json:"display_name"`
+	Message         string `json:"message"`
 }
 
 func FormatTransaction(transaction Transaction) TransactionFormatter{
@@ -92,8 +135,145 @@ func FormatTransaction(transaction Transaction) TransactionFormatter{
 	formatter.CampaignID = transaction.CampaignID
 	formatter.UserID = transaction.UserID
 	formatter.Amount = transaction.Amount
+	formatter.Currency = money.NormalizeCurrency(transaction.Currency)
+	formatter.ExchangeRate = transaction.ExchangeRate
+	formatter.CampaignAmount = transaction.CampaignAmount
 	formatter.Status = transaction.Status
 	formatter.Code = transaction.Code
 	formatter.PaymentURL = transaction.PaymentURL
+	formatter.PaymentProvider = transaction.PaymentProvider
+	formatter.RefundedAmount = transaction.RefundedAmount
+	formatter.Visibility = transaction.Visibility
+	formatter.DisplayName = transaction.DisplayName
+	formatter.Message = transaction.Message
+	return formatter
+}
+
+type SupporterFormatter struct {
+	Name string `json:"name"`
+	// ImageURL avatar hanya ditampilkan untuk backer public
+	ImageURL  string    `json:"image_url"`
+	Amount    int       `json:"amount"`
+	Currency  string    `json:"currency"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SupporterPageFormatter struct {
+	Supporters []SupporterFormatter `json:"supporters"`
+	Page       int                  `json:"page"`
+	Limit      int                  `json:"limit"`
+	Total      int64                `json:"total"`
+}
+
+func FormatSupporter(transaction Transaction) SupporterFormatter {
+	formatter := SupporterFormatter{}
+	formatter.Name = transaction.BackerName()
+	formatter.ImageURL = ""
+	if transaction.Visibility == VisibilityPublic && transaction.User.AvatarFileName != "" {
+		formatter.ImageURL = asset.URL(transaction.User.AvatarFileName)
+	}
+	formatter.Amount = transaction.Amount
+	formatter.Currency = money.NormalizeCurrency(transaction.Currency)
+	formatter.Message = transaction.Message
+	formatter.CreatedAt = transaction.CreatedAt
+	return formatter
+}
+
+func FormatSupporterPage(transactions []Transaction, page int, limit int, total int64) SupporterPageFormatter {
+	formatter := SupporterPageFormatter{}
+	formatter.Supporters = []SupporterFormatter{}
+	for _, transaction := range transactions {
+		formatter.Supporters = append(formatter.Supporters, FormatSupporter(transaction))
+	}
+	formatter.Page = page
+	formatter.Limit = limit
+	formatter.Total = total
 	return formatter
-}
\ No newline at end of file
+}
+type RefundFormatter struct {
+	ID            int       `json:"id"`
+	TransactionID int       `json:"transaction_id"`
+	Amount        int       `json:"amount"`
+	Reason        string    `json:"reason"`
+	Status        string    `json:"status"`
+	RequestedBy   string    `json:"requested_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func FormatRefund(refund Refund) RefundFormatter {
+	formatter := RefundFormatter{}
+	formatter.ID = refund.ID
+	formatter.TransactionID = refund.TransactionID
+	formatter.Amount = refund.Amount
+	formatter.Reason = refund.Reason
+	formatter.Status = refund.Status
+	formatter.RequestedBy = refund.RequestedBy
+	formatter.CreatedAt = refund.CreatedAt
+	return formatter
+}
+
+type FeeReportFormatter struct {
+	CampaignID       int    `json:"campaign_id"`
+	CampaignName     string `json:"campaign_name"`
+	Currency         string `json:"currency"`
+	TransactionCount int    `json:"transaction_count"`
+	GrossAmount      int    `json:"gross_amount"`
+	RefundedAmount   int    `json:"refunded_amount"`
+	PlatformFee      int    `json:"platform_fee"`
+	GatewayFee       int    `json:"gateway_fee"`
+	NetAmount        int    `json:"net_amount"`
+}
+
+type FeeReportSummaryFormatter struct {
+	Campaigns []FeeReportFormatter `json:"campaigns"`
+	// Totals total per mata uang, amount dari mata uang berbeda tidak dijumlahkan
+	Totals []FeeReportFormatter `json:"totals"`
+}
+
+func FormatFeeReport(report FeeReport) FeeReportFormatter {
+	formatter := FeeReportFormatter{}
+	formatter.CampaignID = report.CampaignID
+	formatter.CampaignName = report.CampaignName
+	formatter.Currency = money.NormalizeCurrency(report.Currency)
+	formatter.TransactionCount = report.TransactionCount
+	formatter.GrossAmount = report.GrossAmount
+	formatter.RefundedAmount = report.RefundedAmount
+	formatter.PlatformFee = report.PlatformFee
+	formatter.GatewayFee = report.GatewayFee
+	formatter.NetAmount = report.NetAmount
+	return formatter
+}
+
+// FormatFeeReports laporan per campaign beserta total per mata uang
+func FormatFeeReports(reports []FeeReport) FeeReportSummaryFormatter {
+	summary := FeeReportSummaryFormatter{}
+	summary.Campaigns = []FeeReportFormatter{}
+	summary.Totals = []FeeReportFormatter{}
+
+	totals := map[string]*FeeReport{}
+	currencies := []string{}
+	for _, report := range reports {
+		summary.Campaigns = append(summary.Campaigns, FormatFeeReport(report))
+
+		currency := money.NormalizeCurrency(report.Currency)
+		total, ok := totals[currency]
+		if !ok {
+			total = &FeeReport{Currency: currency}
+			totals[currency] = total
+			currencies = append(currencies, currency)
+		}
+
+		total.TransactionCount += report.TransactionCount
+		total.GrossAmount += report.GrossAmount
+		total.RefundedAmount += report.RefundedAmount
+		total.PlatformFee += report.PlatformFee
+		total.GatewayFee += report.GatewayFee
+		total.NetAmount += report.NetAmount
+	}
+
+	for _, currency := range currencies {
+		summary.Totals = append(summary.Totals, FormatFeeReport(*totals[currency]))
+	}
+	return summary
+}
@@ -2,6 +2,7 @@ package transaction
 
 import (
 	"bwastartup/api/user"
+	"net/http"
 )
 
 type GetCampaignTransactionsInput struct {
@@ -12,12 +13,62 @@ type GetCampaignTransactionsInput struct {
 type CreateTransactionInput struct {
 	Amount 			int `json:"amount" binding:"required"`
 	CampaignID 	int `json:"campaign_id" binding:"required"`
+	// PaymentProvider opsional, kosong berarti provider default
+	PaymentProvider string `json:"payment_provider"`
+	// Currency mata uang yang dibayar backer, kosong berarti mata uang campaign
+	Currency string `json:"currency"`
+	// Visibility public (default), display_name atau anonymous
+	Visibility  string `json:"visibility"`
+	DisplayName string `json:"display_name"`
+	Message     string `json:"message"`
+	// RecurringPledgeID dan BillingPeriod diisi package recurring, tidak bisa dikirim client
+	RecurringPledgeID int `json:"-"`
+	BillingPeriod     int `json:"-"`
 	User 				user.User
 }
 
+type GetCampaignSupportersInput struct {
+	ID    int `uri:"id" binding:"required"`
+	Page  int
+	Limit int
+}
+
+const (
+	defaultSupportersLimit = 20
+	maxSupportersLimit     = 100
+)
+
+// NormalizeSupportersPagination mengisi nilai default page dan limit serta membatasi limit maksimal
+func NormalizeSupportersPagination(page int, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = defaultSupportersLimit
+	}
+	if limit > maxSupportersLimit {
+		limit = maxSupportersLimit
+	}
+	return page, limit
+}
+
+// TransactionNotificationInput webhook mentah dari payment provider, signature diperiksa dari body aslinya
+// sehingga body tidak di-bind ke struct di handler
 type TransactionNotificationInput struct {
-	TransactionStatus string `json:"transaction_status"`
-	OrderID 					string `json:"order_id"`
-	PaymentType 			string `json:"payment_type"`
-	FraudStatus 			string `json:"fraud_status"`
-}
\ No newline at end of file
+	Provider string
+	Header   http.Header
+	Body     []byte
+}
+
+type GetTransactionDetailInput struct {
+	ID int `uri:"id" binding:"required"`
+}
+
+// RefundTransactionInput amount kosong (0) berarti seluruh sisa dana transaksi dikembalikan
+type RefundTransactionInput struct {
+	Amount int    `json:"amount" form:"amount"`
+	Reason string `json:"reason" form:"reason" binding:"required"`
+	// RequestedBy RefundRequestedByAdmin (CMS) atau RefundRequestedByCreator (pemilik campaign lewat API)
+	RequestedBy string
+	User        user.User
+}
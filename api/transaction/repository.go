@@ -1,6 +1,11 @@
 package transaction
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
 
 type repository struct {
 	db *gorm.DB
@@ -8,11 +13,28 @@ type repository struct {
 
 type Repository interface {
 	GetByCampaignID(campaignID int) ([]Transaction, error)
+	FindSupportersByCampaignID(campaignID int, page int, limit int) ([]Transaction, int64, error)
 	GetByUserID(userID int) ([]Transaction, error)
 	GetByID(ID int) (Transaction, error)
+	GetByCode(code string) (Transaction, error)
 	Save(transaction Transaction) (Transaction, error)
 	Update(transaction Transaction) (Transaction, error)
 	FindAll() ([]Transaction, error)
+	GetPaidBackerIDs(campaignID int) ([]int, error)
+	IsPaidBacker(campaignID int, userID int) (bool, error)
+	UpdateStatus(ID int, fromStatus string, toStatus string, reason string) (bool, error)
+	UpdateFees(ID int, platformFee int, gatewayFee int, netAmount int) error
+	UpdatePaymentToken(ID int, paymentToken string) error
+	FindLatestByRecurringPeriod(recurringPledgeID int, billingPeriod int) (Transaction, error)
+	GetFeeReport(userID int) ([]FeeReport, error)
+	FindPendingCreatedBefore(createdBefore time.Time, afterID int, limit int) ([]Transaction, error)
+	FindSettledByProvider(provider string, createdFrom time.Time, createdTo time.Time) ([]Transaction, error)
+	IsNotificationProcessed(eventKey string) (bool, error)
+	SaveNotification(notification PaymentNotification) error
+	ApplyRefund(ID int, fromStatus string, fromRefundedAmount int, toStatus string, refundedAmount int) (bool, error)
+	SaveRefund(refund Refund) (Refund, error)
+	UpdateRefund(refund Refund) (Refund, error)
+	GetRefundsByTransactionID(transactionID int) ([]Refund, error)
 }
 
 func NewRepository(db *gorm.DB) *repository{
@@ -31,6 +53,25 @@ func (r *repository) GetByCampaignID(campaignID int) ([]Transaction, error){
 
 }
 
+// FindSupportersByCampaignID transaksi yang masih dihitung di campaign (paid dan partially refunded) per halaman beserta totalnya
+func (r *repository) FindSupportersByCampaignID(campaignID int, page int, limit int) ([]Transaction, int64, error) {
+	var transactions []Transaction
+	var total int64
+
+	query := r.db.Model(&Transaction{}).Where("campaign_id = ? AND status IN ?", campaignID, []string{StatusPaid, StatusPartiallyRefunded})
+
+	err := query.Count(&total).Error
+	if err != nil {
+		return transactions, total, err
+	}
+
+	err = query.Preload("User").Order("id desc").Offset((page - 1) * limit).Limit(limit).Find(&transactions).Error
+	if err != nil {
+		return transactions, total, err
+	}
+	return transactions, total, nil
+}
+
 func (r *repository) GetByUserID(userID int) ([]Transaction, error){
 	var transactions []Transaction
 
@@ -44,7 +85,7 @@ func (r *repository) GetByUserID(userID int) ([]Transaction, error){
 
 func (r *repository) GetByID(ID int) (Transaction, error){
 	var transaction Transaction
-	err := r.db.Where("id = ?", ID).Find(&transaction).Error
+	err := r.db.Preload("Campaign").Where("id = ?", ID).Find(&transaction).Error
 
 	if err != nil {
 		return transaction, err
@@ -54,6 +95,17 @@ func (r *repository) GetByID(ID int) (Transaction, error){
 
 }
 
+func (r *repository) GetByCode(code string) (Transaction, error){
+	var transaction Transaction
+	err := r.db.Preload("Campaign").Where("code = ?", code).Find(&transaction).Error
+
+	if err != nil {
+		return transaction, err
+	}
+
+	return transaction, nil
+}
+
 func (r *repository) Save(transaction Transaction) (Transaction, error){
 	err := r.db.Create(&transaction).Error
 	if err != nil {
@@ -79,4 +131,173 @@ func (r *repository) FindAll() ([]Transaction, error){
 		return transactions, err
 	}
 	return transactions, nil
-}
\ No newline at end of file
+}
+
+// GetPaidBackerIDs mengambil user_id unik yang punya transaksi paid pada campaign,
+// refund sebagian tidak menghapus status backer
+func (r *repository) GetPaidBackerIDs(campaignID int) ([]int, error){
+	var userIDs []int
+
+	err := r.db.Model(&Transaction{}).Where("campaign_id = ? AND status IN ?", campaignID, []string{StatusPaid, StatusPartiallyRefunded}).Distinct().Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return userIDs, err
+	}
+	return userIDs, nil
+}
+
+func (r *repository) IsPaidBacker(campaignID int, userID int) (bool, error){
+	var count int64
+
+	err := r.db.Model(&Transaction{}).Where("campaign_id = ? AND user_id = ? AND status IN ?", campaignID, userID, []string{StatusPaid, StatusPartiallyRefunded}).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// UpdateStatus mengubah status hanya jika status saat ini masih fromStatus.
+// Mengembalikan false jika status sudah diubah proses lain (notifikasi yang datang bersamaan)
+func (r *repository) UpdateStatus(ID int, fromStatus string, toStatus string, reason string) (bool, error){
+	result := r.db.Model(&Transaction{}).Where("id = ? AND status = ?", ID, fromStatus).Updates(map[string]interface{}{
+		"status":        toStatus,
+		"status_reason": reason,
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *repository) UpdateFees(ID int, platformFee int, gatewayFee int, netAmount int) error{
+	err := r.db.Model(&Transaction{}).Where("id = ?", ID).Updates(map[string]interface{}{
+		"platform_fee": platformFee,
+		"gateway_fee":  gatewayFee,
+		"net_amount":   netAmount,
+	}).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *repository) UpdatePaymentToken(ID int, paymentToken string) error{
+	err := r.db.Model(&Transaction{}).Where("id = ?", ID).Update("payment_token", paymentToken).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindLatestByRecurringPeriod tagihan terakhir recurring pledge untuk satu periode, ID 0 jika belum pernah ditagih
+func (r *repository) FindLatestByRecurringPeriod(recurringPledgeID int, billingPeriod int) (Transaction, error){
+	var transaction Transaction
+	err := r.db.Where("recurring_pledge_id = ? AND billing_period = ?", recurringPledgeID, billingPeriod).Order("id desc").Limit(1).Find(&transaction).Error
+	if err != nil {
+		return transaction, err
+	}
+	return transaction, nil
+}
+
+// GetFeeReport total per campaign dari transaksi yang pernah dibayar, userID 0 berarti semua campaign
+func (r *repository) GetFeeReport(userID int) ([]FeeReport, error){
+	var reports []FeeReport
+
+	query := r.db.Table("transactions").
+		// refunded_amount dalam mata uang transaksi, diubah ke mata uang campaign seperti Transaction.CampaignAmountFor
+		Select("transactions.campaign_id, campaigns.name AS campaign_name, campaigns.currency, COUNT(*) AS transaction_count, SUM(transactions.campaign_amount) AS gross_amount, SUM(ROUND(transactions.refunded_amount * transactions.campaign_amount / transactions.amount)) AS refunded_amount, SUM(transactions.platform_fee) AS platform_fee, SUM(transactions.gateway_fee) AS gateway_fee, SUM(transactions.net_amount) AS net_amount").
+		Joins("JOIN campaigns ON campaigns.id = transactions.campaign_id").
+		Where("transactions.status IN ?", []string{StatusPaid, StatusPartiallyRefunded, StatusRefunded}).
+		Group("transactions.campaign_id, campaigns.name, campaigns.currency").
+		Order("transactions.campaign_id asc")
+
+	if userID != 0 {
+		query = query.Where("campaigns.user_id = ?", userID)
+	}
+
+	err := query.Scan(&reports).Error
+	if err != nil {
+		return reports, err
+	}
+	return reports, nil
+}
+
+// FindPendingCreatedBefore transaksi pending dengan id setelah afterID, diurutkan dari id terkecil
+func (r *repository) FindPendingCreatedBefore(createdBefore time.Time, afterID int, limit int) ([]Transaction, error){
+	var transactions []Transaction
+
+	err := r.db.Where("status = ? AND created_at < ? AND id > ?", StatusPending, createdBefore, afterID).Order("id asc").Limit(limit).Find(&transactions).Error
+	if err != nil {
+		return transactions, err
+	}
+	return transactions, nil
+}
+
+// FindSettledByProvider transaksi yang sudah dibayar (termasuk yang sudah di-refund) lewat provider
+// dan dibuat pada rentang [createdFrom, createdTo)
+func (r *repository) FindSettledByProvider(provider string, createdFrom time.Time, createdTo time.Time) ([]Transaction, error){
+	var transactions []Transaction
+
+	err := r.db.Where("payment_provider = ? AND status IN ? AND created_at >= ? AND created_at < ?", provider, []string{StatusPaid, StatusPartiallyRefunded, StatusRefunded}, createdFrom, createdTo).Order("id asc").Find(&transactions).Error
+	if err != nil {
+		return transactions, err
+	}
+	return transactions, nil
+}
+
+func (r *repository) IsNotificationProcessed(eventKey string) (bool, error){
+	var count int64
+
+	err := r.db.Model(&PaymentNotification{}).Where("event_key = ?", eventKey).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// SaveNotification menyimpan log notifikasi, notifikasi yang sama (event_key) cukup disimpan sekali
+func (r *repository) SaveNotification(notification PaymentNotification) error{
+	err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&notification).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ApplyRefund mengubah status dan refunded_amount hanya jika keduanya belum diubah proses lain,
+// sehingga dua refund yang diajukan bersamaan tidak bisa melebihi amount transaksi
+func (r *repository) ApplyRefund(ID int, fromStatus string, fromRefundedAmount int, toStatus string, refundedAmount int) (bool, error){
+	result := r.db.Model(&Transaction{}).Where("id = ? AND status = ? AND refunded_amount = ?", ID, fromStatus, fromRefundedAmount).Updates(map[string]interface{}{
+		"status":          toStatus,
+		"refunded_amount": refundedAmount,
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *repository) SaveRefund(refund Refund) (Refund, error){
+	err := r.db.Create(&refund).Error
+	if err != nil {
+		return refund, err
+	}
+	return refund, nil
+}
+
+func (r *repository) UpdateRefund(refund Refund) (Refund, error){
+	err := r.db.Save(&refund).Error
+	if err != nil {
+		return refund, err
+	}
+	return refund, nil
+}
+
+func (r *repository) GetRefundsByTransactionID(transactionID int) ([]Refund, error){
+	var refunds []Refund
+
+	err := r.db.Where("transaction_id = ?", transactionID).Order("id desc").Find(&refunds).Error
+	if err != nil {
+		return refunds, err
+	}
+	return refunds, nil
+}
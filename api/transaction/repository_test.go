@@ -0,0 +1,47 @@
+package transaction
+
+import (
+	"sort"
+	"testing"
+)
+
+// TestPaidBackers backer yang dananya dikembalikan sebagian tetap dihitung backer, refund penuh dan pending tidak
+func TestPaidBackers(t *testing.T) {
+	db := newTestDB(t)
+	testCampaign := createTestCampaign(t, db)
+	repository := NewRepository(db)
+
+	statuses := map[int]string{
+		2: StatusPaid,
+		3: StatusPartiallyRefunded,
+		4: StatusRefunded,
+		5: StatusPending,
+	}
+	for userID, status := range statuses {
+		err := db.Create(&Transaction{CampaignID: testCampaign.ID, UserID: userID, Amount: 50000, Status: status, Code: "TEST-" + status}).Error
+		if err != nil {
+			t.Fatalf("create transaction: %v", err)
+		}
+	}
+
+	userIDs, err := repository.GetPaidBackerIDs(testCampaign.ID)
+	if err != nil {
+		t.Fatalf("GetPaidBackerIDs: %v", err)
+	}
+	sort.Ints(userIDs)
+	if len(userIDs) != 2 || userIDs[0] != 2 || userIDs[1] != 3 {
+		t.Errorf("GetPaidBackerIDs = %v, want [2 3]", userIDs)
+	}
+
+	for userID, status := range statuses {
+		isBacker, err := repository.IsPaidBacker(testCampaign.ID, userID)
+		if err != nil {
+			t.Fatalf("IsPaidBacker: %v", err)
+		}
+
+		want := status == StatusPaid || status == StatusPartiallyRefunded
+		if isBacker != want {
+			t.Errorf("IsPaidBacker for %s transaction = %v, want %v", status, isBacker, want)
+		}
+	}
+}
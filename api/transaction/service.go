@@ -2,27 +2,45 @@ package transaction
 
 import (
 	"bwastartup/api/campaign"
+	"bwastartup/api/fee"
+	"bwastartup/api/ledger"
+	"bwastartup/api/money"
 	"bwastartup/api/payment"
 	"errors"
 	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 type service struct {
 	repository         Repository
 	campaignRepository campaign.Repository
 	paymentService		 payment.Service
+	unitOfWork         UnitOfWork
+	codeGenerator      CodeGenerator
+	feeService         fee.Service
+	rateProvider       money.RateProvider
 }
 
 type Service interface {
 	GetTransactionByCampaignID(input GetCampaignTransactionsInput) ([]Transaction, error)
+	GetCampaignSupporters(input GetCampaignSupportersInput) ([]Transaction, int64, error)
 	GetTransactionsByUserID(userID int) ([]Transaction, error)
 	CreateTransaction(input CreateTransactionInput) (Transaction, error)
+	ChargeRecurring(input CreateTransactionInput, paymentToken string) (Transaction, error)
+	GetRecurringTransaction(recurringPledgeID int, billingPeriod int) (Transaction, error)
 	ProcessPayment(input TransactionNotificationInput) error
 	GetAllTransactions() ([]Transaction, error)
+	ExpireStaleTransactions(createdBefore time.Time, batchSize int) (int, error)
+	GetTransactionByID(ID int) (Transaction, error)
+	GetRefunds(transactionID int) ([]Refund, error)
+	GetFeeReport(userID int) ([]FeeReport, error)
+	RefundTransaction(inputID GetTransactionDetailInput, input RefundTransactionInput) (Refund, error)
 }
 
-func NewService(repository Repository, campaignRepository campaign.Repository, paymentService payment.Service) *service {
-	return &service{repository, campaignRepository, paymentService}
+func NewService(repository Repository, campaignRepository campaign.Repository, paymentService payment.Service, unitOfWork UnitOfWork, codeGenerator CodeGenerator, feeService fee.Service, rateProvider money.RateProvider) *service {
+	return &service{repository, campaignRepository, paymentService, unitOfWork, codeGenerator, feeService, rateProvider}
 }
 
 func (s *service) GetTransactionByCampaignID(input GetCampaignTransactionsInput) ([]Transaction, error) {
@@ -46,6 +64,23 @@ func (s *service) GetTransactionByCampaignID(input GetCampaignTransactionsInput)
 	return transactions, nil
 }
 
+// GetCampaignSupporters supporter wall publik, hanya tersedia jika diaktifkan pemilik campaign
+func (s *service) GetCampaignSupporters(input GetCampaignSupportersInput) ([]Transaction, int64, error) {
+	campaign, err := s.campaignRepository.FindByID(input.ID)
+	if err != nil {
+		return []Transaction{}, 0, err
+	}
+	if campaign.ID == 0 {
+		return []Transaction{}, 0, errors.New("No campaign found with that ID")
+	}
+	if !campaign.SupporterWallEnabled {
+		return []Transaction{}, 0, errors.New("Supporter wall is not enabled for this campaign")
+	}
+
+	page, limit := NormalizeSupportersPagination(input.Page, input.Limit)
+	return s.repository.FindSupportersByCampaignID(campaign.ID, page, limit)
+}
+
 func(s *service) GetTransactionsByUserID(userID int) ([]Transaction, error){
 	transactions, err := s.repository.GetByUserID(userID)
 	if err != nil{
@@ -55,33 +90,27 @@ func(s *service) GetTransactionsByUserID(userID int) ([]Transaction, error){
 }
 
 func(s *service) CreateTransaction(input CreateTransactionInput) (Transaction, error){
-	transaction := Transaction{}
-
-	if input.Amount < 10000 {
-		return transaction, errors.New("Minimal funding Rp.10,000 ")
-	}
-
-	transaction.CampaignID = input.CampaignID
-	transaction.Amount = input.Amount
-	transaction.UserID = input.User.ID
-	transaction.Status = "pending"
-
-	newTransaction, err := s.repository.Save(transaction)
+	newTransaction, campaign, err := s.saveTransaction(input)
 	if err != nil{
 		return newTransaction, err
 	}
 
 	paymentTransaction := payment.Transaction{
 		ID: newTransaction.ID,
+		OrderID: newTransaction.Code,
 		Amount: newTransaction.Amount,
+		Currency: newTransaction.Currency,
+		Description: campaign.Name,
+		SavePaymentMethod: newTransaction.RecurringPledgeID != 0,
 	}
 
-	paymentURL, err := s.paymentService.GetPaymentUrl(paymentTransaction, input.User)
+	charge, err := s.paymentService.CreateCharge(newTransaction.PaymentProvider, paymentTransaction, input.User)
 	if err != nil{
 		return newTransaction, err
 	}
 
-	newTransaction.PaymentURL = paymentURL
+	newTransaction.PaymentURL = charge.PaymentURL
+	newTransaction.PaymentReference = charge.Reference
 
 	newTransaction, err = s.repository.Update(newTransaction)
 	if err != nil{
@@ -91,42 +120,364 @@ func(s *service) CreateTransaction(input CreateTransactionInput) (Transaction, e
 	return newTransaction, nil
 }
 
-func (s *service) ProcessPayment(input TransactionNotificationInput) error{
-	transaction_id, _ := strconv.Atoi(input.OrderID)
+// ChargeRecurring membuat tagihan lanjutan recurring pledge dan langsung menagih metode pembayaran tersimpan.
+// Tagihan yang ditolak provider dicatat cancelled, error dikembalikan supaya package recurring bisa menjalankan dunning
+func (s *service) ChargeRecurring(input CreateTransactionInput, paymentToken string) (Transaction, error) {
+	newTransaction, campaign, err := s.saveTransaction(input)
+	if err != nil {
+		return newTransaction, err
+	}
+
+	paymentTransaction := payment.Transaction{
+		ID:          newTransaction.ID,
+		OrderID:     newTransaction.Code,
+		Amount:      newTransaction.Amount,
+		Currency:    newTransaction.Currency,
+		Description: campaign.Name,
+	}
+
+	notification, chargeErr := s.paymentService.ChargeSaved(newTransaction.PaymentProvider, paymentTransaction, paymentToken)
+	if chargeErr != nil {
+		// provider menolak tagihan sebelum pembayaran dibuat, transaksi ditutup dengan notifikasi lokal
+		notification = payment.Notification{}
+		notification.Provider = newTransaction.PaymentProvider
+		notification.EventKey = newTransaction.PaymentProvider + ":recurring-failed:" + newTransaction.Code
+		notification.OrderID = newTransaction.Code
+		notification.Status = payment.StatusCancelled
+		notification.Amount = newTransaction.Amount
+		notification.RawStatus = "charge_failed"
+	}
+
+	err = s.applyNotification(newTransaction, notification, newTransaction.PaymentProvider+" recurring charge: "+notification.RawStatus)
+	if err != nil {
+		return newTransaction, err
+	}
+
+	chargedTransaction, err := s.repository.GetByID(newTransaction.ID)
+	if err != nil {
+		return newTransaction, err
+	}
+	if chargeErr != nil {
+		return chargedTransaction, chargeErr
+	}
+	return chargedTransaction, nil
+}
+
+// GetRecurringTransaction tagihan terakhir recurring pledge untuk satu periode, ID 0 jika belum pernah ditagih
+func (s *service) GetRecurringTransaction(recurringPledgeID int, billingPeriod int) (Transaction, error) {
+	return s.repository.FindLatestByRecurringPeriod(recurringPledgeID, billingPeriod)
+}
+
+// saveTransaction memvalidasi dukungan lalu menyimpan transaksi pending, tagihan ke provider dibuat oleh pemanggilnya
+func (s *service) saveTransaction(input CreateTransactionInput) (Transaction, campaign.Campaign, error) {
+	transaction := Transaction{}
+
+	paymentProvider := input.PaymentProvider
+	if paymentProvider == "" {
+		paymentProvider = s.paymentService.DefaultProvider()
+	}
+
+	if !s.paymentService.HasProvider(paymentProvider) {
+		return transaction, campaign.Campaign{}, errors.New("Payment provider is not available")
+	}
+
+	if input.RecurringPledgeID != 0 && !s.paymentService.SupportsRecurring(paymentProvider) {
+		return transaction, campaign.Campaign{}, errors.New("Payment provider does not support recurring pledges")
+	}
+
+	campaign, err := s.campaignRepository.FindByID(input.CampaignID)
+	if err != nil{
+		return transaction, campaign, err
+	}
+
+	if campaign.ID == 0 {
+		return transaction, campaign, errors.New("No campaign found with that ID")
+	}
+
+	campaignCurrency := money.NormalizeCurrency(campaign.Currency)
+	currency := campaignCurrency
+	if input.Currency != "" {
+		currency = money.NormalizeCurrency(input.Currency)
+	}
+
+	if !money.IsSupported(currency) {
+		return transaction, campaign, errors.New("Currency is not supported")
+	}
+
+	// kurs disimpan di transaksi supaya refund dan laporan tetap memakai kurs saat backer membayar
+	rate, err := s.rateProvider.Rate(currency, campaignCurrency)
+	if err != nil{
+		return transaction, campaign, err
+	}
+
+	campaignAmount := money.New(input.Amount, currency).Convert(campaignCurrency, rate.Value)
+
+	err = validatePledge(campaign, campaignAmount, currency == campaignCurrency)
+	if err != nil{
+		return transaction, campaign, err
+	}
+
+	err = applyBackerPreferences(&transaction, input)
+	if err != nil{
+		return transaction, campaign, err
+	}
+
+	code, err := s.generateCode()
+	if err != nil{
+		return transaction, campaign, err
+	}
+
+	transaction.CampaignID = input.CampaignID
+	transaction.Code = code
+	transaction.Amount = input.Amount
+	transaction.Currency = currency
+	transaction.ExchangeRate = rate.Value
+	transaction.CampaignAmount = campaignAmount.Amount
+	transaction.UserID = input.User.ID
+	transaction.Status = StatusPending
+	transaction.PaymentProvider = paymentProvider
+	transaction.RecurringPledgeID = input.RecurringPledgeID
+	transaction.BillingPeriod = input.BillingPeriod
+
+	newTransaction, err := s.repository.Save(transaction)
+	if err != nil{
+		return newTransaction, campaign, err
+	}
+
+	return newTransaction, campaign, nil
+}
+
+const (
+	maxDisplayNameLength = 50
+	maxMessageLength     = 500
+)
+
+// applyBackerPreferences mengisi pilihan tampilan dan pesan dukungan backer, visibility kosong berarti public
+func applyBackerPreferences(transaction *Transaction, input CreateTransactionInput) error {
+	visibility := strings.ToLower(strings.TrimSpace(input.Visibility))
+	if visibility == "" {
+		visibility = VisibilityPublic
+	}
+
+	displayName := strings.TrimSpace(input.DisplayName)
+	switch visibility {
+	case VisibilityPublic, VisibilityAnonymous:
+		displayName = ""
+	case VisibilityDisplayName:
+		if displayName == "" {
+			return errors.New("Display name is required")
+		}
+		if utf8.RuneCountInString(displayName) > maxDisplayNameLength {
+			return errors.New("Display name must be at most 50 characters")
+		}
+	default:
+		return errors.New("Visibility must be public, display_name or anonymous")
+	}
+
+	message := strings.TrimSpace(input.Message)
+	if utf8.RuneCountInString(message) > maxMessageLength {
+		return errors.New("Message must be at most 500 characters")
+	}
+
+	transaction.Visibility = visibility
+	transaction.DisplayName = displayName
+	transaction.Message = message
+	return nil
+}
+
+// validatePledge memeriksa amount (dalam mata uang campaign) terhadap aturan dukungan campaign.
+// Kelipatan hanya diperiksa jika backer membayar dengan mata uang campaign karena hasil konversi kurs tidak bulat.
+// Batas overfunding dihitung dari dana yang sudah masuk, transaksi pending yang dibayar bersamaan masih bisa sedikit melewatinya
+func validatePledge(campaign campaign.Campaign, amount money.Money, checkIncrement bool) error {
+	rules := campaign.PledgeRules()
 
-	transaction, err := s.repository.GetByID(transaction_id)
+	if amount.Amount < rules.MinimumAmount {
+		return errors.New("Minimal funding " + money.New(rules.MinimumAmount, amount.Currency).Format())
+	}
+
+	if rules.MaximumAmount > 0 && amount.Amount > rules.MaximumAmount {
+		return errors.New("Maximal funding " + money.New(rules.MaximumAmount, amount.Currency).Format())
+	}
+
+	if checkIncrement && rules.Increment > 0 && amount.Amount%rules.Increment != 0 {
+		return errors.New("Funding must be a multiple of " + money.New(rules.Increment, amount.Currency).Format())
+	}
+
+	fundingLimit := campaign.FundingLimit()
+	if fundingLimit > 0 {
+		if campaign.CurrentAmount >= fundingLimit {
+			return errors.New("Campaign has reached its funding limit")
+		}
+		if campaign.CurrentAmount+amount.Amount > fundingLimit {
+			return errors.New("Funding exceeds the campaign funding limit, remaining " + money.New(fundingLimit-campaign.CurrentAmount, amount.Currency).Format())
+		}
+	}
+	return nil
+}
+
+// generateCode mencoba beberapa kali jika kode acak kebetulan sudah dipakai,
+// unique index pada kolom code tetap menjadi pengaman terakhir
+func (s *service) generateCode() (string, error) {
+	for i := 0; i < 5; i++ {
+		code, err := s.codeGenerator.Generate()
+		if err != nil {
+			return "", err
+		}
+
+		existingTransaction, err := s.repository.GetByCode(code)
+		if err != nil {
+			return "", err
+		}
+		if existingTransaction.ID == 0 {
+			return code, nil
+		}
+	}
+	return "", errors.New("Failed to generate a unique transaction code")
+}
+
+func (s *service) ProcessPayment(input TransactionNotificationInput) error{
+	// status yang dipakai adalah hasil verifikasi provider, bukan langsung dari body notifikasi
+	notification, err := s.paymentService.ParseNotification(input.Provider, input.Header, input.Body)
 	if err != nil {
 		return err
 	}
 
-	if (input.PaymentType == "credit_card" && input.TransactionStatus == "capture" && input.FraudStatus == "accept"){
-		transaction.Status = "paid"
-	} else if input.TransactionStatus == "settlement"{
-		transaction.Status = "paid"
-	} else if input.TransactionStatus == "deny" || input.TransactionStatus == "expire" || input.TransactionStatus == "cancel"{
-		transaction.Status = "cancelled"
+	// event provider yang tidak berhubungan dengan transaksi
+	if notification.OrderID == "" {
+		return nil
 	}
 
-	updatedTransaction, err := s.repository.Update(transaction)
+	// notifikasi yang dikirim ulang provider cukup dianggap berhasil
+	isProcessed, err := s.repository.IsNotificationProcessed(notification.EventKey)
 	if err != nil {
 		return err
 	}
+	if isProcessed {
+		return nil
+	}
 
-	campaign, err := s.campaignRepository.FindByID(updatedTransaction.CampaignID)
+	transaction, err := s.repository.GetByCode(notification.OrderID)
 	if err != nil {
 		return err
 	}
 
-	if updatedTransaction.Status == "paid"{
-		campaign.BackerCount = campaign.BackerCount + 1
-		campaign.CurrentAmount = campaign.CurrentAmount + updatedTransaction.Amount
+	if transaction.ID == 0 {
+		return errors.New("No transaction found with that order ID")
+	}
 
-		_, err := s.campaignRepository.Update(campaign)
-		if err != nil {
-			return err
+	return s.applyNotification(transaction, notification, notification.Provider+" notification: "+notification.RawStatus)
+}
+
+// applyNotification menerapkan status dari provider (webhook atau status API) ke transaksi,
+// reason disimpan sebagai alasan perubahan status
+func (s *service) applyNotification(transaction Transaction, notification payment.Notification, reason string) error {
+	// notifikasi hanya diterima dari provider yang dipakai transaksi tersebut
+	if transaction.PaymentProvider != notification.Provider {
+		return errors.New("Notification provider does not match the transaction")
+	}
+
+	if notification.Amount != transaction.Amount {
+		return errors.New("Gross amount does not match the transaction amount")
+	}
+
+	previousStatus := transaction.Status
+	newStatus := notification.Status
+
+	// perubahan status, total campaign dan log notifikasi disimpan dalam satu transaksi database
+	return s.unitOfWork.Do(func(repository Repository, campaignRepository campaign.Repository, ledgerService ledger.Service) error {
+		var err error
+		isTransitioned := false
+		if newStatus == StatusRefunded && CanTransition(previousStatus, newStatus) {
+			// refund penuh dari dashboard provider, sisa dana yang belum dikembalikan ikut tercatat
+			isTransitioned, err = repository.ApplyRefund(transaction.ID, previousStatus, transaction.RefundedAmount, newStatus, transaction.Amount)
+			if err != nil {
+				return err
+			}
+		} else if newStatus != "" && CanTransition(previousStatus, newStatus) {
+			isTransitioned, err = repository.UpdateStatus(transaction.ID, previousStatus, newStatus, reason)
+			if err != nil {
+				return err
+			}
+		}
+
+		// total campaign hanya berubah sekali, saat transaksi pertama kali berubah menjadi paid
+		// (atau menjadi refunded)
+		if isTransitioned && newStatus == StatusPaid {
+			err = campaignRepository.AddFunding(transaction.CampaignID, transaction.CampaignAmount, transaction.backerCount())
+			if err != nil {
+				return err
+			}
+
+			err = ledgerService.PostPayment(transaction.ID, transaction.CampaignID, transaction.UserID, transaction.CampaignAmount)
+			if err != nil {
+				return err
+			}
+
+			err = s.recordFees(repository, campaignRepository, ledgerService, transaction, notification.GatewayFee)
+			if err != nil {
+				return err
+			}
+
+			// metode pembayaran tersimpan dari tagihan pertama dipakai package recurring untuk tagihan berikutnya
+			if transaction.RecurringPledgeID != 0 && notification.PaymentToken != "" {
+				err = repository.UpdatePaymentToken(transaction.ID, notification.PaymentToken)
+				if err != nil {
+					return err
+				}
+			}
 		}
+
+		if isTransitioned && newStatus == StatusRefunded {
+			remainingAmount := transaction.CampaignAmount - transaction.CampaignAmountFor(transaction.RefundedAmount)
+
+			err = campaignRepository.AddFunding(transaction.CampaignID, -remainingAmount, -transaction.backerCount())
+			if err != nil {
+				return err
+			}
+
+			err = ledgerService.PostRefund("refund:"+notification.EventKey, transaction.ID, transaction.CampaignID, transaction.UserID, remainingAmount)
+			if err != nil {
+				return err
+			}
+		}
+
+		paymentNotification := PaymentNotification{}
+		paymentNotification.TransactionID = transaction.ID
+		paymentNotification.Provider = notification.Provider
+		paymentNotification.EventKey = notification.EventKey
+		paymentNotification.TransactionStatus = notification.RawStatus
+		paymentNotification.StatusCode = notification.StatusCode
+		paymentNotification.PreviousStatus = previousStatus
+		paymentNotification.NewStatus = previousStatus
+		if isTransitioned {
+			paymentNotification.NewStatus = newStatus
+		}
+
+		return repository.SaveNotification(paymentNotification)
+	})
+}
+
+// recordFees menghitung fee platform sesuai policy campaign saat transaksi dibayar,
+// fee payment provider diambil dari notifikasi (dalam mata uang transaksi). Semua fee dicatat dalam mata uang campaign
+func (s *service) recordFees(repository Repository, campaignRepository campaign.Repository, ledgerService ledger.Service, transaction Transaction, gatewayFee int) error {
+	fundedCampaign, err := campaignRepository.FindByID(transaction.CampaignID)
+	if err != nil {
+		return err
 	}
-	return nil
+
+	gatewayFee = transaction.CampaignAmountFor(gatewayFee)
+	platformFee := s.feeService.Calculate(fundedCampaign, transaction.CampaignAmount)
+	if platformFee+gatewayFee > transaction.CampaignAmount {
+		gatewayFee = transaction.CampaignAmount - platformFee
+	}
+	netAmount := transaction.CampaignAmount - platformFee - gatewayFee
+
+	err = repository.UpdateFees(transaction.ID, platformFee, gatewayFee, netAmount)
+	if err != nil {
+		return err
+	}
+
+	return ledgerService.PostFee(transaction.ID, transaction.CampaignID, platformFee, gatewayFee)
 }
 
 func (s *service) GetAllTransactions() ([]Transaction, error) {
@@ -135,4 +486,230 @@ func (s *service) GetAllTransactions() ([]Transaction, error) {
 		return transactions, err
 	}
 	return transactions, nil
-}
\ No newline at end of file
+}
+
+// ExpireStaleTransactions memeriksa transaksi yang masih pending sejak sebelum createdBefore ke payment provider.
+// Transaksi yang tidak pernah dibayar di provider ditandai expired, status lain dari provider (paid, expired,
+// cancelled) diterapkan seperti notifikasi biasa, dan yang masih pending di provider dibiarkan.
+// Transaksi diambil per batchSize supaya yang masih pending di provider tidak menghalangi transaksi setelahnya.
+// Mengembalikan jumlah transaksi yang diperiksa tanpa error
+func (s *service) ExpireStaleTransactions(createdBefore time.Time, batchSize int) (int, error) {
+	checked := 0
+	lastID := 0
+	var lastErr error
+
+	for {
+		transactions, err := s.repository.FindPendingCreatedBefore(createdBefore, lastID, batchSize)
+		if err != nil {
+			return checked, err
+		}
+
+		for _, transaction := range transactions {
+			lastID = transaction.ID
+
+			err = s.expireTransaction(transaction)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			checked++
+		}
+
+		if len(transactions) < batchSize {
+			return checked, lastErr
+		}
+	}
+}
+
+func (s *service) expireTransaction(transaction Transaction) error {
+	notification, err := s.paymentService.GetTransactionStatus(transaction.PaymentProvider, transaction.Code)
+	if errors.Is(err, payment.ErrPaymentNotFound) {
+		// backer tidak pernah menyelesaikan halaman pembayaran sehingga tidak akan ada notifikasi expire
+		notification = payment.Notification{}
+		notification.Provider = transaction.PaymentProvider
+		notification.EventKey = "expiry:" + transaction.Code
+		notification.OrderID = transaction.Code
+		notification.Status = payment.StatusExpired
+		notification.Amount = transaction.Amount
+		notification.RawStatus = "not_found"
+
+		return s.applyNotification(transaction, notification, "No payment was made at "+transaction.PaymentProvider+" before the pending TTL")
+	}
+	if err != nil {
+		return err
+	}
+
+	// masih menunggu pembayaran di provider (misalnya virtual account), provider yang akan mengirim notifikasi expire
+	if notification.Status == "" {
+		return nil
+	}
+
+	isProcessed, err := s.repository.IsNotificationProcessed(notification.EventKey)
+	if err != nil {
+		return err
+	}
+	if isProcessed {
+		return nil
+	}
+
+	return s.applyNotification(transaction, notification, "Status confirmed by "+transaction.PaymentProvider+" after the pending TTL: "+notification.RawStatus)
+}
+
+func (s *service) GetTransactionByID(ID int) (Transaction, error) {
+	transaction, err := s.repository.GetByID(ID)
+	if err != nil {
+		return transaction, err
+	}
+
+	if transaction.ID == 0 {
+		return transaction, errors.New("No transaction found with that ID")
+	}
+	return transaction, nil
+}
+
+// GetFeeReport userID 0 berarti laporan semua campaign (admin)
+func (s *service) GetFeeReport(userID int) ([]FeeReport, error) {
+	reports, err := s.repository.GetFeeReport(userID)
+	if err != nil {
+		return reports, err
+	}
+	return reports, nil
+}
+
+func (s *service) GetRefunds(transactionID int) ([]Refund, error) {
+	refunds, err := s.repository.GetRefundsByTransactionID(transactionID)
+	if err != nil {
+		return refunds, err
+	}
+	return refunds, nil
+}
+
+// RefundTransaction mengembalikan dana transaksi (penuh atau sebagian) lewat payment provider.
+// Status transaksi dan total campaign diubah dulu (dicadangkan) supaya refund bersamaan tidak melebihi amount,
+// lalu refund dikirim ke provider. Jika provider gagal, perubahan tersebut dikembalikan lagi
+func (s *service) RefundTransaction(inputID GetTransactionDetailInput, input RefundTransactionInput) (Refund, error) {
+	refund := Refund{}
+
+	transaction, err := s.GetTransactionByID(inputID.ID)
+	if err != nil {
+		return refund, err
+	}
+
+	if input.RequestedBy == RefundRequestedByAdmin && input.User.ID == 0 {
+		return refund, errors.New("Admin user is required to refund a transaction")
+	}
+
+	if input.RequestedBy != RefundRequestedByAdmin {
+		campaign, err := s.campaignRepository.FindByID(transaction.CampaignID)
+		if err != nil {
+			return refund, err
+		}
+
+		if campaign.UserID != input.User.ID {
+			return refund, errors.New("Not an owner of the campaign")
+		}
+		input.RequestedBy = RefundRequestedByCreator
+	}
+
+	refundableAmount := transaction.RefundableAmount()
+	if refundableAmount == 0 {
+		return refund, errors.New("Only paid transactions can be refunded")
+	}
+
+	amount := input.Amount
+	if amount == 0 {
+		amount = refundableAmount
+	}
+
+	if amount < 0 || amount > refundableAmount {
+		return refund, errors.New("Refund amount exceeds the refundable amount")
+	}
+
+	previousStatus := transaction.Status
+	previousRefundedAmount := transaction.RefundedAmount
+	newRefundedAmount := previousRefundedAmount + amount
+
+	newStatus := StatusPartiallyRefunded
+	backerCount := 0
+	if newRefundedAmount == transaction.Amount {
+		newStatus = StatusRefunded
+		backerCount = -transaction.backerCount()
+	}
+
+	// amount refund dalam mata uang transaksi, total campaign dan ledger dalam mata uang campaign
+	campaignAmount := transaction.CampaignAmountFor(newRefundedAmount) - transaction.CampaignAmountFor(previousRefundedAmount)
+
+	refund.TransactionID = transaction.ID
+	refund.Amount = amount
+	refund.Reason = input.Reason
+	refund.Status = RefundStatusPending
+	refund.RequestedBy = input.RequestedBy
+	refund.RequestedByUserID = input.User.ID
+
+	err = s.unitOfWork.Do(func(repository Repository, campaignRepository campaign.Repository, ledgerService ledger.Service) error {
+		isApplied, err := repository.ApplyRefund(transaction.ID, previousStatus, previousRefundedAmount, newStatus, newRefundedAmount)
+		if err != nil {
+			return err
+		}
+		if !isApplied {
+			return errors.New("Transaction has been changed, please try again")
+		}
+
+		err = campaignRepository.AddFunding(transaction.CampaignID, -campaignAmount, backerCount)
+		if err != nil {
+			return err
+		}
+
+		refund, err = repository.SaveRefund(refund)
+		if err != nil {
+			return err
+		}
+
+		return ledgerService.PostRefund("refund:"+strconv.Itoa(refund.ID), transaction.ID, transaction.CampaignID, transaction.UserID, campaignAmount)
+	})
+	if err != nil {
+		return refund, err
+	}
+
+	providerReference, refundErr := s.paymentService.Refund(transaction.PaymentProvider, transaction.Code, amount, input.Reason)
+	if refundErr != nil {
+		refund.Status = RefundStatusFailed
+		refund.FailureReason = refundErr.Error()
+
+		err = s.unitOfWork.Do(func(repository Repository, campaignRepository campaign.Repository, ledgerService ledger.Service) error {
+			isReverted, err := repository.ApplyRefund(transaction.ID, newStatus, newRefundedAmount, previousStatus, previousRefundedAmount)
+			if err != nil {
+				return err
+			}
+
+			// transaksi sudah diubah proses lain (misalnya notifikasi refund dari provider), total campaign tidak dikembalikan
+			if isReverted {
+				err = campaignRepository.AddFunding(transaction.CampaignID, campaignAmount, -backerCount)
+				if err != nil {
+					return err
+				}
+
+				err = ledgerService.PostRefundReversal("refund_reversal:"+strconv.Itoa(refund.ID), transaction.ID, transaction.CampaignID, transaction.UserID, campaignAmount)
+				if err != nil {
+					return err
+				}
+			}
+
+			refund, err = repository.UpdateRefund(refund)
+			return err
+		})
+		if err != nil {
+			return refund, err
+		}
+		return refund, refundErr
+	}
+
+	refund.Status = RefundStatusSucceeded
+	refund.ProviderReference = providerReference
+
+	refund, err = s.repository.UpdateRefund(refund)
+	if err != nil {
+		return refund, err
+	}
+	return refund, nil
+}
@@ -0,0 +1,28 @@
+package transaction
+
+import (
+	"bwastartup/api/campaign"
+	"bwastartup/api/ledger"
+
+	"gorm.io/gorm"
+)
+
+// UnitOfWork menjalankan beberapa operasi repository transaksi, campaign dan ledger dalam satu
+// transaksi database. Jika fn mengembalikan error semua perubahan dibatalkan (rollback)
+type UnitOfWork interface {
+	Do(fn func(repository Repository, campaignRepository campaign.Repository, ledgerService ledger.Service) error) error
+}
+
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) *unitOfWork {
+	return &unitOfWork{db}
+}
+
+func (u *unitOfWork) Do(fn func(repository Repository, campaignRepository campaign.Repository, ledgerService ledger.Service) error) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		return fn(NewRepository(tx), campaign.NewRepository(tx), ledger.NewService(ledger.NewRepository(tx)))
+	})
+}
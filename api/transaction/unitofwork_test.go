@@ -0,0 +1,227 @@
+package transaction
+
+import (
+	"bwastartup/api/campaign"
+	"bwastartup/api/category"
+	"bwastartup/api/fee"
+	"bwastartup/api/ledger"
+	"bwastartup/api/money"
+	"bwastartup/api/payment"
+	"bwastartup/api/user"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const testMockSecret = "unitofwork-test-secret"
+
+// newTestDB database sqlite di file sementara. _txlock=immediate membuat transaksi langsung mengambil lock tulis
+// sehingga transaksi yang bersamaan saling menunggu (busy timeout) seperti row lock di MySQL, bukan gagal deadlock
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := "file:" + filepath.Join(t.TempDir(), "bwastartup.db") + "?_busy_timeout=10000&_txlock=immediate&_journal_mode=WAL"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger:                                   logger.Default.LogMode(logger.Silent),
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+
+	err = db.AutoMigrate(&user.User{}, &category.Category{}, &campaign.Campaign{}, &campaign.CampaignImage{}, &campaign.CampaignTag{},
+		&Transaction{}, &PaymentNotification{}, &ledger.Account{}, &ledger.JournalEntry{}, &ledger.JournalLine{})
+	if err != nil {
+		t.Fatalf("migrate database: %v", err)
+	}
+
+	// unique key yang dipakai untuk idempotensi, sama dengan folder migrations
+	for _, statement := range []string{
+		"CREATE UNIQUE INDEX transactions_code_unique ON transactions (code)",
+		"CREATE UNIQUE INDEX payment_notifications_event_key_unique ON payment_notifications (event_key)",
+		"CREATE UNIQUE INDEX ledger_accounts_code_unique ON ledger_accounts (code)",
+		"CREATE UNIQUE INDEX journal_entries_reference_unique ON journal_entries (reference)",
+	} {
+		err = db.Exec(statement).Error
+		if err != nil {
+			t.Fatalf("create index: %v", err)
+		}
+	}
+	return db
+}
+
+func createTestCampaign(t *testing.T, db *gorm.DB) campaign.Campaign {
+	t.Helper()
+
+	testCampaign := campaign.Campaign{UserID: 1, Name: "Concurrent Campaign", Slug: "concurrent-campaign", GoalAmount: 1000000000, Currency: money.DefaultCurrency}
+	err := db.Create(&testCampaign).Error
+	if err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+	return testCampaign
+}
+
+func signedMockWebhook(t *testing.T, eventID string, orderID string, status string, amount int) TransactionNotificationInput {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]interface{}{"event_id": eventID, "order_id": orderID, "status": status, "amount": amount})
+	if err != nil {
+		t.Fatalf("marshal webhook: %v", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(testMockSecret))
+	mac.Write(body)
+
+	header := http.Header{}
+	header.Set(payment.MockSignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+	return TransactionNotificationInput{Provider: payment.ProviderMock, Header: header, Body: body}
+}
+
+// TestProcessPaymentConcurrentNotifications setiap transaksi menerima webhook paid dua kali secara bersamaan
+// (retry provider) sementara transaksi lain di campaign yang sama juga dibayar. Total campaign harus tepat
+// satu kali per transaksi dan sama dengan dana di ledger
+func TestProcessPaymentConcurrentNotifications(t *testing.T) {
+	db := newTestDB(t)
+	testCampaign := createTestCampaign(t, db)
+
+	paymentService, err := payment.NewService([]payment.Provider{payment.NewMockProvider(testMockSecret, "http://localhost", "")}, payment.ProviderMock)
+	if err != nil {
+		t.Fatalf("payment service: %v", err)
+	}
+
+	service := NewService(NewRepository(db), campaign.NewRepository(db), paymentService, NewUnitOfWork(db), NewCodeGenerator("TEST"),
+		fee.NewService(fee.Policy{BasisPoints: 500}), money.NewFileRateProvider(""))
+
+	const transactionCount = 20
+	const deliveries = 2
+	const amount = 50000
+
+	transactions := []Transaction{}
+	for i := 1; i <= transactionCount; i++ {
+		newTransaction, err := service.CreateTransaction(CreateTransactionInput{Amount: amount, CampaignID: testCampaign.ID, User: user.User{ID: i + 1}})
+		if err != nil {
+			t.Fatalf("create transaction: %v", err)
+		}
+		transactions = append(transactions, newTransaction)
+	}
+
+	start := make(chan struct{})
+	errs := make(chan error, transactionCount*deliveries)
+	var wg sync.WaitGroup
+
+	for _, pendingTransaction := range transactions {
+		input := signedMockWebhook(t, pendingTransaction.Code+"-paid", pendingTransaction.Code, payment.StatusPaid, amount)
+		for i := 0; i < deliveries; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				errs <- service.ProcessPayment(input)
+			}()
+		}
+	}
+
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("process payment: %v", err)
+		}
+	}
+
+	var fundedCampaign campaign.Campaign
+	err = db.First(&fundedCampaign, testCampaign.ID).Error
+	if err != nil {
+		t.Fatalf("load campaign: %v", err)
+	}
+
+	if fundedCampaign.CurrentAmount != transactionCount*amount {
+		t.Errorf("current_amount = %d, want %d", fundedCampaign.CurrentAmount, transactionCount*amount)
+	}
+	if fundedCampaign.BackerCount != transactionCount {
+		t.Errorf("backer_count = %d, want %d", fundedCampaign.BackerCount, transactionCount)
+	}
+
+	var paidCount int64
+	err = db.Model(&Transaction{}).Where("campaign_id = ? AND status = ?", testCampaign.ID, StatusPaid).Count(&paidCount).Error
+	if err != nil {
+		t.Fatalf("count paid transactions: %v", err)
+	}
+	if paidCount != transactionCount {
+		t.Errorf("paid transactions = %d, want %d", paidCount, transactionCount)
+	}
+
+	balance, err := ledger.NewService(ledger.NewRepository(db)).CheckCampaignBalance(testCampaign.ID, fundedCampaign.CurrentAmount)
+	if err != nil {
+		t.Fatalf("check ledger balance: %v", err)
+	}
+	if !balance.IsBalanced() {
+		t.Errorf("ledger amount = %d, campaign current_amount = %d", balance.LedgerAmount, balance.CurrentAmount)
+	}
+}
+
+// TestAddFundingConcurrentUpdates AddFunding langsung dan lewat UnitOfWork secara bersamaan tidak boleh saling menimpa
+func TestAddFundingConcurrentUpdates(t *testing.T) {
+	db := newTestDB(t)
+	testCampaign := createTestCampaign(t, db)
+
+	campaignRepository := campaign.NewRepository(db)
+	unitOfWork := NewUnitOfWork(db)
+
+	const workers = 50
+	const amount = 10000
+
+	start := make(chan struct{})
+	errs := make(chan error, workers*2)
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			<-start
+			errs <- campaignRepository.AddFunding(testCampaign.ID, amount, 1)
+		}()
+		go func() {
+			defer wg.Done()
+			<-start
+			errs <- unitOfWork.Do(func(repository Repository, campaignRepository campaign.Repository, ledgerService ledger.Service) error {
+				return campaignRepository.AddFunding(testCampaign.ID, amount, 1)
+			})
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("add funding: %v", err)
+		}
+	}
+
+	var fundedCampaign campaign.Campaign
+	err := db.First(&fundedCampaign, testCampaign.ID).Error
+	if err != nil {
+		t.Fatalf("load campaign: %v", err)
+	}
+
+	if fundedCampaign.CurrentAmount != workers*2*amount {
+		t.Errorf("current_amount = %d, want %d", fundedCampaign.CurrentAmount, workers*2*amount)
+	}
+	if fundedCampaign.BackerCount != workers*2 {
+		t.Errorf("backer_count = %d, want %d", fundedCampaign.BackerCount, workers*2)
+	}
+}
@@ -0,0 +1,41 @@
+package upload
+
+import "fmt"
+
+const (
+	ErrCodeTooLarge        = "file_too_large"
+	ErrCodeUnsupportedType = "unsupported_file_type"
+	ErrCodeInvalidImage    = "invalid_image"
+	ErrCodeEmptyFile       = "empty_file"
+)
+
+// ValidationError kesalahan file upload dari sisi user, Code bisa dipakai client
+// untuk menampilkan pesan yang sesuai
+type ValidationError struct {
+	Code    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func errTooLarge(maxSize int64) *ValidationError {
+	return &ValidationError{ErrCodeTooLarge, fmt.Sprintf("File is larger than the maximum size of %d bytes", maxSize)}
+}
+
+func errUnsupportedType() *ValidationError {
+	return &ValidationError{ErrCodeUnsupportedType, "Only JPEG, PNG and WebP images are allowed"}
+}
+
+func errTooManyPixels() *ValidationError {
+	return &ValidationError{ErrCodeTooLarge, fmt.Sprintf("Image resolution is larger than %d pixels", maxPixels)}
+}
+
+func errInvalidImage() *ValidationError {
+	return &ValidationError{ErrCodeInvalidImage, "File is not a valid image"}
+}
+
+func errEmptyFile() *ValidationError {
+	return &ValidationError{ErrCodeEmptyFile, "File is empty"}
+}
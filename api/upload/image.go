@@ -0,0 +1,65 @@
+package upload
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime/multipart"
+	"path"
+)
+
+// Image hasil validasi file upload yang sudah bersih dari metadata
+type Image struct {
+	Data        []byte
+	ContentType string
+	Checksum    string
+}
+
+// Key nama file di storage, diambil dari hash isi file sehingga nama asli dari user
+// tidak pernah dipakai
+func (i Image) Key(folder string) string {
+	return path.Join(folder, i.Checksum+extensions[i.ContentType])
+}
+
+// Process membaca file upload dan menjalankan semua validasi. Ukuran dicek lagi saat membaca
+// karena file.Size berasal dari request
+func Process(file *multipart.FileHeader, maxFileSize int64) (Image, error) {
+	if file.Size > maxFileSize {
+		return Image{}, errTooLarge(maxFileSize)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return Image{}, err
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, maxFileSize+1))
+	if err != nil {
+		return Image{}, err
+	}
+	if int64(len(data)) > maxFileSize {
+		return Image{}, errTooLarge(maxFileSize)
+	}
+	if len(data) == 0 {
+		return Image{}, errEmptyFile()
+	}
+
+	contentType := sniffType(data)
+	if contentType == "" {
+		return Image{}, errUnsupportedType()
+	}
+
+	data, err = stripMetadata(contentType, data)
+	if err != nil {
+		return Image{}, err
+	}
+
+	sum := sha256.Sum256(data)
+
+	image := Image{}
+	image.Data = data
+	image.ContentType = contentType
+	image.Checksum = hex.EncodeToString(sum[:])
+	return image, nil
+}
@@ -0,0 +1,159 @@
+package upload
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// stripMetadata menghapus metadata (EXIF, XMP, IPTC, komentar) yang bisa berisi lokasi GPS
+// atau data kamera. Data gambar sendiri tidak diubah
+func stripMetadata(contentType string, data []byte) ([]byte, error) {
+	switch contentType {
+	case TypeJPEG:
+		return stripJPEG(data)
+	case TypePNG:
+		return stripPNG(data)
+	case TypeWebP:
+		return stripWebP(data)
+	}
+	return nil, errUnsupportedType()
+}
+
+// stripJPEG membuang segment APP1 (EXIF/XMP), APP13 (IPTC) dan COM sebelum data scan (SOS).
+// APP0 (JFIF), APP2 (ICC profile) dan APP14 (Adobe) tetap disimpan karena dibutuhkan decoder.
+// Dari EXIF hanya tag Orientation yang disimpan, supaya foto portrait dari kamera tidak tampil miring
+func stripJPEG(data []byte) ([]byte, error) {
+	var out bytes.Buffer
+	out.Write(data[:2])
+
+	hasOrientation := false
+
+	i := 2
+	for {
+		if i+4 > len(data) || data[i] != 0xff {
+			return nil, errInvalidImage()
+		}
+
+		marker := data[i+1]
+		if marker == 0xff {
+			// byte pengisi
+			i++
+			continue
+		}
+
+		if marker == 0xd8 || marker == 0x01 || (marker >= 0xd0 && marker <= 0xd7) {
+			out.Write(data[i : i+2])
+			i += 2
+			continue
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			return nil, errInvalidImage()
+		}
+
+		if marker == 0xda {
+			// mulai data scan, sisa file disalin apa adanya
+			out.Write(data[i:])
+			return out.Bytes(), nil
+		}
+
+		isMetadata := marker == 0xe1 || marker == 0xed || marker == 0xfe
+		if !isMetadata {
+			out.Write(data[i:end])
+		}
+
+		if marker == 0xe1 && !hasOrientation {
+			orientation := exifOrientation(data[i+4 : end])
+			if orientation != 1 {
+				out.Write(orientationSegment(orientation))
+				hasOrientation = true
+			}
+		}
+		i = end
+	}
+}
+
+var pngMetadataChunks = map[string]bool{
+	"eXIf": true,
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"tIME": true,
+}
+
+func stripPNG(data []byte) ([]byte, error) {
+	var out bytes.Buffer
+	out.Write(pngSignature)
+
+	i := len(pngSignature)
+	for i < len(data) {
+		if i+8 > len(data) {
+			return nil, errInvalidImage()
+		}
+
+		length := int(binary.BigEndian.Uint32(data[i : i+4]))
+		chunkType := string(data[i+4 : i+8])
+		end := i + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, errInvalidImage()
+		}
+
+		if !pngMetadataChunks[chunkType] {
+			out.Write(data[i:end])
+		}
+		i = end
+
+		if chunkType == "IEND" {
+			break
+		}
+	}
+	return out.Bytes(), nil
+}
+
+const (
+	webpFlagXMP  = 0x04
+	webpFlagEXIF = 0x08
+)
+
+// stripWebP membuang chunk EXIF dan XMP lalu menyesuaikan flag VP8X dan ukuran RIFF
+func stripWebP(data []byte) ([]byte, error) {
+	var chunks bytes.Buffer
+
+	i := 12
+	for i < len(data) {
+		if i+8 > len(data) {
+			return nil, errInvalidImage()
+		}
+
+		chunkType := string(data[i : i+4])
+		length := int(binary.LittleEndian.Uint32(data[i+4 : i+8]))
+		end := i + 8 + length + length%2
+		if end > len(data) {
+			if i+8+length != len(data) {
+				return nil, errInvalidImage()
+			}
+			// padding chunk terakhir kadang tidak ditulis encoder
+			end = len(data)
+		}
+
+		switch chunkType {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			chunk := append([]byte{}, data[i:end]...)
+			if len(chunk) > 8 {
+				chunk[8] &^= webpFlagEXIF | webpFlagXMP
+			}
+			chunks.Write(chunk)
+		default:
+			chunks.Write(data[i:end])
+		}
+		i = end
+	}
+
+	out := make([]byte, 12, 12+chunks.Len())
+	copy(out, data[:12])
+	binary.LittleEndian.PutUint32(out[4:8], uint32(4+chunks.Len()))
+	return append(out, chunks.Bytes()...), nil
+}
@@ -0,0 +1,143 @@
+package upload
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+)
+
+// exifHeader awal isi segment APP1 yang berisi EXIF (bukan XMP)
+var exifHeader = []byte("Exif\x00\x00")
+
+const exifTagOrientation = 0x0112
+
+// exifOrientation membaca tag Orientation (1-8) dari isi segment APP1 EXIF.
+// Mengembalikan 1 (tidak perlu diputar) jika tag tidak ada atau tidak valid
+func exifOrientation(segment []byte) int {
+	if !bytes.HasPrefix(segment, exifHeader) {
+		return 1
+	}
+
+	tiff := segment[len(exifHeader):]
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 1
+	}
+
+	count := int(order.Uint16(tiff[offset : offset+2]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+
+		// tipe 3 = SHORT, nilainya ada di 2 byte pertama field value
+		if order.Uint16(tiff[entry:entry+2]) == exifTagOrientation && order.Uint16(tiff[entry+2:entry+4]) == 3 {
+			orientation := int(order.Uint16(tiff[entry+8 : entry+10]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+	return 1
+}
+
+// orientationSegment segment APP1 EXIF minimal yang hanya berisi tag Orientation
+func orientationSegment(orientation int) []byte {
+	tiff := []byte{
+		'M', 'M', 0x00, 0x2a, 0x00, 0x00, 0x00, 0x08, // header TIFF big endian, IFD0 di offset 8
+		0x00, 0x01, // jumlah tag
+		0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x00, byte(orientation), 0x00, 0x00, // Orientation SHORT
+		0x00, 0x00, 0x00, 0x00, // tidak ada IFD berikutnya
+	}
+
+	segment := []byte{0xff, 0xe1, 0x00, 0x00}
+	binary.BigEndian.PutUint16(segment[2:4], uint16(2+len(exifHeader)+len(tiff)))
+	segment = append(segment, exifHeader...)
+	return append(segment, tiff...)
+}
+
+// jpegOrientation tag Orientation dari segment APP1 EXIF pertama sebelum data scan
+func jpegOrientation(data []byte) int {
+	i := 2
+	for i+4 <= len(data) && data[i] == 0xff {
+		marker := data[i+1]
+		if marker == 0xff {
+			i++
+			continue
+		}
+		if marker == 0xd8 || marker == 0x01 || (marker >= 0xd0 && marker <= 0xd7) {
+			i += 2
+			continue
+		}
+		if marker == 0xda {
+			break
+		}
+
+		end := i + 2 + int(binary.BigEndian.Uint16(data[i+2:i+4]))
+		if end > len(data) {
+			break
+		}
+		if marker == 0xe1 && bytes.HasPrefix(data[i+4:end], exifHeader) {
+			return exifOrientation(data[i+4 : end])
+		}
+		i = end
+	}
+	return 1
+}
+
+// orient memutar dan/atau membalik pixel sesuai tag Orientation EXIF sehingga gambar tampil tegak
+// tanpa perlu membaca EXIF lagi. Orientation 5-8 menukar lebar dan tinggi
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+
+	width, height := src.Rect.Dx(), src.Rect.Dy()
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		for x := 0; x < dstWidth; x++ {
+			// posisi pixel sumber untuk pixel hasil (x, y)
+			sx, sy := x, y
+			switch orientation {
+			case 2:
+				sx = width - 1 - x
+			case 3:
+				sx, sy = width-1-x, height-1-y
+			case 4:
+				sy = height - 1 - y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, height-1-x
+			case 7:
+				sx, sy = width-1-y, height-1-x
+			case 8:
+				sx, sy = width-1-y, x
+			}
+
+			copy(dst.Pix[y*dst.Stride+x*4:y*dst.Stride+x*4+4], src.Pix[sy*src.Stride+sx*4:sy*src.Stride+sx*4+4])
+		}
+	}
+	return dst
+}
@@ -0,0 +1,126 @@
+package upload
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// exifSegment segment APP1 EXIF little endian dengan tag Make dan Orientation
+func exifSegment(orientation int) []byte {
+	tiff := []byte{'I', 'I', 0x2a, 0x00, 0x08, 0x00, 0x00, 0x00, 0x02, 0x00}
+	tiff = append(tiff, 0x0f, 0x01, 0x02, 0x00, 0x04, 0x00, 0x00, 0x00, 'C', 'a', 'm', 0x00)
+	tiff = append(tiff, 0x12, 0x01, 0x03, 0x00, 0x01, 0x00, 0x00, 0x00, byte(orientation), 0x00, 0x00, 0x00)
+	tiff = append(tiff, 0x00, 0x00, 0x00, 0x00)
+
+	segment := []byte{0xff, 0xe1, 0x00, 0x00}
+	binary.BigEndian.PutUint16(segment[2:4], uint16(2+len(exifHeader)+len(tiff)))
+	segment = append(segment, exifHeader...)
+	return append(segment, tiff...)
+}
+
+// landscapeJPEG gambar 60x30 dengan setengah kiri merah dan setengah kanan biru
+func landscapeJPEG(t *testing.T, orientation int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, 60, 30))
+	for y := 0; y < 30; y++ {
+		for x := 0; x < 60; x++ {
+			img.Set(x, y, color.RGBA{R: 0xff, A: 0xff})
+			if x >= 30 {
+				img.Set(x, y, color.RGBA{B: 0xff, A: 0xff})
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95})
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	data := buf.Bytes()
+	return append(append(append([]byte{}, data[:2]...), exifSegment(orientation)...), data[2:]...)
+}
+
+func TestStripJPEGKeepsOrientation(t *testing.T) {
+	data := landscapeJPEG(t, 6)
+	if jpegOrientation(data) != 6 {
+		t.Fatalf("jpegOrientation before strip = %d, want 6", jpegOrientation(data))
+	}
+
+	stripped, err := stripMetadata(TypeJPEG, data)
+	if err != nil {
+		t.Fatalf("stripMetadata: %v", err)
+	}
+	if jpegOrientation(stripped) != 6 {
+		t.Errorf("jpegOrientation after strip = %d, want 6", jpegOrientation(stripped))
+	}
+	if bytes.Contains(stripped, []byte("Cam\x00")) {
+		t.Error("EXIF tags other than Orientation were not removed")
+	}
+
+	// tanpa rotasi tidak ada EXIF yang tersisa sama sekali
+	stripped, err = stripMetadata(TypeJPEG, landscapeJPEG(t, 1))
+	if err != nil {
+		t.Fatalf("stripMetadata: %v", err)
+	}
+	if bytes.Contains(stripped, exifHeader) {
+		t.Error("EXIF segment was kept for orientation 1")
+	}
+}
+
+func TestMakeVariantsAppliesOrientation(t *testing.T) {
+	stripped, err := stripMetadata(TypeJPEG, landscapeJPEG(t, 6))
+	if err != nil {
+		t.Fatalf("stripMetadata: %v", err)
+	}
+
+	key := "images/campaigns/1/photo.jpg"
+	variants, err := makeVariants(Image{Data: stripped, ContentType: TypeJPEG}, key)
+	if err != nil {
+		t.Fatalf("makeVariants: %v", err)
+	}
+
+	thumbnail, err := jpeg.Decode(bytes.NewReader(variants[VariantKey(key, "thumbnail")].Data))
+	if err != nil {
+		t.Fatalf("decode thumbnail: %v", err)
+	}
+	if thumbnail.Bounds().Dx() != 30 || thumbnail.Bounds().Dy() != 60 {
+		t.Fatalf("thumbnail size = %v, want 30x60", thumbnail.Bounds().Size())
+	}
+
+	// diputar 90 derajat searah jarum jam: setengah kiri (merah) menjadi setengah atas
+	top := color.RGBAModel.Convert(thumbnail.At(15, 10)).(color.RGBA)
+	bottom := color.RGBAModel.Convert(thumbnail.At(15, 50)).(color.RGBA)
+	if top.R < 0xc0 || top.B > 0x40 || bottom.B < 0xc0 || bottom.R > 0x40 {
+		t.Errorf("top pixel = %v, bottom pixel = %v, want red on top and blue at the bottom", top, bottom)
+	}
+}
+
+func TestOrient(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	for i := range src.Pix {
+		src.Pix[i] = uint8(i)
+	}
+
+	// setiap orientation dibalik oleh orientation pasangannya
+	inverses := map[int]int{1: 1, 2: 2, 3: 3, 4: 4, 5: 5, 6: 8, 7: 7, 8: 6}
+	for orientation, inverse := range inverses {
+		oriented := orient(src, orientation)
+		if orientation >= 5 && (oriented.Rect.Dx() != 2 || oriented.Rect.Dy() != 3) {
+			t.Errorf("orientation %d size = %v, want 2x3", orientation, oriented.Rect.Size())
+		}
+		if !bytes.Equal(orient(oriented, inverse).Pix, src.Pix) {
+			t.Errorf("orientation %d followed by %d does not restore the image", orientation, inverse)
+		}
+	}
+
+	// orientation 6: pojok kiri atas hasil adalah pojok kiri bawah sumber
+	if got := orient(src, 6).RGBAAt(0, 0); got != src.RGBAAt(0, 1) {
+		t.Errorf("orientation 6 top left = %v, want %v", got, src.RGBAAt(0, 1))
+	}
+}
@@ -0,0 +1,89 @@
+package upload
+
+import (
+	"bwastartup/api/storage"
+	"bytes"
+	"mime/multipart"
+	"os"
+	"strconv"
+)
+
+const DefaultMaxFileSize int64 = 5 << 20
+
+// Service pipeline upload gambar yang dipakai semua endpoint upload (avatar dan gambar campaign):
+// validasi ukuran dan tipe, hapus metadata, buat variant ukuran kecil, lalu simpan ke storage
+// dengan nama dari hash isi file
+type Service interface {
+	SaveImage(file *multipart.FileHeader, folder string) (string, error)
+	Delete(key string) error
+}
+
+type service struct {
+	storage     storage.Storage
+	maxFileSize int64
+}
+
+func NewService(storage storage.Storage, maxFileSize int64) *service {
+	if maxFileSize <= 0 {
+		maxFileSize = DefaultMaxFileSize
+	}
+	return &service{storage, maxFileSize}
+}
+
+// MaxFileSizeFromEnv membaca UPLOAD_MAX_FILE_SIZE (dalam byte)
+func MaxFileSizeFromEnv() int64 {
+	maxFileSize, err := strconv.ParseInt(os.Getenv("UPLOAD_MAX_FILE_SIZE"), 10, 64)
+	if err != nil || maxFileSize <= 0 {
+		return DefaultMaxFileSize
+	}
+	return maxFileSize
+}
+
+// SaveImage memproses file lalu menyimpannya di folder, misalnya "images/avatars/3".
+// Yang dikembalikan adalah key file di storage untuk disimpan ke database
+func (s *service) SaveImage(file *multipart.FileHeader, folder string) (string, error) {
+	image, err := Process(file, s.maxFileSize)
+	if err != nil {
+		return "", err
+	}
+
+	key := image.Key(folder)
+
+	// variant dibuat sebelum menyimpan apa pun, sekaligus memastikan file bisa di-decode
+	variants, err := makeVariants(image, key)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.storage.Put(key, bytes.NewReader(image.Data), image.ContentType)
+	if err != nil {
+		return "", err
+	}
+
+	for variantKey, variant := range variants {
+		err = s.storage.Put(variantKey, bytes.NewReader(variant.Data), variant.ContentType)
+		if err != nil {
+			return "", err
+		}
+	}
+	return key, nil
+}
+
+// Delete menghapus file asli beserta variant dan versi WebP-nya
+func (s *service) Delete(key string) error {
+	err := s.storage.Delete(key)
+	if err != nil {
+		return err
+	}
+
+	if !hasVariants(key) {
+		return nil
+	}
+	for _, variantKey := range variantKeys(key) {
+		err = s.storage.Delete(variantKey)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
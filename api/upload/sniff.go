@@ -0,0 +1,30 @@
+package upload
+
+import "bytes"
+
+const (
+	TypeJPEG = "image/jpeg"
+	TypePNG  = "image/png"
+	TypeWebP = "image/webp"
+)
+
+var extensions = map[string]string{
+	TypeJPEG: ".jpg",
+	TypePNG:  ".png",
+	TypeWebP: ".webp",
+}
+
+var pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+
+// sniffType menentukan tipe file dari magic bytes, bukan dari nama file atau header Content-Type
+func sniffType(data []byte) string {
+	switch {
+	case len(data) >= 3 && data[0] == 0xff && data[1] == 0xd8 && data[2] == 0xff:
+		return TypeJPEG
+	case bytes.HasPrefix(data, pngSignature):
+		return TypePNG
+	case len(data) >= 12 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return TypeWebP
+	}
+	return ""
+}
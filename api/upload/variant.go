@@ -0,0 +1,272 @@
+package upload
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"path"
+	"strings"
+
+	"golang.org/x/image/webp"
+)
+
+// Variant ukuran gambar yang dibuat saat upload, MaxSize adalah sisi terpanjang dalam pixel
+type Variant struct {
+	Name    string
+	MaxSize int
+}
+
+var Variants = []Variant{
+	{"thumbnail", 320},
+	{"medium", 800},
+	{"large", 1600},
+}
+
+// maxPixels batas resolusi gambar yang mau di-decode, supaya file kecil dengan resolusi
+// sangat besar (decompression bomb) tidak menghabiskan memory
+const maxPixels = 40000000
+
+const jpegQuality = 85
+
+// VariantKey key file variant dari key gambar asli,
+// misalnya "images/campaigns/1/abc.jpg" menjadi "images/campaigns/1/abc_thumbnail.jpg"
+func VariantKey(key string, name string) string {
+	ext := path.Ext(key)
+	return strings.TrimSuffix(key, ext) + "_" + name + ext
+}
+
+// WebPKey key versi WebP dari setiap ukuran gambar, name "original" untuk ukuran asli,
+// misalnya "images/campaigns/1/abc.jpg" menjadi "images/campaigns/1/abc_thumbnail.webp".
+// Untuk upload WebP hasilnya sama dengan key asli dan VariantKey
+func WebPKey(key string, name string) string {
+	base := strings.TrimSuffix(key, path.Ext(key))
+	if name == "original" {
+		return base + extensions[TypeWebP]
+	}
+	return base + "_" + name + extensions[TypeWebP]
+}
+
+// ImageURLs daftar file untuk setiap ukuran gambar, versi WebP-nya dengan nama "<ukuran>_webp".
+// Gambar lama (sebelum ada variant) tidak punya variant, jadi semua ukuran diarahkan ke file aslinya
+func ImageURLs(key string) map[string]string {
+	imageURLs := map[string]string{}
+	if key == "" {
+		return imageURLs
+	}
+
+	imageURLs["original"] = key
+	if !hasVariants(key) {
+		for _, variant := range Variants {
+			imageURLs[variant.Name] = key
+		}
+		return imageURLs
+	}
+
+	imageURLs["original_webp"] = WebPKey(key, "original")
+	for _, variant := range Variants {
+		imageURLs[variant.Name] = VariantKey(key, variant.Name)
+		imageURLs[variant.Name+"_webp"] = WebPKey(key, variant.Name)
+	}
+	return imageURLs
+}
+
+// variantKeys key semua file turunan yang dibuat makeVariants untuk key gambar asli
+func variantKeys(key string) []string {
+	keys := []string{}
+	for _, variant := range Variants {
+		keys = append(keys, VariantKey(key, variant.Name))
+	}
+
+	if path.Ext(key) != extensions[TypeWebP] {
+		keys = append(keys, WebPKey(key, "original"))
+		for _, variant := range Variants {
+			keys = append(keys, WebPKey(key, variant.Name))
+		}
+	}
+	return keys
+}
+
+// hasVariants variant hanya dibuat untuk file yang disimpan lewat SaveImage (nama file = sha256)
+func hasVariants(key string) bool {
+	ext := path.Ext(key)
+	if ext != extensions[TypeJPEG] && ext != extensions[TypePNG] && ext != extensions[TypeWebP] {
+		return false
+	}
+
+	name := strings.TrimSuffix(path.Base(key), ext)
+	if len(name) != 64 {
+		return false
+	}
+	for _, char := range name {
+		if !strings.ContainsRune("0123456789abcdef", char) {
+			return false
+		}
+	}
+	return true
+}
+
+// variantFile isi file turunan yang disimpan bersama file asli
+type variantFile struct {
+	Data        []byte
+	ContentType string
+}
+
+// makeVariants membuat semua ukuran variant dengan format yang sama dengan file asli, ditambah
+// versi WebP untuk ukuran asli dan setiap variant. Hasilnya map key storage (lihat variantKeys) ke isi file
+func makeVariants(file Image, key string) (map[string]variantFile, error) {
+	src, err := decode(file)
+	if err != nil {
+		return nil, err
+	}
+
+	isWebP := file.ContentType == TypeWebP
+	variants := map[string]variantFile{}
+
+	// file asli disimpan apa adanya, yang dibuat hanya versi WebP-nya
+	if !isWebP {
+		data, err := encodeWebP(src)
+		if err != nil {
+			return nil, err
+		}
+		variants[WebPKey(key, "original")] = variantFile{data, TypeWebP}
+	}
+
+	for _, variant := range Variants {
+		resized := resize(src, variant.MaxSize)
+
+		data, err := encode(resized, file.ContentType)
+		if err != nil {
+			return nil, err
+		}
+		variants[VariantKey(key, variant.Name)] = variantFile{data, file.ContentType}
+
+		if !isWebP {
+			data, err = encodeWebP(resized)
+			if err != nil {
+				return nil, err
+			}
+			variants[WebPKey(key, variant.Name)] = variantFile{data, TypeWebP}
+		}
+	}
+	return variants, nil
+}
+
+func encode(img *image.RGBA, contentType string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+
+	switch contentType {
+	case TypePNG:
+		err = png.Encode(&buf, img)
+	case TypeWebP:
+		return encodeWebP(img)
+	default:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decode(file Image) (*image.RGBA, error) {
+	reader := bytes.NewReader(file.Data)
+
+	decodeConfig, decodeImage := jpeg.DecodeConfig, jpeg.Decode
+	switch file.ContentType {
+	case TypePNG:
+		decodeConfig, decodeImage = png.DecodeConfig, png.Decode
+	case TypeWebP:
+		decodeConfig, decodeImage = webp.DecodeConfig, webp.Decode
+	}
+
+	config, err := decodeConfig(reader)
+	if err != nil || config.Width <= 0 || config.Height <= 0 {
+		return nil, errInvalidImage()
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, errTooManyPixels()
+	}
+
+	reader.Reset(file.Data)
+	img, err := decodeImage(reader)
+	if err != nil {
+		return nil, errInvalidImage()
+	}
+
+	// decoder JPEG tidak membaca EXIF, variant diputar di sini karena tidak menyimpan tag Orientation
+	if file.ContentType == TypeJPEG {
+		return orient(toRGBA(img), jpegOrientation(file.Data)), nil
+	}
+	return toRGBA(img), nil
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba
+}
+
+// resize mengecilkan gambar (tidak pernah memperbesar) dengan mengambil rata-rata
+// pixel sumber yang tertutup oleh setiap pixel hasil
+func resize(src *image.RGBA, maxSize int) *image.RGBA {
+	width, height := src.Rect.Dx(), src.Rect.Dy()
+	if width <= maxSize && height <= maxSize {
+		return src
+	}
+
+	newWidth, newHeight := maxSize, maxSize
+	if width >= height {
+		newHeight = height * maxSize / width
+	} else {
+		newWidth = width * maxSize / height
+	}
+	if newWidth < 1 {
+		newWidth = 1
+	}
+	if newHeight < 1 {
+		newHeight = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
+	for y := 0; y < newHeight; y++ {
+		y0, y1 := boxRange(y, height, newHeight)
+
+		for x := 0; x < newWidth; x++ {
+			x0, x1 := boxRange(x, width, newWidth)
+
+			var r, g, b, a, count int
+			for sy := y0; sy < y1; sy++ {
+				offset := sy*src.Stride + x0*4
+				for sx := x0; sx < x1; sx++ {
+					r += int(src.Pix[offset])
+					g += int(src.Pix[offset+1])
+					b += int(src.Pix[offset+2])
+					a += int(src.Pix[offset+3])
+					count++
+					offset += 4
+				}
+			}
+
+			i := y*dst.Stride + x*4
+			dst.Pix[i] = uint8(r / count)
+			dst.Pix[i+1] = uint8(g / count)
+			dst.Pix[i+2] = uint8(b / count)
+			dst.Pix[i+3] = uint8(a / count)
+		}
+	}
+	return dst
+}
+
+// boxRange rentang pixel sumber [start, end) untuk pixel hasil ke-i
+func boxRange(i int, srcSize int, dstSize int) (int, int) {
+	start := i * srcSize / dstSize
+	end := (i + 1) * srcSize / dstSize
+	if end <= start {
+		end = start + 1
+	}
+	return start, end
+}
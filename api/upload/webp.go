@@ -0,0 +1,19 @@
+package upload
+
+import (
+	"image"
+	"image/draw"
+
+	"github.com/chai2010/webp"
+)
+
+const webpQuality = 80
+
+// encodeWebP WebP lossy lewat libwebp (binding cgo). libwebp membaca pixel RGBA tanpa premultiplied alpha,
+// jadi pixel dikonversi ke NRGBA dulu supaya bagian transparan tidak menjadi gelap
+func encodeWebP(img *image.RGBA) ([]byte, error) {
+	nrgba := image.NewNRGBA(img.Rect)
+	draw.Draw(nrgba, nrgba.Rect, img, img.Rect.Min, draw.Src)
+
+	return webp.EncodeRGBA(&image.RGBA{Pix: nrgba.Pix, Stride: nrgba.Stride, Rect: nrgba.Rect}, webpQuality)
+}
@@ -0,0 +1,242 @@
+package upload
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"bwastartup/api/storage"
+
+	"golang.org/x/image/webp"
+)
+
+// testKey key gambar seperti hasil SaveImage (nama file = sha256)
+func testKey(ext string) string {
+	return "images/campaigns/1/" + strings.Repeat("a", 64) + ext
+}
+
+func testImage(width int, height int, opaque bool) *image.RGBA {
+	random := rand.New(rand.NewSource(int64(width*height + 1)))
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.NRGBA{
+				R: uint8(x*255/width + random.Intn(8)),
+				G: uint8(y*255/height + random.Intn(8)),
+				B: uint8((x + y) * 3),
+				A: 0xff,
+			}
+			if !opaque {
+				c.A = uint8(random.Intn(256))
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestEncodeWebP(t *testing.T) {
+	cases := map[string]*image.RGBA{
+		"single pixel":     testImage(1, 1, true),
+		"odd size":         testImage(67, 41, true),
+		"transparent":      testImage(45, 38, false),
+		"offset rectangle": testImage(80, 60, true).SubImage(image.Rect(13, 7, 70, 51)).(*image.RGBA),
+	}
+
+	for name, src := range cases {
+		t.Run(name, func(t *testing.T) {
+			data, err := encodeWebP(src)
+			if err != nil {
+				t.Fatalf("encodeWebP: %v", err)
+			}
+			if sniffType(data) != TypeWebP {
+				t.Fatalf("sniffType = %q, want %q", sniffType(data), TypeWebP)
+			}
+
+			decoded, err := webp.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if decoded.Bounds().Dx() != src.Bounds().Dx() || decoded.Bounds().Dy() != src.Bounds().Dy() {
+				t.Fatalf("size = %v, want %v", decoded.Bounds().Size(), src.Bounds().Size())
+			}
+		})
+	}
+}
+
+func TestEncodeWebPKeepsAlpha(t *testing.T) {
+	// setengah kiri transparan penuh, setengah kanan merah setengah transparan
+	src := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 32; x < 64; x++ {
+			src.Set(x, y, color.NRGBA{R: 0xff, A: 0x80})
+		}
+	}
+
+	data, err := encodeWebP(src)
+	if err != nil {
+		t.Fatalf("encodeWebP: %v", err)
+	}
+	decoded, err := webp.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	transparent := color.NRGBAModel.Convert(decoded.At(8, 32)).(color.NRGBA)
+	if transparent.A != 0 {
+		t.Errorf("transparent pixel = %v, want alpha 0", transparent)
+	}
+
+	// warna tidak boleh menjadi gelap karena alpha dikalikan dua kali
+	translucent := color.NRGBAModel.Convert(decoded.At(48, 32)).(color.NRGBA)
+	if translucent.A < 0x70 || translucent.A > 0x90 || translucent.R < 0xe0 {
+		t.Errorf("translucent pixel = %v, want about %v", translucent, color.NRGBA{R: 0xff, A: 0x80})
+	}
+}
+
+func TestMakeVariants(t *testing.T) {
+	src := testImage(1800, 900, true)
+
+	var jpegData, pngData bytes.Buffer
+	if err := jpeg.Encode(&jpegData, src, nil); err != nil {
+		t.Fatalf("encode jpeg: %v", err)
+	}
+	if err := png.Encode(&pngData, src); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	webpData, err := encodeWebP(src)
+	if err != nil {
+		t.Fatalf("encode webp: %v", err)
+	}
+
+	cases := []Image{
+		{Data: jpegData.Bytes(), ContentType: TypeJPEG},
+		{Data: pngData.Bytes(), ContentType: TypePNG},
+		{Data: webpData, ContentType: TypeWebP},
+	}
+
+	for _, file := range cases {
+		t.Run(file.ContentType, func(t *testing.T) {
+			key := testKey(extensions[file.ContentType])
+
+			variants, err := makeVariants(file, key)
+			if err != nil {
+				t.Fatalf("makeVariants: %v", err)
+			}
+			if len(variants) != len(variantKeys(key)) {
+				t.Errorf("got %d files, want %d", len(variants), len(variantKeys(key)))
+			}
+
+			if file.ContentType != TypeWebP {
+				original, ok := variants[WebPKey(key, "original")]
+				if !ok {
+					t.Fatal("WebP version of the original is missing")
+				}
+				config, err := webp.DecodeConfig(bytes.NewReader(original.Data))
+				if err != nil {
+					t.Fatalf("decode WebP original: %v", err)
+				}
+				if config.Width != 1800 || config.Height != 900 {
+					t.Errorf("WebP original size = %dx%d, want 1800x900", config.Width, config.Height)
+				}
+			}
+
+			for _, variant := range Variants {
+				resized, ok := variants[VariantKey(key, variant.Name)]
+				if !ok {
+					t.Fatalf("variant %s is missing", variant.Name)
+				}
+				if sniffType(resized.Data) != file.ContentType || resized.ContentType != file.ContentType {
+					t.Errorf("variant %s type = %q, want %q", variant.Name, sniffType(resized.Data), file.ContentType)
+				}
+
+				webpVariant, ok := variants[WebPKey(key, variant.Name)]
+				if !ok {
+					t.Fatalf("WebP variant %s is missing", variant.Name)
+				}
+				if sniffType(webpVariant.Data) != TypeWebP || webpVariant.ContentType != TypeWebP {
+					t.Errorf("WebP variant %s type = %q, want %q", variant.Name, sniffType(webpVariant.Data), TypeWebP)
+				}
+
+				config, err := webp.DecodeConfig(bytes.NewReader(webpVariant.Data))
+				if err != nil {
+					t.Fatalf("decode WebP variant %s: %v", variant.Name, err)
+				}
+				if config.Width != variant.MaxSize || config.Height != variant.MaxSize/2 {
+					t.Errorf("WebP variant %s size = %dx%d, want %dx%d", variant.Name, config.Width, config.Height, variant.MaxSize, variant.MaxSize/2)
+				}
+			}
+		})
+	}
+}
+
+func TestImageURLs(t *testing.T) {
+	key := testKey(".jpg")
+	base := strings.TrimSuffix(key, ".jpg")
+
+	imageURLs := ImageURLs(key)
+	expected := map[string]string{
+		"original":       key,
+		"original_webp":  base + ".webp",
+		"thumbnail":      base + "_thumbnail.jpg",
+		"thumbnail_webp": base + "_thumbnail.webp",
+		"medium":         base + "_medium.jpg",
+		"medium_webp":    base + "_medium.webp",
+		"large":          base + "_large.jpg",
+		"large_webp":     base + "_large.webp",
+	}
+	for name, want := range expected {
+		if imageURLs[name] != want {
+			t.Errorf("%s = %q, want %q", name, imageURLs[name], want)
+		}
+	}
+
+	// upload WebP: versi WebP adalah file itu sendiri
+	key = testKey(".webp")
+	imageURLs = ImageURLs(key)
+	if imageURLs["original_webp"] != key || imageURLs["thumbnail_webp"] != imageURLs["thumbnail"] {
+		t.Errorf("WebP upload URLs = %v", imageURLs)
+	}
+
+	// gambar lama tidak punya variant maupun versi WebP
+	imageURLs = ImageURLs("images/avatars/1/photo.jpg")
+	if imageURLs["thumbnail"] != "images/avatars/1/photo.jpg" {
+		t.Errorf("legacy thumbnail = %q", imageURLs["thumbnail"])
+	}
+	if _, ok := imageURLs["thumbnail_webp"]; ok {
+		t.Error("legacy image has a WebP URL")
+	}
+}
+
+func TestDeleteRemovesVariants(t *testing.T) {
+	fileStorage := storage.NewMemoryStorage("")
+	uploadService := NewService(fileStorage, 0)
+
+	key := testKey(".png")
+	keys := append([]string{key}, variantKeys(key)...)
+	for _, fileKey := range keys {
+		err := fileStorage.Put(fileKey, strings.NewReader("image data"), TypePNG)
+		if err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+
+	err := uploadService.Delete(key)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	for _, fileKey := range keys {
+		_, err := fileStorage.Get(fileKey)
+		if !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("Get(%s) error = %v, want ErrNotFound", fileKey, err)
+		}
+	}
+}
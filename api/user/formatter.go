@@ -1,5 +1,10 @@
 package user
 
+import (
+	"bwastartup/api/asset"
+	"bwastartup/api/upload"
+)
+
 type UserFormatter struct {
 	ID         int    `json:"id"`
 	Name       string `json:"name"`
@@ -7,6 +12,7 @@ type UserFormatter struct {
 	Email      string `json:"email"`
 	Token      string `json:"token"`
 	ImageUrl   string `json:"image_url"`
+	ImageURLs  map[string]string `json:"image_urls"`
 }
 
 func FormatUser(user User, token string) UserFormatter {
@@ -16,7 +22,8 @@ func FormatUser(user User, token string) UserFormatter {
 		Occupation: user.Occupation,
 		Email:      user.Email,
 		Token:      token,
-		ImageUrl:   user.AvatarFileName,
+		ImageUrl:   asset.URL(user.AvatarFileName),
+		ImageURLs:  asset.URLs(upload.ImageURLs(user.AvatarFileName)),
 	}
 	return formatter
 }
\ No newline at end of file
@@ -0,0 +1,136 @@
+// Command ledgercheck memeriksa ledger: setiap journal entry harus seimbang (debit = credit)
+// dan CurrentAmount setiap campaign harus sama dengan dana bersih di escrow campaign menurut ledger.
+//
+//	go run ./cmd/ledgercheck            # hanya memeriksa, exit code 1 jika ada selisih
+//	go run ./cmd/ledgercheck -backfill  # mencatat dulu transaksi lama yang belum ada di ledger
+package main
+
+import (
+	"bwastartup/api/campaign"
+	"bwastartup/api/ledger"
+	"bwastartup/api/transaction"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/joho/godotenv"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func main() {
+	backfill := flag.Bool("backfill", false, "post ledger entries for paid and refunded transactions that are not in the ledger yet")
+	flag.Parse()
+
+	godotenv.Load(".env")
+
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		dsn = "root:@tcp(127.0.0.1:3306)/bwastartup_db?charset=utf8mb4&parseTime=True&loc=Local"
+	}
+
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	if *backfill {
+		posted, err := backfillTransactions(db)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		fmt.Printf("backfill: %d transactions checked\n", posted)
+	}
+
+	ledgerService := ledger.NewService(ledger.NewRepository(db))
+	campaignRepository := campaign.NewRepository(db)
+
+	isBalanced := true
+
+	entries, err := ledgerService.FindUnbalancedEntries()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	for _, entry := range entries {
+		isBalanced = false
+		fmt.Printf("UNBALANCED entry %d (%s %s)\n", entry.ID, entry.Type, entry.Reference)
+	}
+
+	campaigns, err := campaignRepository.FindAll("asc", "", "")
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	for _, campaign := range campaigns {
+		check, err := ledgerService.CheckCampaignBalance(campaign.ID, campaign.CurrentAmount)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+
+		if !check.IsBalanced() {
+			isBalanced = false
+			fmt.Printf("MISMATCH campaign %d %q: current_amount=%d ledger=%d difference=%d\n", campaign.ID, campaign.Name, check.CurrentAmount, check.LedgerAmount, check.CurrentAmount-check.LedgerAmount)
+		}
+	}
+
+	if !isBalanced {
+		os.Exit(1)
+	}
+	fmt.Printf("ok: %d campaigns match the ledger\n", len(campaigns))
+}
+
+// backfillTransactions mencatat pembayaran, fee dan refund dari transaksi yang dibuat sebelum ledger ada.
+// Aman dijalankan berulang kali karena reference entry unik (sama dengan reference saat transaksi dibayar)
+func backfillTransactions(db *gorm.DB) (int, error) {
+	transactions, err := transaction.NewRepository(db).FindAll()
+	if err != nil {
+		return 0, err
+	}
+
+	checked := 0
+	for _, paidTransaction := range transactions {
+		if paidTransaction.Status != transaction.StatusPaid && paidTransaction.Status != transaction.StatusPartiallyRefunded && paidTransaction.Status != transaction.StatusRefunded {
+			continue
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			ledgerService := ledger.NewService(ledger.NewRepository(tx))
+
+			err := ledgerService.PostPayment(paidTransaction.ID, paidTransaction.CampaignID, paidTransaction.UserID, paidTransaction.CampaignAmount)
+			if err != nil {
+				return err
+			}
+
+			// fee yang tersimpan di transaksi sudah dalam mata uang campaign, sama seperti yang dicatat recordFees
+			err = ledgerService.PostFee(paidTransaction.ID, paidTransaction.CampaignID, paidTransaction.PlatformFee, paidTransaction.GatewayFee)
+			if err != nil {
+				return err
+			}
+
+			refunded, err := ledgerService.GetTransactionRefunded(paidTransaction.ID)
+			if err != nil {
+				return err
+			}
+
+			// transaksi refunded dari sebelum ada refunded_amount dianggap refund penuh
+			refundedAmount := paidTransaction.RefundedAmount
+			if paidTransaction.Status == transaction.StatusRefunded {
+				refundedAmount = paidTransaction.Amount
+			}
+
+			// ledger dalam mata uang campaign, refunded_amount dalam mata uang transaksi
+			campaignRefundedAmount := paidTransaction.CampaignAmountFor(refundedAmount)
+			if campaignRefundedAmount > refunded {
+				reference := "refund:backfill:" + strconv.Itoa(paidTransaction.ID) + ":" + strconv.Itoa(refundedAmount)
+				return ledgerService.PostRefund(reference, paidTransaction.ID, paidTransaction.CampaignID, paidTransaction.UserID, campaignRefundedAmount-refunded)
+			}
+			return nil
+		})
+		if err != nil {
+			return checked, err
+		}
+		checked++
+	}
+	return checked, nil
+}
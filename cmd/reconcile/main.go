@@ -0,0 +1,104 @@
+// Command reconcile mencocokkan laporan settlement payment provider dengan transaksi berdasarkan kode order
+// dan jumlah, lalu mencatat selisihnya (missing, amount berbeda, status berbeda) untuk ditinjau admin di CMS.
+//
+//	go run ./cmd/reconcile -provider midtrans -file settlement.csv
+//	go run ./cmd/reconcile -provider xendit -file report.json -from 2026-10-01 -to 2026-10-31
+//
+// File csv harus punya header dengan kolom order_id, amount dan status (nama kolom laporan Midtrans,
+// Xendit dan Stripe juga dikenali), file json berisi array object {"order_id", "amount", "status"}.
+// Jika -from dan -to diisi, transaksi paid pada rentang tersebut yang tidak ada di laporan juga ditandai.
+// Exit code 1 jika ada selisih.
+package main
+
+import (
+	"bwastartup/api/reconciliation"
+	"bwastartup/api/transaction"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func main() {
+	provider := flag.String("provider", "", "payment provider of the settlement report (midtrans, xendit, stripe)")
+	file := flag.String("file", "", "settlement report file")
+	format := flag.String("format", "", "csv or json, defaults to the file extension")
+	from := flag.String("from", "", "first transaction date (YYYY-MM-DD) covered by the report")
+	to := flag.String("to", "", "last transaction date (YYYY-MM-DD) covered by the report")
+	flag.Parse()
+
+	if *provider == "" || *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+	}
+
+	input := reconciliation.ReconcileInput{}
+	input.Provider = *provider
+	input.FileName = filepath.Base(*file)
+
+	if *from != "" || *to != "" {
+		createdFrom, err := time.ParseInLocation("2006-01-02", *from, time.Local)
+		if err != nil {
+			log.Fatal("invalid -from: " + err.Error())
+		}
+		createdTo, err := time.ParseInLocation("2006-01-02", *to, time.Local)
+		if err != nil {
+			log.Fatal("invalid -to: " + err.Error())
+		}
+		// -to ikut dihitung sampai akhir hari
+		createdTo = createdTo.AddDate(0, 0, 1)
+
+		input.CreatedFrom = &createdFrom
+		input.CreatedTo = &createdTo
+	}
+
+	reportFile, err := os.Open(*file)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	defer reportFile.Close()
+
+	input.Records, err = reconciliation.ParseSettlementFile(reportFile, *format)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	godotenv.Load(".env")
+
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		dsn = "root:@tcp(127.0.0.1:3306)/bwastartup_db?charset=utf8mb4&parseTime=True&loc=Local"
+	}
+
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	reconciliationService := reconciliation.NewService(reconciliation.NewRepository(db), transaction.NewRepository(db))
+
+	run, discrepancies, err := reconciliationService.Reconcile(input)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	for _, discrepancy := range discrepancies {
+		fmt.Printf("%s %s: %s (expected %d %s, reported %d %s)\n", strings.ToUpper(discrepancy.Type), discrepancy.OrderID, discrepancy.Detail, discrepancy.ExpectedAmount, discrepancy.ExpectedStatus, discrepancy.ReportedAmount, discrepancy.ReportedStatus)
+	}
+
+	fmt.Printf("run %d: %d records, %d orders matched, %d discrepancies\n", run.ID, run.RecordCount, run.MatchedCount, run.DiscrepancyCount)
+	if run.DiscrepancyCount > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,26 @@
+-- Kategori campaign yang dikelola admin dan tag bebas pada campaign
+
+CREATE TABLE categories (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  name VARCHAR(255) NOT NULL,
+  slug VARCHAR(255) NOT NULL,
+  icon VARCHAR(255) NOT NULL DEFAULT '',
+  description TEXT,
+  created_at DATETIME,
+  updated_at DATETIME,
+  PRIMARY KEY (id),
+  UNIQUE KEY categories_slug_unique (slug)
+);
+
+ALTER TABLE campaigns ADD COLUMN category_id INT(11) NOT NULL DEFAULT 0 AFTER slug;
+ALTER TABLE campaigns ADD INDEX campaigns_category_id_index (category_id);
+
+CREATE TABLE campaign_tags (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  campaign_id INT(11) NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  created_at DATETIME,
+  PRIMARY KEY (id),
+  UNIQUE KEY campaign_tags_campaign_id_name_unique (campaign_id, name),
+  INDEX campaign_tags_name_index (name)
+);
@@ -0,0 +1,40 @@
+-- Slug campaign harus unik, slug lama disimpan agar url lama bisa di-redirect (301)
+
+-- Slug duplikat dibuat unik dulu sebelum unique key ditambahkan: campaign paling lama tetap memakai slug aslinya,
+-- campaign berikutnya diberi akhiran -2, -3, dst sesuai urutan id (sama seperti generateSlug)
+UPDATE campaigns c
+JOIN (
+  -- DISTINCT mencegah MySQL me-merge derived table ke UPDATE (error 1093)
+  SELECT DISTINCT duplicate.id, CONCAT(duplicate.slug, '-', duplicate.position) AS new_slug
+  FROM (
+    SELECT c1.id, c1.slug, COUNT(c2.id) + 1 AS position
+    FROM campaigns c1
+    JOIN campaigns c2 ON c2.slug = c1.slug AND c2.id < c1.id
+    GROUP BY c1.id, c1.slug
+  ) duplicate
+  LEFT JOIN campaigns existing ON existing.slug = CONCAT(duplicate.slug, '-', duplicate.position)
+  WHERE existing.id IS NULL
+) renamed ON renamed.id = c.id
+SET c.slug = renamed.new_slug;
+
+-- Akhiran yang sudah dipakai campaign lain (misalnya slug aslinya memang berakhiran -2) ditambah id campaign
+UPDATE campaigns c
+JOIN (
+  SELECT c1.id, c1.slug, COUNT(c2.id) + 1 AS position
+  FROM campaigns c1
+  JOIN campaigns c2 ON c2.slug = c1.slug AND c2.id < c1.id
+  GROUP BY c1.id, c1.slug
+) duplicate ON duplicate.id = c.id
+SET c.slug = CONCAT(duplicate.slug, '-', duplicate.position, '-', c.id);
+
+ALTER TABLE campaigns ADD UNIQUE KEY campaigns_slug_unique (slug);
+
+CREATE TABLE campaign_slugs (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  campaign_id INT(11) NOT NULL,
+  slug VARCHAR(255) NOT NULL,
+  created_at DATETIME,
+  PRIMARY KEY (id),
+  UNIQUE KEY campaign_slugs_slug_unique (slug),
+  INDEX campaign_slugs_campaign_id_index (campaign_id)
+);
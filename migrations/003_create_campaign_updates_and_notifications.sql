@@ -0,0 +1,28 @@
+-- Kabar perkembangan campaign dari pembuat campaign dan notifikasi untuk user
+
+CREATE TABLE campaign_updates (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  campaign_id INT(11) NOT NULL,
+  user_id INT(11) NOT NULL,
+  title VARCHAR(255) NOT NULL,
+  body TEXT NOT NULL,
+  visibility VARCHAR(20) NOT NULL DEFAULT 'public',
+  created_at DATETIME,
+  updated_at DATETIME,
+  PRIMARY KEY (id),
+  INDEX campaign_updates_campaign_id_index (campaign_id)
+);
+
+CREATE TABLE notifications (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  user_id INT(11) NOT NULL,
+  type VARCHAR(50) NOT NULL,
+  title VARCHAR(255) NOT NULL,
+  message TEXT,
+  link VARCHAR(255) NOT NULL DEFAULT '',
+  read_at DATETIME NULL,
+  created_at DATETIME,
+  updated_at DATETIME,
+  PRIMARY KEY (id),
+  INDEX notifications_user_id_index (user_id)
+);
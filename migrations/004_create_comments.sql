@@ -0,0 +1,30 @@
+-- Komentar dan diskusi bertingkat pada campaign
+
+ALTER TABLE campaigns ADD COLUMN comments_backers_only TINYINT(1) NOT NULL DEFAULT 0 AFTER category_id;
+
+CREATE TABLE comments (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  campaign_id INT(11) NOT NULL,
+  user_id INT(11) NOT NULL,
+  parent_id INT(11) NOT NULL DEFAULT 0,
+  root_id INT(11) NOT NULL DEFAULT 0,
+  body TEXT NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'visible',
+  flag_count INT(11) NOT NULL DEFAULT 0,
+  edited_at DATETIME NULL,
+  created_at DATETIME,
+  updated_at DATETIME,
+  PRIMARY KEY (id),
+  INDEX comments_campaign_id_parent_id_index (campaign_id, parent_id),
+  INDEX comments_root_id_index (root_id)
+);
+
+CREATE TABLE comment_flags (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  comment_id INT(11) NOT NULL,
+  user_id INT(11) NOT NULL,
+  reason VARCHAR(255) NOT NULL DEFAULT '',
+  created_at DATETIME,
+  PRIMARY KEY (id),
+  UNIQUE KEY comment_flags_comment_id_user_id_unique (comment_id, user_id)
+);
@@ -0,0 +1,4 @@
+-- Urutan dan caption gambar campaign
+
+ALTER TABLE campaign_images ADD COLUMN position INT(11) NOT NULL DEFAULT 0 AFTER is_primary;
+ALTER TABLE campaign_images ADD COLUMN caption VARCHAR(255) NOT NULL DEFAULT '' AFTER position;
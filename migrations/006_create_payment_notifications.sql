@@ -0,0 +1,17 @@
+-- Log notifikasi pembayaran yang sudah diproses, supaya notifikasi yang dikirim ulang provider
+-- tidak diproses dua kali
+
+CREATE TABLE payment_notifications (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  transaction_id INT(11) NOT NULL,
+  provider VARCHAR(50) NOT NULL,
+  event_key VARCHAR(255) NOT NULL,
+  transaction_status VARCHAR(50) NOT NULL DEFAULT '',
+  status_code VARCHAR(10) NOT NULL DEFAULT '',
+  previous_status VARCHAR(20) NOT NULL DEFAULT '',
+  new_status VARCHAR(20) NOT NULL DEFAULT '',
+  created_at DATETIME,
+  PRIMARY KEY (id),
+  UNIQUE KEY payment_notifications_event_key_unique (event_key),
+  INDEX payment_notifications_transaction_id_index (transaction_id)
+);
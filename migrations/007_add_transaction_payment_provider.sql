@@ -0,0 +1,5 @@
+-- Payment provider yang dipakai setiap transaksi (midtrans, xendit, stripe, mock)
+-- dan referensi tagihan dari provider tersebut
+
+ALTER TABLE transactions ADD COLUMN payment_provider VARCHAR(20) NOT NULL DEFAULT 'midtrans' AFTER payment_url;
+ALTER TABLE transactions ADD COLUMN payment_reference VARCHAR(255) NOT NULL DEFAULT '' AFTER payment_provider;
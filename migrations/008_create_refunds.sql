@@ -0,0 +1,20 @@
+-- Refund penuh atau sebagian dari transaksi yang sudah paid.
+-- refunded_amount total dana yang sudah dikembalikan, sisa (amount - refunded_amount) masih dihitung di campaign
+
+ALTER TABLE transactions ADD COLUMN refunded_amount INT(11) NOT NULL DEFAULT 0 AFTER amount;
+
+CREATE TABLE refunds (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  transaction_id INT(11) NOT NULL,
+  amount INT(11) NOT NULL,
+  reason VARCHAR(255) NOT NULL DEFAULT '',
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  requested_by VARCHAR(20) NOT NULL DEFAULT '',
+  requested_by_user_id INT(11) NOT NULL DEFAULT 0,
+  provider_reference VARCHAR(255) NOT NULL DEFAULT '',
+  failure_reason VARCHAR(255) NOT NULL DEFAULT '',
+  created_at DATETIME,
+  updated_at DATETIME,
+  PRIMARY KEY (id),
+  INDEX refunds_transaction_id_index (transaction_id)
+);
@@ -0,0 +1,4 @@
+-- Alasan perubahan status terakhir, misalnya transaksi pending yang di-expire oleh sweeper
+
+ALTER TABLE transactions ADD COLUMN status_reason VARCHAR(255) NOT NULL DEFAULT '' AFTER status;
+ALTER TABLE transactions ADD INDEX transactions_status_created_at_index (status, created_at);
@@ -0,0 +1,7 @@
+-- Kode transaksi (misalnya BWA-2026-7KQ2XD) dipakai sebagai order ID di payment provider.
+-- Transaksi lama memakai id sebagai order ID, jadi kodenya diisi id supaya notifikasi lama tetap ditemukan
+
+UPDATE transactions SET code = CAST(id AS CHAR) WHERE code IS NULL OR code = '';
+
+ALTER TABLE transactions MODIFY code VARCHAR(30) NOT NULL;
+ALTER TABLE transactions ADD UNIQUE KEY transactions_code_unique (code);
@@ -0,0 +1,51 @@
+-- Ledger double-entry untuk semua perpindahan dana (pembayaran, refund, fee, payout).
+-- Journal entry dan line tidak boleh diubah atau dihapus, koreksi dicatat sebagai entry baru
+
+CREATE TABLE ledger_accounts (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  code VARCHAR(100) NOT NULL,
+  type VARCHAR(30) NOT NULL,
+  owner_id INT(11) NOT NULL DEFAULT 0,
+  created_at DATETIME,
+  PRIMARY KEY (id),
+  UNIQUE KEY ledger_accounts_code_unique (code),
+  INDEX ledger_accounts_type_owner_id_index (type, owner_id)
+);
+
+CREATE TABLE journal_entries (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  type VARCHAR(30) NOT NULL,
+  reference VARCHAR(255) NOT NULL,
+  transaction_id INT(11) NOT NULL DEFAULT 0,
+  campaign_id INT(11) NOT NULL DEFAULT 0,
+  description VARCHAR(255) NOT NULL DEFAULT '',
+  created_at DATETIME,
+  PRIMARY KEY (id),
+  UNIQUE KEY journal_entries_reference_unique (reference),
+  INDEX journal_entries_transaction_id_index (transaction_id),
+  INDEX journal_entries_campaign_id_index (campaign_id)
+);
+
+CREATE TABLE journal_lines (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  journal_entry_id INT(11) NOT NULL,
+  account_id INT(11) NOT NULL,
+  debit INT(11) NOT NULL DEFAULT 0,
+  credit INT(11) NOT NULL DEFAULT 0,
+  created_at DATETIME,
+  PRIMARY KEY (id),
+  INDEX journal_lines_journal_entry_id_index (journal_entry_id),
+  INDEX journal_lines_account_id_index (account_id)
+);
+
+CREATE TRIGGER journal_entries_no_update BEFORE UPDATE ON journal_entries
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'journal_entries is append-only';
+
+CREATE TRIGGER journal_entries_no_delete BEFORE DELETE ON journal_entries
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'journal_entries is append-only';
+
+CREATE TRIGGER journal_lines_no_update BEFORE UPDATE ON journal_lines
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'journal_lines is append-only';
+
+CREATE TRIGGER journal_lines_no_delete BEFORE DELETE ON journal_lines
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'journal_lines is append-only';
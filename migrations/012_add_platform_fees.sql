@@ -0,0 +1,13 @@
+-- Fee platform (persentase dalam basis point + biaya tetap) dengan override per kategori dan per campaign.
+-- NULL berarti ikut level di atasnya (campaign -> kategori -> PLATFORM_FEE_PERCENT/PLATFORM_FEE_FIXED)
+
+ALTER TABLE categories ADD COLUMN fee_basis_points INT(11) NULL AFTER description;
+ALTER TABLE categories ADD COLUMN fee_fixed INT(11) NULL AFTER fee_basis_points;
+
+ALTER TABLE campaigns ADD COLUMN fee_basis_points INT(11) NULL AFTER comments_backers_only;
+ALTER TABLE campaigns ADD COLUMN fee_fixed INT(11) NULL AFTER fee_basis_points;
+
+-- fee dan net amount diisi saat transaksi menjadi paid
+ALTER TABLE transactions ADD COLUMN platform_fee INT(11) NOT NULL DEFAULT 0 AFTER refunded_amount;
+ALTER TABLE transactions ADD COLUMN gateway_fee INT(11) NOT NULL DEFAULT 0 AFTER platform_fee;
+ALTER TABLE transactions ADD COLUMN net_amount INT(11) NOT NULL DEFAULT 0 AFTER gateway_fee;
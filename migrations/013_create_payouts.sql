@@ -0,0 +1,38 @@
+-- Payout dana campaign ke rekening creator. Rekening disalin ke payout saat diajukan
+-- supaya riwayat tetap utuh walaupun rekening dihapus
+
+CREATE TABLE bank_accounts (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  user_id INT(11) NOT NULL,
+  bank_code VARCHAR(50) NOT NULL,
+  account_number VARCHAR(50) NOT NULL,
+  account_holder_name VARCHAR(255) NOT NULL,
+  created_at DATETIME,
+  updated_at DATETIME,
+  PRIMARY KEY (id),
+  INDEX bank_accounts_user_id_index (user_id)
+);
+
+CREATE TABLE payouts (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  user_id INT(11) NOT NULL,
+  campaign_id INT(11) NOT NULL,
+  bank_account_id INT(11) NOT NULL,
+  amount INT(11) NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'requested',
+  bank_code VARCHAR(50) NOT NULL,
+  account_number VARCHAR(50) NOT NULL,
+  account_holder_name VARCHAR(255) NOT NULL,
+  provider VARCHAR(20) NOT NULL DEFAULT '',
+  provider_reference VARCHAR(255) NOT NULL DEFAULT '',
+  note VARCHAR(255) NOT NULL DEFAULT '',
+  reviewed_by_user_id INT(11) NOT NULL DEFAULT 0,
+  reviewed_at DATETIME NULL,
+  paid_at DATETIME NULL,
+  created_at DATETIME,
+  updated_at DATETIME,
+  PRIMARY KEY (id),
+  INDEX payouts_user_id_index (user_id),
+  INDEX payouts_campaign_id_status_index (campaign_id, status),
+  INDEX payouts_provider_reference_index (provider, provider_reference)
+);
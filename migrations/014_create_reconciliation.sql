@@ -0,0 +1,35 @@
+-- Reconciliation laporan settlement payment provider dengan transaksi (go run ./cmd/reconcile).
+-- Selisih tetap terbuka sampai di-resolve admin atau order tersebut cocok pada run berikutnya
+
+CREATE TABLE reconciliation_runs (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  provider VARCHAR(20) NOT NULL,
+  file_name VARCHAR(255) NOT NULL DEFAULT '',
+  record_count INT(11) NOT NULL DEFAULT 0,
+  matched_count INT(11) NOT NULL DEFAULT 0,
+  discrepancy_count INT(11) NOT NULL DEFAULT 0,
+  created_at DATETIME,
+  PRIMARY KEY (id)
+);
+
+CREATE TABLE reconciliation_discrepancies (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  run_id INT(11) NOT NULL,
+  provider VARCHAR(20) NOT NULL,
+  order_id VARCHAR(255) NOT NULL,
+  transaction_id INT(11) NOT NULL DEFAULT 0,
+  type VARCHAR(30) NOT NULL,
+  expected_amount INT(11) NOT NULL DEFAULT 0,
+  reported_amount INT(11) NOT NULL DEFAULT 0,
+  expected_status VARCHAR(20) NOT NULL DEFAULT '',
+  reported_status VARCHAR(20) NOT NULL DEFAULT '',
+  detail VARCHAR(255) NOT NULL DEFAULT '',
+  note VARCHAR(255) NOT NULL DEFAULT '',
+  resolved_by_user_id INT(11) NOT NULL DEFAULT 0,
+  resolved_at DATETIME NULL,
+  created_at DATETIME,
+  updated_at DATETIME,
+  PRIMARY KEY (id),
+  INDEX reconciliation_discrepancies_run_id_index (run_id),
+  INDEX reconciliation_discrepancies_order_index (provider, order_id, resolved_at)
+);
@@ -0,0 +1,12 @@
+-- Multi mata uang: amount tetap integer dalam minor unit mata uangnya (rupiah tanpa sen, USD dalam sen).
+-- Data lama seluruhnya rupiah sehingga cukup diisi default IDR dengan kurs 1
+
+ALTER TABLE campaigns ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR' AFTER goal_amount;
+
+-- amount dan refunded_amount dalam mata uang backer, campaign_amount hasil konversi ke mata uang campaign
+-- dengan kurs saat transaksi dibuat (exchange_rate) dan dipakai untuk total campaign, ledger dan fee
+ALTER TABLE transactions ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR' AFTER amount;
+ALTER TABLE transactions ADD COLUMN exchange_rate DECIMAL(20,10) NOT NULL DEFAULT 1 AFTER currency;
+ALTER TABLE transactions ADD COLUMN campaign_amount INT(11) NOT NULL DEFAULT 0 AFTER exchange_rate;
+
+UPDATE transactions SET campaign_amount = amount;
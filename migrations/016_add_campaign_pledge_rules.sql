@@ -0,0 +1,8 @@
+-- Aturan dukungan per campaign (nominal dalam minor unit mata uang campaign).
+-- NULL berarti ikut aturan platform (PLEDGE_MIN_AMOUNT, PLEDGE_MAX_AMOUNT, PLEDGE_INCREMENT, PLEDGE_OVERFUNDING_CAP_PERCENT)
+
+ALTER TABLE campaigns ADD COLUMN pledge_minimum_amount INT(11) NULL AFTER fee_fixed;
+ALTER TABLE campaigns ADD COLUMN pledge_maximum_amount INT(11) NULL AFTER pledge_minimum_amount;
+ALTER TABLE campaigns ADD COLUMN pledge_increment INT(11) NULL AFTER pledge_maximum_amount;
+-- batas total dana dalam persen dari goal_amount, 150 berarti maksimal 150% goal
+ALTER TABLE campaigns ADD COLUMN overfunding_cap_percent INT(11) NULL AFTER pledge_increment;
@@ -0,0 +1,11 @@
+-- Pilihan tampilan backer per transaksi (public, display_name, anonymous) beserta pesan dukungan,
+-- dan supporter wall publik yang bisa diaktifkan per campaign
+
+ALTER TABLE transactions ADD COLUMN visibility VARCHAR(20) NOT NULL DEFAULT 'public' AFTER campaign_amount;
+ALTER TABLE transactions ADD COLUMN display_name VARCHAR(50) NOT NULL DEFAULT '' AFTER visibility;
+ALTER TABLE transactions ADD COLUMN message VARCHAR(500) NOT NULL DEFAULT '' AFTER display_name;
+
+-- backer lama belum pernah memilih untuk tampil, jadi dianggap anonymous
+UPDATE transactions SET visibility = 'anonymous';
+
+ALTER TABLE campaigns ADD COLUMN supporter_wall_enabled TINYINT(1) NOT NULL DEFAULT 0 AFTER comments_backers_only;
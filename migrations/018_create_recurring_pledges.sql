@@ -0,0 +1,36 @@
+-- Recurring pledge bulanan. Tagihan pertama lewat halaman pembayaran provider sambil menyimpan metode pembayaran,
+-- tagihan berikutnya dibuat scheduler sebagai transaksi biasa dengan recurring_pledge_id dan billing_period
+
+CREATE TABLE recurring_pledges (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  campaign_id INT(11) NOT NULL,
+  user_id INT(11) NOT NULL,
+  amount INT(11) NOT NULL,
+  currency CHAR(3) NOT NULL DEFAULT '',
+  campaign_amount INT(11) NOT NULL DEFAULT 0,
+  payment_provider VARCHAR(20) NOT NULL DEFAULT '',
+  payment_token VARCHAR(255) NOT NULL DEFAULT '',
+  status VARCHAR(20) NOT NULL,
+  visibility VARCHAR(20) NOT NULL DEFAULT '',
+  display_name VARCHAR(50) NOT NULL DEFAULT '',
+  message VARCHAR(500) NOT NULL DEFAULT '',
+  billing_day INT(11) NOT NULL DEFAULT 0,
+  current_period INT(11) NOT NULL DEFAULT 0,
+  due_at DATETIME NULL,
+  next_charge_at DATETIME NULL,
+  failed_attempts INT(11) NOT NULL DEFAULT 0,
+  last_transaction_id INT(11) NOT NULL DEFAULT 0,
+  note TEXT,
+  cancelled_at DATETIME NULL,
+  created_at DATETIME,
+  updated_at DATETIME,
+  PRIMARY KEY (id),
+  INDEX recurring_pledges_user_id_index (user_id),
+  INDEX recurring_pledges_campaign_status_index (campaign_id, status),
+  INDEX recurring_pledges_due_index (status, next_charge_at)
+);
+
+ALTER TABLE transactions ADD COLUMN recurring_pledge_id INT(11) NOT NULL DEFAULT 0 AFTER message;
+ALTER TABLE transactions ADD COLUMN billing_period INT(11) NOT NULL DEFAULT 0 AFTER recurring_pledge_id;
+ALTER TABLE transactions ADD COLUMN payment_token VARCHAR(255) NOT NULL DEFAULT '' AFTER billing_period;
+CREATE INDEX transactions_recurring_period_index ON transactions (recurring_pledge_id, billing_period);
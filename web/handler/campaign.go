@@ -2,8 +2,14 @@ package handler
 
 import (
 	"bwastartup/api/campaign"
+	"bwastartup/api/category"
+	"bwastartup/api/fee"
+	"bwastartup/api/money"
+	"bwastartup/api/upload"
 	"bwastartup/api/user"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 
@@ -13,14 +19,16 @@ import (
 type campaignHandler struct {
 	campaignService campaign.Service
 	userService     user.Service
+	categoryService category.Service
+	uploadService   upload.Service
 }
 
-func NewCampaignHandler(campaignService campaign.Service,userService user.Service) *campaignHandler{
-	return &campaignHandler{campaignService, userService}
+func NewCampaignHandler(campaignService campaign.Service,userService user.Service, categoryService category.Service, uploadService upload.Service) *campaignHandler{
+	return &campaignHandler{campaignService, userService, categoryService, uploadService}
 }
 
 func (h *campaignHandler) Index(c *gin.Context){
-	campaigns, err := h.campaignService.GetCampaigns(0, "asc", "")
+	campaigns, err := h.campaignService.GetCampaigns(0, "asc", "", "")
 
 	if err!= nil {
 		c.HTML(http.StatusInternalServerError, "error.html", nil)
@@ -37,8 +45,17 @@ func (h *campaignHandler) New(c *gin.Context){
 		return
 	}
 
+	categories, err := h.categoryService.GetCategories()
+	if err!= nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
 	input := campaign.FormCreateCampaignInput{}
 	input.Users = users
+	input.Categories = categories
+	input.Currencies = money.SupportedCurrencies()
+	input.Currency = money.DefaultCurrency
 
 	c.HTML(http.StatusOK, "campaign_new.html", input)
 }
@@ -53,7 +70,14 @@ func(h *campaignHandler) Create(c *gin.Context){
 			c.HTML(http.StatusInternalServerError, "error.html", nil)
 			return
 		}
+		categories, e := h.categoryService.GetCategories()
+		if e != nil {
+			c.HTML(http.StatusInternalServerError, "error.html", nil)
+			return
+		}
 		input.Users = users
+		input.Categories = categories
+		input.Currencies = money.SupportedCurrencies()
 		input.Error = err
 
 		c.HTML(http.StatusOK, "campaign_new.html", input)
@@ -71,7 +95,12 @@ func(h *campaignHandler) Create(c *gin.Context){
 	createCampaignInput.ShortDescription = input.ShortDescription
 	createCampaignInput.Description = input.Description
 	createCampaignInput.GoalAmount = input.GoalAmount
+	createCampaignInput.Currency = input.Currency
 	createCampaignInput.Perks = input.Perks
+	createCampaignInput.CategoryID = &input.CategoryID
+	createCampaignInput.Tags = &input.Tags
+	createCampaignInput.CommentsBackersOnly = &input.CommentsBackersOnly
+	createCampaignInput.SupporterWallEnabled = &input.SupporterWallEnabled
 	createCampaignInput.User = user
 
 	_, err = h.campaignService.CreateCampaign(createCampaignInput)
@@ -102,34 +131,51 @@ func (h *campaignHandler) CreateImage(c *gin.Context) {
 	id, _ := strconv.Atoi(idParam)
 
 	existingCampaign, err := h.campaignService.GetCampaignByID(campaign.GetCampaignDetailInput{ID: id})
-	if err != nil {
+	if err != nil || existingCampaign.ID == 0 {
 		c.HTML(http.StatusInternalServerError, "error.html", nil)
 		return
 	}
 
 	userID := existingCampaign.UserID
 
-	path := fmt.Sprintf("images/%d-%s", userID, file.Filename)
-	err = c.SaveUploadedFile(file, path)
+	userCampaign, err := h.userService.GetUserByID(userID)
 	if err != nil {
 		c.HTML(http.StatusInternalServerError, "error.html", nil)
 		return
 	}
 
-	createCampaignImageInput := campaign.CreateCampaignImageInput{}
-	createCampaignImageInput.CampaignID = id
-	createCampaignImageInput.IsPrimary = true
-
-	userCampaign, err := h.userService.GetUserByID(userID)
+	path, err := h.uploadService.SaveImage(file, fmt.Sprintf("images/campaigns/%d", id))
 	if err != nil {
+		var validationError *upload.ValidationError
+		if errors.As(err, &validationError) {
+			c.HTML(http.StatusOK, "campaign_image.html", gin.H{"ID": id, "Error": validationError.Message})
+			return
+		}
 		c.HTML(http.StatusInternalServerError, "error.html", nil)
 		return
 	}
 
+	createCampaignImageInput := campaign.CreateCampaignImageInput{}
+	createCampaignImageInput.CampaignID = id
+	createCampaignImageInput.IsPrimary = true
 	createCampaignImageInput.User = userCampaign
 
 	_, err = h.campaignService.SaveCampaignImage(createCampaignImageInput, path)
 	if err != nil {
+		// file yang terlanjur disimpan dihapus, kecuali dipakai gambar campaign yang sudah ada (nama file = hash isi file)
+		isUsed := false
+		for _, image := range existingCampaign.CampaignImages {
+			if image.FileName == path {
+				isUsed = true
+			}
+		}
+		if !isUsed {
+			err = h.uploadService.Delete(path)
+			if err != nil {
+				log.Printf("failed to remove campaign image file %s: %s", path, err.Error())
+			}
+		}
+
 		c.HTML(http.StatusInternalServerError, "error.html", nil)
 		return
 	}
@@ -153,7 +199,26 @@ func (h *campaignHandler) Edit(c *gin.Context){
 	input.ShortDescription = existingCampaign.ShortDescription
 	input.Description = existingCampaign.Description
 	input.GoalAmount = existingCampaign.GoalAmount
+	input.Currency = money.NormalizeCurrency(existingCampaign.Currency)
 	input.Perks = existingCampaign.Perks
+	input.CategoryID = existingCampaign.CategoryID
+	input.Tags = existingCampaign.TagsString()
+	input.CommentsBackersOnly = existingCampaign.CommentsBackersOnly
+	input.SupporterWallEnabled = existingCampaign.SupporterWallEnabled
+	input.FeePercent = fee.FormatPercent(existingCampaign.FeeBasisPoints)
+	input.FeeFixed = fee.FormatAmount(existingCampaign.FeeFixed)
+	input.PledgeMinimumAmount = campaign.FormatPledgeRule(existingCampaign.PledgeMinimumAmount)
+	input.PledgeMaximumAmount = campaign.FormatPledgeRule(existingCampaign.PledgeMaximumAmount)
+	input.PledgeIncrement = campaign.FormatPledgeRule(existingCampaign.PledgeIncrement)
+	input.OverfundingCapPercent = campaign.FormatPledgeRule(existingCampaign.OverfundingCapPercent)
+
+	categories, err := h.categoryService.GetCategories()
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+	input.Categories = categories
+	input.Currencies = money.SupportedCurrencies()
 
 	c.HTML(http.StatusOK, "campaign_edit.html", input)
 }
@@ -191,15 +256,64 @@ func (h *campaignHandler) Update(c *gin.Context) {
 	updateInput.ShortDescription = input.ShortDescription
 	updateInput.Description = input.Description
 	updateInput.GoalAmount = input.GoalAmount
+	updateInput.Currency = input.Currency
 	updateInput.Perks = input.Perks
+	updateInput.CategoryID = &input.CategoryID
+	updateInput.Tags = &input.Tags
+	updateInput.CommentsBackersOnly = &input.CommentsBackersOnly
+	updateInput.SupporterWallEnabled = &input.SupporterWallEnabled
 	updateInput.User = userCampaign
 
+	pledgeRules := campaign.PledgeRulesInput{}
+	pledgeRules.MinimumAmount, err = campaign.ParsePledgeRule(input.PledgeMinimumAmount)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	pledgeRules.MaximumAmount, err = campaign.ParsePledgeRule(input.PledgeMaximumAmount)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	pledgeRules.Increment, err = campaign.ParsePledgeRule(input.PledgeIncrement)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	pledgeRules.OverfundingCapPercent, err = campaign.ParsePledgeRule(input.OverfundingCapPercent)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+	updateInput.PledgeRules = &pledgeRules
+
+	feeBasisPoints, err := fee.ParsePercent(input.FeePercent)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	feeFixed, err := fee.ParseAmount(input.FeeFixed)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
 	_, err = h.campaignService.UpdateCampaign(campaign.GetCampaignDetailInput{ID: id}, updateInput)
 	if err != nil {
 		c.HTML(http.StatusInternalServerError, "error.html", nil)
 		return
 	}
 
+	_, err = h.campaignService.UpdateCampaignFee(campaign.GetCampaignDetailInput{ID: id}, feeBasisPoints, feeFixed)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
 	c.Redirect(http.StatusFound, "/campaigns")
 }
 
@@ -214,4 +328,132 @@ func (h *campaignHandler) Show(c *gin.Context){
 
 	c.HTML(http.StatusOK, "campaign_show.html", existingCampaign)
 
-}
\ No newline at end of file
+}
+
+// campaignOwner mengambil campaign dan user pemiliknya, aksi gambar dari CMS dijalankan
+// atas nama pemilik campaign supaya tetap melewati pengecekan owner di service
+func (h *campaignHandler) campaignOwner(campaignID int) (campaign.Campaign, user.User, error) {
+	existingCampaign, err := h.campaignService.GetCampaignByID(campaign.GetCampaignDetailInput{ID: campaignID})
+	if err != nil {
+		return existingCampaign, user.User{}, err
+	}
+
+	userCampaign, err := h.userService.GetUserByID(existingCampaign.UserID)
+	if err != nil {
+		return existingCampaign, userCampaign, err
+	}
+	return existingCampaign, userCampaign, nil
+}
+
+func (h *campaignHandler) SetPrimaryImage(c *gin.Context) {
+	idParam := c.Param("id")
+	id, _ := strconv.Atoi(idParam)
+	imageID, _ := strconv.Atoi(c.PostForm("image_id"))
+
+	_, userCampaign, err := h.campaignOwner(id)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	isPrimary := true
+	input := campaign.UpdateCampaignImageInput{IsPrimary: &isPrimary, User: userCampaign}
+
+	_, err = h.campaignService.UpdateCampaignImage(campaign.GetCampaignImageInput{ID: imageID}, input)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	c.Redirect(http.StatusFound, fmt.Sprintf("/campaigns/show/%d", id))
+}
+
+func (h *campaignHandler) UpdateImageCaption(c *gin.Context) {
+	idParam := c.Param("id")
+	id, _ := strconv.Atoi(idParam)
+	imageID, _ := strconv.Atoi(c.PostForm("image_id"))
+	caption := c.PostForm("caption")
+
+	_, userCampaign, err := h.campaignOwner(id)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	input := campaign.UpdateCampaignImageInput{Caption: &caption, User: userCampaign}
+
+	_, err = h.campaignService.UpdateCampaignImage(campaign.GetCampaignImageInput{ID: imageID}, input)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	c.Redirect(http.StatusFound, fmt.Sprintf("/campaigns/show/%d", id))
+}
+
+// MoveImage menukar posisi gambar dengan gambar sebelum (up) atau sesudahnya (down)
+func (h *campaignHandler) MoveImage(c *gin.Context) {
+	idParam := c.Param("id")
+	id, _ := strconv.Atoi(idParam)
+	imageID, _ := strconv.Atoi(c.PostForm("image_id"))
+	direction := c.PostForm("direction")
+
+	existingCampaign, userCampaign, err := h.campaignOwner(id)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	imageIDs := []int{}
+	for _, image := range existingCampaign.CampaignImages {
+		imageIDs = append(imageIDs, image.ID)
+	}
+
+	for i, currentID := range imageIDs {
+		if currentID != imageID {
+			continue
+		}
+		if direction == "up" && i > 0 {
+			imageIDs[i], imageIDs[i-1] = imageIDs[i-1], imageIDs[i]
+		}
+		if direction == "down" && i < len(imageIDs)-1 {
+			imageIDs[i], imageIDs[i+1] = imageIDs[i+1], imageIDs[i]
+		}
+		break
+	}
+
+	input := campaign.ReorderCampaignImagesInput{ImageIDs: imageIDs, User: userCampaign}
+
+	_, err = h.campaignService.ReorderCampaignImages(campaign.GetCampaignDetailInput{ID: id}, input)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	c.Redirect(http.StatusFound, fmt.Sprintf("/campaigns/show/%d", id))
+}
+
+func (h *campaignHandler) DeleteImage(c *gin.Context) {
+	idParam := c.Param("id")
+	id, _ := strconv.Atoi(idParam)
+	imageID, _ := strconv.Atoi(c.PostForm("image_id"))
+
+	_, userCampaign, err := h.campaignOwner(id)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	deletedImage, err := h.campaignService.DeleteCampaignImage(campaign.GetCampaignImageInput{ID: imageID, User: userCampaign})
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	err = h.uploadService.Delete(deletedImage.FileName)
+	if err != nil {
+		log.Printf("failed to remove campaign image file %s: %s", deletedImage.FileName, err.Error())
+	}
+
+	c.Redirect(http.StatusFound, fmt.Sprintf("/campaigns/show/%d", id))
+}
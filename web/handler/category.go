@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"bwastartup/api/category"
+	"bwastartup/api/fee"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type categoryHandler struct {
+	categoryService category.Service
+}
+
+func NewCategoryHandler(categoryService category.Service) *categoryHandler {
+	return &categoryHandler{categoryService}
+}
+
+func (h *categoryHandler) Index(c *gin.Context) {
+	categories, err := h.categoryService.GetCategories()
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	c.HTML(http.StatusOK, "category_index.html", gin.H{"categories": categories})
+}
+
+func (h *categoryHandler) New(c *gin.Context) {
+	c.HTML(http.StatusOK, "category_new.html", category.FormCreateCategoryInput{})
+}
+
+func (h *categoryHandler) Create(c *gin.Context) {
+	var input category.FormCreateCategoryInput
+
+	err := c.ShouldBind(&input)
+	if err != nil {
+		input.Error = err
+		c.HTML(http.StatusOK, "category_new.html", input)
+		return
+	}
+
+	_, err = h.categoryService.CreateCategory(input)
+	if err != nil {
+		input.Error = err
+		c.HTML(http.StatusOK, "category_new.html", input)
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/categories")
+}
+
+func (h *categoryHandler) Edit(c *gin.Context) {
+	idParam := c.Param("id")
+	id, _ := strconv.Atoi(idParam)
+
+	existingCategory, err := h.categoryService.GetCategoryByID(id)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	input := category.FormUpdateCategoryInput{}
+	input.ID = existingCategory.ID
+	input.Name = existingCategory.Name
+	input.Icon = existingCategory.Icon
+	input.Description = existingCategory.Description
+	input.FeePercent = fee.FormatPercent(existingCategory.FeeBasisPoints)
+	input.FeeFixed = fee.FormatAmount(existingCategory.FeeFixed)
+
+	c.HTML(http.StatusOK, "category_edit.html", input)
+}
+
+func (h *categoryHandler) Update(c *gin.Context) {
+	idParam := c.Param("id")
+	id, _ := strconv.Atoi(idParam)
+
+	var input category.FormUpdateCategoryInput
+
+	err := c.ShouldBind(&input)
+	input.ID = id
+	if err != nil {
+		input.Error = err
+		c.HTML(http.StatusOK, "category_edit.html", input)
+		return
+	}
+
+	input.FeeBasisPoints, err = fee.ParsePercent(input.FeePercent)
+	if err == nil {
+		input.FeeFixedAmount, err = fee.ParseAmount(input.FeeFixed)
+	}
+	if err != nil {
+		input.Error = err
+		c.HTML(http.StatusOK, "category_edit.html", input)
+		return
+	}
+
+	_, err = h.categoryService.UpdateCategory(input)
+	if err != nil {
+		input.Error = err
+		c.HTML(http.StatusOK, "category_edit.html", input)
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/categories")
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"bwastartup/api/comment"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type commentHandler struct {
+	commentService comment.Service
+}
+
+func NewCommentHandler(commentService comment.Service) *commentHandler {
+	return &commentHandler{commentService}
+}
+
+func (h *commentHandler) Index(c *gin.Context) {
+	comments, err := h.commentService.GetCommentsForModeration()
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	c.HTML(http.StatusOK, "comment_index.html", gin.H{"comments": comments})
+}
+
+func (h *commentHandler) Hide(c *gin.Context) {
+	idParam := c.Param("id")
+	id, _ := strconv.Atoi(idParam)
+
+	err := h.commentService.SetCommentStatus(id, comment.StatusHidden)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/comments")
+}
+
+func (h *commentHandler) Show(c *gin.Context) {
+	idParam := c.Param("id")
+	id, _ := strconv.Atoi(idParam)
+
+	err := h.commentService.SetCommentStatus(id, comment.StatusVisible)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/comments")
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"bwastartup/api/payout"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type payoutHandler struct {
+	payoutService payout.Service
+}
+
+func NewPayoutHandler(payoutService payout.Service) *payoutHandler {
+	return &payoutHandler{payoutService}
+}
+
+// Index antrian payout, yang menunggu persetujuan tampil paling atas
+func (h *payoutHandler) Index(c *gin.Context) {
+	h.renderIndex(c, http.StatusOK, "")
+}
+
+func (h *payoutHandler) Approve(c *gin.Context) {
+	idParam := c.Param("id")
+	id, _ := strconv.Atoi(idParam)
+
+	userID, ok := sessionUserID(c)
+	if !ok {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	_, err := h.payoutService.ApprovePayout(id, userID)
+	if err != nil {
+		h.renderIndex(c, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/payouts")
+}
+
+func (h *payoutHandler) Reject(c *gin.Context) {
+	idParam := c.Param("id")
+	id, _ := strconv.Atoi(idParam)
+
+	reason := c.PostForm("reason")
+	if reason == "" {
+		h.renderIndex(c, http.StatusUnprocessableEntity, "Reason is required")
+		return
+	}
+
+	userID, ok := sessionUserID(c)
+	if !ok {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	_, err := h.payoutService.RejectPayout(id, userID, reason)
+	if err != nil {
+		h.renderIndex(c, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/payouts")
+}
+
+func (h *payoutHandler) renderIndex(c *gin.Context, status int, errorMessage string) {
+	payouts, err := h.payoutService.GetAllPayouts()
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	c.HTML(status, "payout_index.html", gin.H{"payouts": payouts, "Error": errorMessage})
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"bwastartup/api/reconciliation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type reconciliationHandler struct {
+	reconciliationService reconciliation.Service
+}
+
+func NewReconciliationHandler(reconciliationService reconciliation.Service) *reconciliationHandler {
+	return &reconciliationHandler{reconciliationService}
+}
+
+// Index daftar selisih reconciliation yang masih terbuka, ?all=1 ikut menampilkan yang sudah di-resolve
+func (h *reconciliationHandler) Index(c *gin.Context) {
+	h.renderIndex(c, http.StatusOK, "")
+}
+
+func (h *reconciliationHandler) Resolve(c *gin.Context) {
+	idParam := c.Param("id")
+	id, _ := strconv.Atoi(idParam)
+
+	var input reconciliation.ResolveDiscrepancyInput
+
+	err := c.ShouldBind(&input)
+	if err != nil {
+		h.renderIndex(c, http.StatusUnprocessableEntity, "Note is required")
+		return
+	}
+
+	userID, ok := sessionUserID(c)
+	if !ok {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	err = h.reconciliationService.ResolveDiscrepancy(id, userID, input)
+	if err != nil {
+		h.renderIndex(c, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/reconciliation")
+}
+
+func (h *reconciliationHandler) renderIndex(c *gin.Context, status int, errorMessage string) {
+	includeResolved := c.Query("all") == "1"
+
+	discrepancies, err := h.reconciliationService.GetDiscrepancies(includeResolved)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	runs, err := h.reconciliationService.GetRuns()
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	c.HTML(status, "reconciliation_index.html", gin.H{"discrepancies": discrepancies, "runs": runs, "includeResolved": includeResolved, "Error": errorMessage})
+}
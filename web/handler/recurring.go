@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"bwastartup/api/recurring"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type recurringHandler struct {
+	recurringService recurring.Service
+}
+
+func NewRecurringHandler(recurringService recurring.Service) *recurringHandler {
+	return &recurringHandler{recurringService}
+}
+
+// Index monthly recurring revenue semua campaign
+func (h *recurringHandler) Index(c *gin.Context) {
+	revenues, err := h.recurringService.GetRevenue(0)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	c.HTML(http.StatusOK, "recurring_index.html", recurring.FormatRevenues(revenues))
+}
@@ -48,4 +48,14 @@ func (h *sessionHandler) Destroy(c *gin.Context){
 	session.Clear()
 	session.Save()
 	c.Redirect(http.StatusFound, "/login")
-}
\ No newline at end of file
+}
+
+// sessionUserID ID admin yang login, false jika session tidak berisi user ID yang valid
+func sessionUserID(c *gin.Context) (int, bool) {
+	session := sessions.Default(c)
+	userID, ok := session.Get("userID").(int)
+	if !ok || userID == 0 {
+		return 0, false
+	}
+	return userID, true
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"bwastartup/api/transaction"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -22,4 +23,67 @@ func (h *transactionHandler) Index(c *gin.Context){
 		return
 	}
 	c.HTML(http.StatusOK, "transaction_index.html", gin.H{"transactions": transactions})
-}
\ No newline at end of file
+}
+
+func (h *transactionHandler) NewRefund(c *gin.Context){
+	idParam := c.Param("id")
+	id, _ := strconv.Atoi(idParam)
+
+	h.renderRefund(c, id, http.StatusOK, "")
+}
+
+func (h *transactionHandler) CreateRefund(c *gin.Context){
+	idParam := c.Param("id")
+	id, _ := strconv.Atoi(idParam)
+
+	var input transaction.RefundTransactionInput
+
+	err := c.ShouldBind(&input)
+	if err != nil {
+		h.renderRefund(c, id, http.StatusUnprocessableEntity, "Reason is required")
+		return
+	}
+
+	userID, ok := sessionUserID(c)
+	if !ok {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	input.User.ID = userID
+	input.RequestedBy = transaction.RefundRequestedByAdmin
+
+	_, err = h.transactionService.RefundTransaction(transaction.GetTransactionDetailInput{ID: id}, input)
+	if err != nil {
+		h.renderRefund(c, id, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/transactions/refund/"+idParam)
+}
+
+func (h *transactionHandler) renderRefund(c *gin.Context, id int, status int, errorMessage string){
+	existingTransaction, err := h.transactionService.GetTransactionByID(id)
+	if err != nil{
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	refunds, err := h.transactionService.GetRefunds(id)
+	if err != nil{
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	c.HTML(status, "transaction_refund.html", gin.H{"Transaction": existingTransaction, "Refunds": refunds, "Error": errorMessage})
+}
+
+func (h *transactionHandler) FeeReport(c *gin.Context){
+	reports, err := h.transactionService.GetFeeReport(0)
+	if err != nil{
+		c.HTML(http.StatusInternalServerError, "error.html", nil)
+		return
+	}
+
+	c.HTML(http.StatusOK, "fee_report.html", transaction.FormatFeeReports(reports))
+}
@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"bwastartup/api/upload"
 	"bwastartup/api/user"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -10,11 +12,12 @@ import (
 )
 
 type userHandler struct {
-	userService user.Service
+	userService   user.Service
+	uploadService upload.Service
 }
 
-func NewUserHandler(userService user.Service) *userHandler {
-	return &userHandler{userService}
+func NewUserHandler(userService user.Service, uploadService upload.Service) *userHandler {
+	return &userHandler{userService, uploadService}
 }
 
 func (h *userHandler) Index(c *gin.Context){
@@ -118,10 +121,13 @@ func (h *userHandler) CreateAvatar(c *gin.Context) {
 
 	userID := id
 	
-	path := fmt.Sprintf("images/%d-%s", userID, file.Filename)
-
-	err = c.SaveUploadedFile(file, path)
+	path, err := h.uploadService.SaveImage(file, fmt.Sprintf("images/avatars/%d", userID))
 	if err!= nil {
+		var validationError *upload.ValidationError
+		if errors.As(err, &validationError) {
+			c.HTML(http.StatusOK, "user_avatar.html", gin.H{"ID": id, "Error": validationError.Message})
+			return
+		}
     c.HTML(http.StatusInternalServerError, "error.html", nil)
     return
   }